@@ -4,6 +4,6 @@ This module contains adapters utilities needed to construct the necessary class.
 """
 
 from .base import Adapter
-from .registry import get_adapter, register, list_adapters
+from .registry import get_adapter, register, list_adapters, supported_envs, has_adapter
 
-__all__ = ["Adapter", "get_adapter", "register", "list_adapters"]
\ No newline at end of file
+__all__ = ["Adapter", "get_adapter", "register", "list_adapters", "supported_envs", "has_adapter"]
\ No newline at end of file
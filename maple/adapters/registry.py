@@ -42,6 +42,31 @@ def get_adapter(policy: str, env: str) -> Adapter:
     
     return _IdentityAdapter(policy, env)
     
+def supported_envs(policy: str) -> List[str]:
+    """
+    List the environments a policy has a registered adapter for.
+
+    :param policy: policy name, with or without a version
+    :return: Environment names, in registration order
+    """
+    base = policy.split(":")[0]
+    envs = []
+    for key in ADAPTERS:
+        key_policy, env = key.rsplit(":", 1)
+        if key_policy in (policy, base) and env not in envs:
+            envs.append(env)
+    return envs
+
+def has_adapter(policy: str, env: str) -> bool:
+    """
+    Check whether a dedicated adapter exists for a policy and environment.
+
+    :param policy: policy name, with or without a version
+    :param env: environment name
+    :return: True if get_adapter would not fall back to the identity adapter
+    """
+    return env in supported_envs(policy)
+
 def register(policy: str, env: str, cls: Type[Adapter]) -> None:
     """Register an adapter class at runtime.
     
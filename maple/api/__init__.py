@@ -0,0 +1,7 @@
+"""
+Python client for the MAPLE daemon HTTP API
+"""
+
+from .client import Client, DaemonError, DaemonNotRunning, StreamInterrupted, PullInterrupted
+
+__all__ = ["Client", "DaemonError", "DaemonNotRunning", "StreamInterrupted", "PullInterrupted"]
@@ -0,0 +1,412 @@
+"""
+HTTP client for the MAPLE daemon.
+
+This module wraps the daemon's REST API in a Client class so CLI commands
+and third-party programs share one implementation of URLs, payloads and
+error handling instead of building requests by hand.
+
+Every method returns the decoded JSON response. Failures are raised as:
+- DaemonNotRunning: the daemon could not be reached
+- DaemonError: the daemon answered with an error status
+- StreamInterrupted: a create or push progress stream dropped
+- PullInterrupted: a pull's progress stream dropped; safe to retry
+
+Connecting gives up after a short timeout (MAPLE_CONNECT_TIMEOUT, set by
+the global --connect-timeout flag) so a stopped daemon is reported quickly; GET
+requests are retried a few times first in case the daemon is starting.
+Read timeouts raise requests.exceptions.Timeout unchanged so callers can
+report the limit that was hit.
+
+Example:
+    client = Client.from_config()
+    for policy in client.ps()["policies"]:
+        print(policy["policy_id"])
+"""
+
+import os
+import json
+import time
+import requests
+from typing import Any, Callable, Dict, Iterator, Optional, Type
+
+from maple.utils.config import get_config
+from maple.utils.misc import daemon_url, parse_error_response
+
+# Environment variable carrying --connect-timeout to the client
+CONNECT_TIMEOUT_ENV = "MAPLE_CONNECT_TIMEOUT"
+# Seconds to wait for the daemon to accept a connection
+DEFAULT_CONNECT_TIMEOUT = 3.0
+# Extra attempts for GET requests that could not connect, and the pause between them
+CONNECT_RETRIES = 2
+RETRY_DELAY = 0.5
+
+class DaemonNotRunning(Exception):
+    """Raised when the daemon cannot be reached."""
+
+class DaemonError(Exception):
+    """Raised when the daemon answers with an error status."""
+
+    def __init__(self, message: str, status_code: Optional[int] = None):
+        """
+        :param message: Error detail returned by the daemon.
+        :param status_code: HTTP status code of the response.
+        """
+        super().__init__(message)
+        self.status_code = status_code
+
+class StreamInterrupted(DaemonError):
+    """
+    Raised when a progress stream ends before its final event.
+
+    The outcome is unknown: the daemon may still finish the request.
+    """
+    retryable = False
+
+class PullInterrupted(StreamInterrupted):
+    """
+    Raised when a pull's progress stream ends before its final event.
+
+    The daemon keeps the pull running, so sending the same pull again
+    re-attaches to it instead of starting over.
+    """
+    retryable = True
+
+class Client:
+    """
+    Client for one MAPLE daemon.
+    """
+
+    def __init__(self, base_url: str, connect_timeout: Optional[float] = None):
+        """
+        Initialize the client.
+
+        :param base_url: Daemon base URL (e.g., 'http://localhost:8000').
+        :param connect_timeout: Seconds to wait for a connection. Defaults to
+                                MAPLE_CONNECT_TIMEOUT or 3 seconds.
+        """
+        self.base_url = base_url.rstrip("/")
+        if connect_timeout is None:
+            connect_timeout = float(os.environ.get(CONNECT_TIMEOUT_ENV) or DEFAULT_CONNECT_TIMEOUT)
+        self.connect_timeout = connect_timeout
+
+    @classmethod
+    def from_config(cls, port: Optional[int] = None, host: Optional[str] = None) -> "Client":
+        """
+        Create a client for the local daemon, or for one on another machine.
+
+        :param port: Daemon port. Defaults to the configured daemon port.
+        :param host: Host name or address of a remote daemon. Defaults to
+                     the local daemon.
+        :return: Client instance.
+        """
+        port = port or get_config().daemon.port
+        return cls(f"http://{host}:{port}" if host else daemon_url(port))
+
+    def _request(self, method: str, path: str, **kwargs) -> requests.Response:
+        """
+        Send a request and check its status.
+
+        :param method: 'get' or 'post'.
+        :param path: Endpoint path starting with '/'.
+        :param kwargs: Extra arguments for requests (params, json, timeout, stream).
+                       timeout limits reading the response; connecting is
+                       limited by the client's connect timeout.
+        :return: Response with status 200.
+        :raises DaemonNotRunning: If the daemon cannot be reached.
+        :raises DaemonError: If the daemon returns a non-200 status.
+        """
+        send = requests.get if method == "get" else requests.post
+        kwargs["timeout"] = (self.connect_timeout, kwargs.get("timeout"))
+        # A failed POST may have reached the daemon, so only GETs are retried
+        attempts = 1 + (CONNECT_RETRIES if method == "get" else 0)
+        for attempt in range(attempts):
+            try:
+                r = send(f"{self.base_url}{path}", **kwargs)
+                break
+            except requests.exceptions.ConnectionError as e:
+                if attempt + 1 == attempts:
+                    raise self._not_running(e) from e
+                time.sleep(RETRY_DELAY)
+
+        if r.status_code != 200:
+            raise DaemonError(parse_error_response(r), status_code=r.status_code)
+        return r
+
+    def _not_running(self, error: requests.exceptions.ConnectionError) -> DaemonNotRunning:
+        """
+        Describe a failed connection with a hint on how to fix it.
+
+        :param error: Error raised by requests.
+        :return: Exception to raise.
+        """
+        if isinstance(error, requests.exceptions.ConnectTimeout):
+            return DaemonNotRunning(
+                f"MAPLE daemon at {self.base_url} did not answer within {self.connect_timeout:g}s. "
+                "Is it running? Start it with 'maple serve', or raise --connect-timeout on slow networks."
+            )
+        return DaemonNotRunning(f"MAPLE daemon is not running at {self.base_url}. Start it with 'maple serve'.")
+
+    def _get(self, path: str, **kwargs) -> Dict[str, Any]:
+        """GET an endpoint and decode its JSON response."""
+        return self._request("get", path, **kwargs).json()
+
+    def _post(self, path: str, **kwargs) -> Dict[str, Any]:
+        """POST to an endpoint and decode its JSON response."""
+        return self._request("post", path, **kwargs).json()
+
+    def status(self, timeout: Optional[float] = None) -> Dict[str, Any]:
+        """
+        Get daemon status, including pulled and serving resources.
+
+        :param timeout: Optional request timeout in seconds.
+        :return: Status dictionary.
+        """
+        return self._get("/status", timeout=timeout)
+
+    def version(self) -> Optional[str]:
+        """
+        Get the MAPLE version the daemon is running.
+
+        :return: Version string, or None for daemons that predate reporting it.
+        """
+        return self.status(timeout=5).get("version")
+
+    def list_policies(self, limit: Optional[int] = None, offset: int = 0, group: bool = False) -> Dict[str, Any]:
+        """
+        List pulled policies.
+
+        :param limit: Maximum number of policies to return, or None for all.
+        :param offset: Number of policies to skip when limit is set.
+        :param group: Group versions by name, with each name's deduplicated size.
+        :return: Dictionary with 'policies' (or 'groups' when grouped) and 'total'.
+        """
+        params = {}
+        if limit is not None:
+            params = {"limit": limit, "offset": offset}
+        if group:
+            params["group"] = "true"
+        return self._get("/policy/list", params=params)
+
+    def list_envs(self) -> Dict[str, Any]:
+        """
+        List pulled environments.
+
+        :return: Dictionary with 'envs'.
+        """
+        return self._get("/env/list")
+
+    def policy_info(self, policy_id: str) -> Dict[str, Any]:
+        """
+        Get metadata of a serving policy.
+
+        :param policy_id: Policy ID (e.g., 'openvla-7b-a1b2c3d4').
+        :return: Policy metadata.
+        """
+        return self._get(f"/policy/info/{policy_id}")
+
+    def ps(self) -> Dict[str, Any]:
+        """
+        List loaded policies and running environments.
+
+        :return: Dictionary with 'policies' and 'envs'.
+        """
+        return self._get("/ps", timeout=5)
+
+    def pull(
+        self,
+        spec: str,
+        progress: Optional[Callable[[Dict[str, Any]], None]] = None,
+        **options: Any,
+    ) -> Dict[str, Any]:
+        """
+        Pull a policy.
+
+        :param spec: Policy specification (e.g., 'openvla:7b').
+        :param progress: Optional callback receiving each 'downloading' event.
+                        When given, progress is streamed as it happens.
+        :param options: Extra request fields (hf_token, concurrency, max_retries, dry_run,
+                        registry, insecure, ca_cert, proxy).
+        :return: The final 'success' event, or the dry-run plan.
+        :raises PullInterrupted: If the stream drops before the pull finishes.
+        :raises DaemonError: If the pull fails.
+        """
+        payload = {"spec": spec, **options}
+        if progress is None or payload.get("dry_run"):
+            return self._post("/policy/pull", json=payload)
+
+        payload["stream"] = True
+        for event in self.pull_events(payload):
+            if event.get("status") == "error":
+                raise DaemonError(event.get("error", "Pull failed"))
+            if event.get("status") == "success":
+                return event
+            progress(event)
+        raise PullInterrupted("Pull stream ended without a result")
+
+    def pull_events(self, payload: Dict[str, Any]) -> Iterator[Dict[str, Any]]:
+        """
+        Stream the NDJSON events of a pull as they arrive.
+
+        Events are decoded one line at a time rather than buffering the
+        response. Each is a 'downloading' event with file, completed and
+        total bytes, followed by one final 'success' or 'error' event.
+
+        :param payload: Pull request body with stream enabled.
+        :return: Iterator of decoded events.
+        :raises PullInterrupted: If the connection drops mid-stream.
+        :raises DaemonError: If the daemon sends a line that is not JSON.
+        """
+        return self._events("/policy/pull", payload, interrupted=PullInterrupted)
+
+    def _events(
+        self,
+        path: str,
+        payload: Dict[str, Any],
+        interrupted: Type[StreamInterrupted] = StreamInterrupted,
+        **kwargs,
+    ) -> Iterator[Dict[str, Any]]:
+        """
+        Post a request and decode its NDJSON response one line at a time.
+
+        :param path: Endpoint path.
+        :param payload: Request body with stream enabled.
+        :param interrupted: Exception raised if the connection drops.
+        :param kwargs: Extra arguments for requests (headers).
+        :return: Iterator of decoded events.
+        :raises StreamInterrupted: If the connection drops mid-stream.
+        :raises DaemonError: If the daemon sends a line that is not JSON.
+        """
+        r = self._request("post", path, json=payload, stream=True, **kwargs)
+        try:
+            for line in r.iter_lines():
+                if not line:
+                    continue
+                try:
+                    yield json.loads(line)
+                except ValueError as e:
+                    raise DaemonError(f"Malformed event from {path}: {line[:80]!r}") from e
+        except (requests.exceptions.ConnectionError, requests.exceptions.ChunkedEncodingError) as e:
+            raise interrupted(f"Lost connection to daemon: {e}") from e
+
+    def create(
+        self,
+        payload: Dict[str, Any],
+        progress: Optional[Callable[[Dict[str, Any]], None]] = None,
+    ) -> Dict[str, Any]:
+        """
+        Create a policy from a pulled base on the daemon host.
+
+        :param payload: Create request with name, base and optional weights,
+                        config, labels and overwrite.
+        :param progress: Optional callback receiving each 'creating' event.
+        :return: Dictionary with the created spec and policy record.
+        :raises StreamInterrupted: If the stream drops before the create finishes.
+        :raises DaemonError: If the create fails (409 if the policy exists).
+        """
+        if progress is None:
+            return self._post("/policy/create", json=payload)
+
+        for event in self._events("/policy/create", {**payload, "stream": True}):
+            if event.get("status") == "error":
+                raise DaemonError(event.get("error", "Create failed"))
+            if event.get("status") == "success":
+                return event
+            progress(event)
+        raise StreamInterrupted("Create stream ended without a result")
+
+    def push(
+        self,
+        payload: Dict[str, Any],
+        progress: Optional[Callable[[Dict[str, Any]], None]] = None,
+        token: Optional[str] = None,
+    ) -> Dict[str, Any]:
+        """
+        Upload a policy from the daemon host to a registry.
+
+        :param payload: Push request with spec, repo and optional registry,
+                        revision and message.
+        :param progress: Optional callback receiving each 'uploading' event.
+        :param token: Registry token sent as the Authorization header.
+                     Without it the daemon uses its stored login.
+        :return: Dictionary with the repo, commit hash and uploaded and skipped files.
+        :raises StreamInterrupted: If the stream drops before the push finishes.
+        :raises DaemonError: If the push fails.
+        """
+        headers = {"Authorization": f"Bearer {token}"} if token else {}
+        if progress is None:
+            return self._post("/policy/push", json=payload, headers=headers)
+
+        for event in self._events("/policy/push", {**payload, "stream": True}, headers=headers):
+            if event.get("status") == "error":
+                raise DaemonError(event.get("error", "Push failed"))
+            if event.get("status") == "success":
+                return event
+            progress(event)
+        raise StreamInterrupted("Push stream ended without a result")
+
+    def serve_policy(self, payload: Dict[str, Any]) -> Dict[str, Any]:
+        """
+        Load a pulled policy into a container.
+
+        :param payload: Serve request with spec, device and optional
+                        model_load_kwargs, host_port and keep_alive.
+        :return: Dictionary with 'policy_id', 'port' and 'device'.
+        """
+        return self._post("/policy/serve", json=payload)
+
+    def act(self, payload: Dict[str, Any]) -> Dict[str, Any]:
+        """
+        Get an action from a serving policy for one observation.
+
+        :param payload: Act request with policy_id, image and instruction.
+        :return: Dictionary containing the action.
+        """
+        return self._post("/policy/act", json=payload)
+
+    def act_batch(self, payload: Dict[str, Any]) -> Dict[str, Any]:
+        """
+        Get actions from a serving policy for several observations at once.
+
+        :param payload: Batch act request with policy_id and parallel
+                        'image' and 'instruction' lists.
+        :return: Dictionary containing 'actions' in input order.
+        """
+        return self._post("/policy/act_batch", json=payload)
+
+    def run(self, payload: Dict[str, Any], timeout: Optional[float] = None) -> Dict[str, Any]:
+        """
+        Run a policy on an environment task for one episode.
+
+        :param payload: Run request with policy_id, env_id, task and options.
+        :param timeout: Optional request timeout in seconds.
+        :return: Episode result.
+        """
+        return self._post("/run", json=payload, timeout=timeout)
+
+    def run_events(self, payload: Dict[str, Any], timeout: Optional[float] = None) -> Iterator[Dict[str, Any]]:
+        """
+        Run one episode, streaming each step as it happens.
+
+        :param payload: Run request with policy_id, env_id, task and options.
+        :param timeout: Optional timeout in seconds between received bytes.
+        :return: Iterator of {"step", "action", "timestamp"} events, then one
+                 final 'success' event with the episode result or an 'error' event.
+        :raises StreamInterrupted: If the connection drops mid-stream.
+        :raises DaemonError: If the daemon sends a line that is not JSON.
+        """
+        return self._events("/run", {**payload, "stream": True}, timeout=timeout)
+
+    def stop_policy(self, policy: str) -> Dict[str, Any]:
+        """
+        Unload a policy.
+
+        :param policy: Policy ID or name:version spec.
+        :return: Dictionary with 'stopped' IDs and 'freed_memory'.
+        """
+        return self._post(f"/policy/stop/{policy}")
+
+    def shutdown(self) -> None:
+        """
+        Stop the daemon and every container it manages.
+        """
+        self._request("post", "/stop")
@@ -245,6 +245,17 @@ class EnvBackend(ABC):
                 f"Build it with: docker build -t {self._image} docker/libero/"
             )
     
+    def image_size(self) -> Optional[int]:
+        """
+        Get the size of the environment Docker image.
+        
+        :return: Image size in bytes, or None if the image is not available locally.
+        """
+        try:
+            return self.client.images.get(self._image).attrs.get("Size")
+        except NotFound:
+            return None
+
     def serve(self, num_envs: int = 1, device: str= "cpu", host_port: Optional[int] = None) -> List[EnvHandle]:
         """
         Start environment container(s).
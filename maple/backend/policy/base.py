@@ -21,8 +21,11 @@ Key features:
 """
 
 import io
+import os
 import uuid
 import time
+import shutil
+import threading
 import base64
 import docker
 import requests
@@ -33,15 +36,32 @@ from docker.errors import NotFound, APIError
 from abc import ABC, abstractmethod
 from dataclasses import dataclass, field
 from typing import List, Dict, Any, Optional
-from huggingface_hub import snapshot_download
 
 from maple.utils.retry import retry
+from maple.utils.hub import Hub
+from maple.utils.download import download_repo, plan_download, resolve_revision, ProgressCallback
 from maple.utils.logging import get_logger
 from maple.utils.config import get_config
 from maple.utils.cleanup import register_container, unregister_container
 
 log = get_logger("policy.base")
 
+# Suffix of the directory a pull of a new revision downloads into
+PULL_STAGING_SUFFIX = ".pulling"
+
+def _swap_dir(staging: Path, dst: Path) -> None:
+    """
+    Replace a directory with a fully populated staging directory.
+    
+    :param staging: New contents.
+    :param dst: Directory to replace.
+    """
+    old = dst.with_name(f".{dst.name}.replaced")
+    shutil.rmtree(old, ignore_errors=True)
+    os.replace(dst, old)
+    os.replace(staging, dst)
+    shutil.rmtree(old, ignore_errors=True)
+
 def _get_config_value(attr: str, default: Any) -> Any:
     """
     Get configuration value with fallback to default.
@@ -136,6 +156,8 @@ class PolicyBackend(ABC):
     name: str
     _image: str
     _hf_repos: Dict[str, str]  # version -> HuggingFace repo ID
+    description: str = ""  # One-line summary shown by 'maple search'
+    param_size: str = ""   # Approximate parameter count (e.g., '7B')
     _container_port: int = 8000
     _startup_timeout: int = 300
     _health_check_interval: int = 5
@@ -161,6 +183,17 @@ class PolicyBackend(ABC):
         self._startup_timeout = _get_config_value("startup_timeout", self._startup_timeout)
         self._health_check_interval = _get_config_value("health_check_interval", self._health_check_interval)
 
+    @classmethod
+    def list_versions(cls) -> List[str]:
+        """
+        List the versions this backend can pull.
+        
+        Available without instantiating the backend (no Docker connection).
+        
+        :return: List of version names.
+        """
+        return list(cls._hf_repos.keys())
+
     @abstractmethod
     def info(self) -> Dict:
         """
@@ -195,6 +228,32 @@ class PolicyBackend(ABC):
         """
         pass
 
+    def act_batch(
+        self,
+        handle: PolicyHandle,
+        payloads: List[Any],
+        instructions: List[str],
+        model_kwargs: Optional[Dict[str, Any]] = {}
+    ) -> List[List[float]]:
+        """
+        Get action predictions for a batch of observations.
+
+        The default implementation calls act() once per observation against
+        the already-loaded model, which saves the caller one HTTP round trip
+        per step. Backends whose containers accept batched inputs can override
+        this to run them in a single forward pass.
+
+        :param handle: Policy handle for the running container.
+        :param payloads: Transformed observations, one per batch item.
+        :param instructions: Natural language instruction for each observation.
+        :param model_kwargs: Model-specific parameters shared by the batch.
+        :return: Predicted actions in the same order as the inputs.
+        """
+        return [
+            self.act(handle, payload, instruction, model_kwargs)
+            for payload, instruction in zip(payloads, instructions)
+        ]
+
     def _get_base_url(self, handle: PolicyHandle) -> str:
         """
         Get base URL for HTTP requests to container.
@@ -486,6 +545,27 @@ class PolicyBackend(ABC):
         # Remove from active handles
         self._active_handles.pop(handle.policy_id, None)
 
+    def memory_usage(self, handle: PolicyHandle) -> Optional[int]:
+        """
+        Get the current memory usage of a policy container.
+        
+        Reads a one-shot Docker stats snapshot. Used to report how much
+        memory is freed when a policy is stopped.
+        
+        :param handle: Policy handle to query.
+        :return: Memory usage in bytes, or None if unavailable.
+        """
+        if not handle.container_id:
+            return None
+        
+        try:
+            container = self.client.containers.get(handle.container_id)
+            stats = container.stats(stream=False)
+            return stats.get("memory_stats", {}).get("usage")
+        except Exception as e:
+            log.debug(f"Could not read memory stats for {handle.policy_id}: {e}")
+            return None
+
     def get_info(self, handle: PolicyHandle) -> Dict:
         """
         Get information about a running policy instance.
@@ -539,20 +619,48 @@ class PolicyBackend(ABC):
                 f"Build it with: docker build -t {self._image} docker/{self.name}/"
             )
 
-    def pull(self, version: str, dst: Path) -> Dict:
+    def pull(
+        self, 
+        version: str, 
+        dst: Path, 
+        repo: Optional[str] = None,
+        token: Optional[str] = None,
+        progress: Optional[ProgressCallback] = None,
+        concurrency: int = 3,
+        max_retries: int = 3,
+        revision: Optional[str] = None,
+        cancel: Optional[threading.Event] = None,
+        hub: Optional[Hub] = None,
+        pulled_revision: Optional[str] = None,
+    ) -> Dict:
         """
         Pull model weights from HuggingFace and Docker image.
         
         Downloads the specified model version from HuggingFace Hub and
         ensures the Docker image is available locally. This prepares
-        everything needed to serve the policy.
+        everything needed to serve the policy. The revision is resolved to
+        a commit hash first so every file comes from the same commit.
         
-        :param version: Model version to pull (must exist in _hf_repos).
+        If dst holds weights of a different revision, the new revision is
+        downloaded into a staging directory next to it, reusing unchanged
+        files, and swapped in once complete. A failed pull then leaves the
+        old weights intact instead of a mix of both revisions.
+        
+        :param version: Model version to pull (must exist in _hf_repos unless repo is given).
         :param dst: Destination directory for model weights.
-        :return: Dictionary with pull metadata (name, version, repo, path).
+        :param repo: Optional HuggingFace repo ID overriding the built-in version mapping.
+        :param token: Optional HuggingFace token for private repos. Defaults to $HF_TOKEN.
+        :param progress: Optional callback receiving (file, completed_bytes, total_bytes).
+        :param concurrency: Maximum number of files downloaded at once.
+        :param max_retries: Retries per file for transient network errors.
+        :param revision: Optional branch, tag or commit to pull. Defaults to the main branch.
+        :param cancel: Optional event that aborts the weight download when set.
+        :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+        :param pulled_revision: Commit the weights in dst were pulled at, if any.
+        :return: Dictionary with pull metadata (name, version, repo, revision, path).
         """
         # Validate version
-        repo = self._hf_repos.get(version)
+        repo = repo or self._hf_repos.get(version)
         if repo is None:
             raise ValueError(f"Unknown version '{version}' for {self.name}")
         
@@ -563,11 +671,27 @@ class PolicyBackend(ABC):
         self.pull_image()
         
         # Download model weights from HuggingFace
-        log.info(f"Downloading {repo} to {dst}...")
-        snapshot_download(
+        token = token or os.environ.get("HF_TOKEN")
+        revision = resolve_revision(repo, revision=revision, token=token, hub=hub)
+        replacing = bool(pulled_revision) and pulled_revision != revision and any(dst.iterdir())
+        target = dst.with_name(f".{dst.name}{PULL_STAGING_SUFFIX}") if replacing else dst
+        if replacing:
+            log.info(f"Replacing {repo}@{pulled_revision[:12]} with {revision[:12]}")
+        log.info(f"Downloading {repo}@{revision[:12]} to {target}...")
+        download_repo(
             repo_id=repo,
-            local_dir=dst,
+            dst=target,
+            token=token,
+            revision=revision,
+            progress=progress,
+            max_workers=concurrency,
+            max_retries=max_retries,
+            cancel=cancel,
+            hub=hub,
+            reuse_from=dst if replacing else None,
         )
+        if replacing:
+            _swap_dir(target, dst)
         log.info(f"Download complete: {repo}")
         
         return {
@@ -576,9 +700,54 @@ class PolicyBackend(ABC):
             "version": version,
             "source": "huggingface",
             "repo": repo,
+            "revision": revision,
             "path": str(dst),
         }
 
+    def plan_pull(
+        self,
+        version: str,
+        dst: Path,
+        repo: Optional[str] = None,
+        token: Optional[str] = None,
+        revision: Optional[str] = None,
+        hub: Optional[Hub] = None,
+    ) -> Dict:
+        """
+        Preview a pull without downloading anything.
+        
+        Lists the weight files of the repo and marks those already present
+        in dst with the expected size, which a pull would skip.
+        
+        :param version: Model version to pull (must exist in _hf_repos unless repo is given).
+        :param dst: Destination directory for model weights.
+        :param repo: Optional HuggingFace repo ID overriding the built-in version mapping.
+        :param token: Optional HuggingFace token for private repos. Defaults to $HF_TOKEN.
+        :param revision: Optional branch, tag or commit. Defaults to the main branch.
+        :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+        :return: Dictionary with repo, per-file entries and total, cached and download sizes.
+        """
+        repo = repo or self._hf_repos.get(version)
+        if repo is None:
+            raise ValueError(f"Unknown version '{version}' for {self.name}")
+        
+        token = token or os.environ.get("HF_TOKEN")
+        files = [
+            {"file": name, "size": size, "cached": cached}
+            for name, size, cached in plan_download(repo, dst, token=token, revision=revision, hub=hub)
+        ]
+        total = sum(f["size"] for f in files)
+        cached = sum(f["size"] for f in files if f["cached"])
+        
+        return {
+            "repo": repo,
+            "revision": revision,
+            "files": files,
+            "total": total,
+            "cached": cached,
+            "download": total - cached,
+        }
+
     def health(self, handle: PolicyHandle) -> Dict:
         """
         Check health of a policy instance.
@@ -61,6 +61,8 @@ class GR00TN15Policy(PolicyBackend):
     
     name = "groot"
     _image = "maplerobotics/gr00tn1.5:latest"
+    description = "NVIDIA Isaac GR00T cross-embodiment foundation model"
+    param_size = "3B"
     
     # Map version strings to HuggingFace repository paths
     _hf_repos = {
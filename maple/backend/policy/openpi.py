@@ -20,10 +20,12 @@ Available versions:
 """
 
 import shutil
+import threading
 from pathlib import Path
-from typing import List, Optional, Any, Dict
+from typing import Callable, List, Optional, Any, Dict
 import requests
 
+from maple.utils.hub import Hub
 from maple.utils.logging import get_logger
 from maple.utils.misc import parse_error_response
 from maple.backend.policy.base import PolicyBackend, PolicyHandle
@@ -47,6 +49,8 @@ class OpenPIPolicy(PolicyBackend):
     
     name = "openpi"
     _image = "maplerobotics/openpi:latest"
+    description = "Physical Intelligence pi0/pi0.5 flow-matching policies"
+    param_size = "3B"
     
     # Map version strings to S3 checkpoint paths (public bucket, anonymous access)
     _gs_checkpoints = {
@@ -225,7 +229,29 @@ class OpenPIPolicy(PolicyBackend):
         if resp.status_code != 200:
             raise RuntimeError(f"Failed to load model: {parse_error_response(resp)}")
 
-    def pull(self, version: str, dst: Path) -> Dict:
+    @classmethod
+    def list_versions(cls) -> List[str]:
+        """
+        List the versions this backend can pull, from both GCS and HuggingFace.
+        
+        :return: List of version names.
+        """
+        return list(cls._gs_checkpoints.keys()) + list(cls._hf_repos.keys())
+
+    def pull(
+        self, 
+        version: str, 
+        dst: Path, 
+        repo: Optional[str] = None,
+        token: Optional[str] = None,
+        progress: Optional[Callable[[str, int, int], None]] = None,
+        concurrency: int = 3,
+        max_retries: int = 3,
+        revision: Optional[str] = None,
+        cancel: Optional[threading.Event] = None,
+        hub: Optional[Hub] = None,
+        pulled_revision: Optional[str] = None,
+    ) -> Dict:
         """
         Pull model weights and Docker image.
         
@@ -234,14 +260,57 @@ class OpenPIPolicy(PolicyBackend):
                 
         :param version: Model version to download (e.g., 'pi05_droid', 'pi0_base').
         :param dst: Destination path for model weights (parent directory is used).
+        :param repo: Optional HuggingFace repo ID overriding the built-in version mapping.
+        :param token: Optional HuggingFace token for private repos.
+        :param progress: Optional callback receiving (file, completed_bytes, total_bytes).
+                        Only used for HuggingFace downloads.
+        :param concurrency: Maximum number of files downloaded at once (HuggingFace only).
+        :param max_retries: Retries per file for transient network errors (HuggingFace only).
+        :param revision: Optional branch, tag or commit to pull (HuggingFace only).
+        :param cancel: Optional event that aborts the download when set (HuggingFace only).
+        :param hub: Registry connection settings (HuggingFace only).
+        :param pulled_revision: Commit the weights in dst were pulled at (HuggingFace only).
         :return: Dictionary with download metadata including name, image, version,
                 source, gs_path, config_name, and local path.
         """
 
-        if "gs" in version:
+        if repo is None and "gs" in version:
+            if revision is not None:
+                raise ValueError(f"Revision pinning is only supported for HuggingFace checkpoints, not '{version}'")
             return self.pull_gs(version, dst)
         else:
-            return super().pull(version, dst)
+            return super().pull(
+                version, dst, repo=repo, token=token, progress=progress,
+                concurrency=concurrency, max_retries=max_retries, revision=revision,
+                cancel=cancel, hub=hub, pulled_revision=pulled_revision,
+            )
+
+    def plan_pull(
+        self,
+        version: str,
+        dst: Path,
+        repo: Optional[str] = None,
+        token: Optional[str] = None,
+        revision: Optional[str] = None,
+        hub: Optional[Hub] = None,
+    ) -> Dict:
+        """
+        Preview a pull without downloading anything.
+        
+        Only HuggingFace checkpoints can be previewed; GCS checkpoints
+        are not supported.
+        
+        :param version: Model version to pull.
+        :param dst: Destination directory for model weights.
+        :param repo: Optional HuggingFace repo ID overriding the built-in version mapping.
+        :param token: Optional HuggingFace token for private repos.
+        :param revision: Optional branch, tag or commit.
+        :param hub: Registry connection settings.
+        :return: Dictionary with repo, per-file entries and total, cached and download sizes.
+        """
+        if repo is None and "gs" in version:
+            raise ValueError(f"Dry run is only supported for HuggingFace checkpoints, not '{version}'")
+        return super().plan_pull(version, dst, repo=repo, token=token, revision=revision, hub=hub)
 
     def pull_gs(self, version: str, dst: Path) -> Dict:
         """
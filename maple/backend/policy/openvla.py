@@ -37,6 +37,8 @@ class OpenVLAPolicy(PolicyBackend):
     
     name = "openvla"
     _image = "maplerobotics/openvla:latest"
+    description = "Open vision-language-action model for generalist manipulation"
+    param_size = "7B"
     
     # Map version strings to HuggingFace repository paths
     _hf_repos = {
@@ -63,6 +65,7 @@ class OpenVLAPolicy(PolicyBackend):
             "type": "policy",
             "inputs": ["image", "instruction"],  # Required inputs for inference
             "outputs": ["action"],  # Model produces action vectors
+            "action_dim": 7,  # [x y z rx ry rz gripper]
             "versions": list(self._hf_repos.keys()),  # Available model versions
             "image": self._image,  # Docker image used for serving
         }
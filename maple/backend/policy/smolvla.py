@@ -39,6 +39,8 @@ class SmolVLAPolicy(PolicyBackend):
     
     name = "smolvla"
     _image = "maplerobotics/smolvla:latest"
+    description = "Compact LeRobot VLA with multi-camera and state inputs"
+    param_size = "450M"
     
     # Map version strings to HuggingFace repository paths
     _hf_repos = {
@@ -3,8 +3,17 @@ Policy and Environment Backend Registry
 
 POLICY_BACKENDS: Simple dict mapping "policy" → Policy backend class.
 ENV_BACKENDS: Simple dict mapping "env" → Environment backend class.
+infer_policy_backend: Resolve a HuggingFace repo to a (backend, version) pair.
+policy_catalog: Every pullable built-in policy spec with its metadata.
+hub_entry: Describe a HuggingFace repo in the same form as a catalog entry.
 """
 
+import re
+import requests
+from typing import Dict, List, Optional, Tuple
+
+from maple.utils.hub import Hub
+
 from .policy import OpenVLAPolicy, SmolVLAPolicy, OpenPIPolicy, GR00TN15Policy
 from .envs import LiberoEnvBackend, RoboCasaEnvBackend, FractalBackend, BridgeBackend, AlohaSimBackend
 
@@ -21,4 +30,109 @@ POLICY_BACKENDS = {
     "smolvla": SmolVLAPolicy,
     "openpi": OpenPIPolicy,
     "gr00tn15": GR00TN15Policy
-}
\ No newline at end of file
+}
+
+# Substrings in a repo ID or model config that identify a policy architecture
+_ARCH_HINTS = {
+    "openvla": "openvla",
+    "smolvla": "smolvla",
+    "openpi": "openpi",
+    "pi0": "openpi",
+    "gr00t": "gr00tn15",
+}
+
+def match_policy_arch(text: str) -> Optional[str]:
+    """
+    Match free text against the known architecture hints.
+    
+    :param text: Repo ID or serialized model config.
+    :return: Policy backend name, or None if nothing matched.
+    """
+    text = text.lower()
+    for hint, backend in _ARCH_HINTS.items():
+        if hint in text:
+            return backend
+    return None
+
+def infer_policy_backend(
+    repo_id: str, 
+    token: Optional[str] = None, 
+    hub: Optional[Hub] = None,
+) -> Optional[Tuple[str, str]]:
+    """
+    Infer which policy backend can serve a HuggingFace repo.
+    
+    Repos already known to a backend keep their built-in version name.
+    Otherwise the architecture is guessed from the repo ID, then from the
+    repo's config.json, and the version is derived from the repo name.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param token: Optional HuggingFace token for private repos.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: Tuple of (backend_name, version), or None if no backend matched.
+    """
+    for name, backend_cls in POLICY_BACKENDS.items():
+        for version, repo in backend_cls._hf_repos.items():
+            if repo.lower() == repo_id.lower():
+                return name, version
+
+    version = repo_id.split("/")[-1].lower()
+    backend = match_policy_arch(repo_id)
+
+    if backend is None:
+        # Fall back to the architecture declared in the model config
+        try:
+            hub = hub or Hub()
+            for endpoint in hub.endpoints:
+                try:
+                    resp = requests.get(
+                        hub.file_url(repo_id, "config.json", endpoint=endpoint),
+                        headers={"Authorization": f"Bearer {token}"} if token and endpoint == hub.endpoint else {},
+                        timeout=30,
+                        **hub.request_kwargs(),
+                    )
+                    resp.raise_for_status()
+                except requests.RequestException:
+                    continue
+                backend = match_policy_arch(resp.text)
+                break
+        except Exception:
+            return None
+
+    return (backend, version) if backend else None
+
+def policy_catalog() -> List[Dict[str, str]]:
+    """
+    List every built-in policy spec that can be pulled.
+    
+    :return: List of dictionaries with name ('backend:version'), arch, size and description.
+    """
+    return [
+        {
+            "name": f"{name}:{version}",
+            "arch": name,
+            "size": backend_cls.param_size,
+            "description": backend_cls.description,
+        }
+        for name, backend_cls in POLICY_BACKENDS.items()
+        for version in backend_cls.list_versions()
+    ]
+
+def hub_entry(repo_id: str) -> Optional[Dict[str, str]]:
+    """
+    Describe a HuggingFace repo as a catalog entry if a backend can serve it.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'lerobot/smolvla_base').
+    :return: Catalog dictionary, or None if the architecture is not recognised.
+    """
+    arch = match_policy_arch(repo_id)
+    if arch is None:
+        return None
+    # Parameter counts are commonly part of the repo name (e.g., '-7b', '_3B')
+    size = re.search(r"(?<![a-z0-9])(\d+(?:\.\d+)?[mb])(?![a-z])", repo_id.split("/")[-1].lower())
+    return {
+        "name": f"hf.co/{repo_id}",
+        "arch": arch,
+        "size": size.group(1).upper() if size else "",
+        "description": "",
+    }
@@ -11,4 +11,17 @@ from .config import config_app
 from .rmv import remove_app
 from .snc import sync_app
 from .doctor import doctor_app
-from .logs import logs_app
\ No newline at end of file
+from .logs import logs_app
+from .ps import ps_app
+from .completion import completion, complete_policy_spec, complete_policy_id
+from .lockfile import lock, verify_lock
+from .annotate import annotate
+from .history import history
+from .copy import cp
+from .bench import bench
+from .restart import restart
+from .prune import prune
+from .create import create
+from .push import push
+from .compat import compat
+from .replay import replay
@@ -0,0 +1,81 @@
+"""
+Annotation commands for the MAPLE CLI.
+
+This module attaches free-form key/value notes to pulled policies, such as
+training run IDs, dataset versions or evaluation scores. Annotations live
+in the local database next to the policy and are kept when it is pulled
+again.
+
+Commands:
+- annotate: Set, remove or show the annotations of a pulled policy
+"""
+
+import typer
+from rich import print
+from rich.markup import escape
+from typing import Dict, List
+
+from maple.utils.spec import parse_versioned
+from maple.state.store import get_policy, set_policy_annotations
+from maple.cmd.cli.completion import complete_policy_spec
+
+def parse_annotations(pairs: List[str]) -> Dict[str, str]:
+    """
+    Parse KEY=VALUE arguments into a dictionary.
+
+    :param pairs: Arguments in 'key=value' form. The value may contain '='.
+    :return: Dictionary of annotations.
+    """
+    annotations = {}
+    for pair in pairs:
+        key, sep, value = pair.partition("=")
+        key = key.strip()
+        if not sep or not key:
+            raise ValueError(f"Invalid annotation '{pair}' (expected key=value)")
+        annotations[key] = value
+    return annotations
+
+def annotate(
+    model: str = typer.Argument(..., help="Policy to annotate (name:version)", autocompletion=complete_policy_spec),
+    pairs: List[str] = typer.Argument(None, help="Annotations to set, as key=value"),
+    remove: List[str] = typer.Option(None, "--rm", help="Annotation key to remove (repeatable)"),
+) -> None:
+    """
+    Set, remove or show annotations on a pulled policy.
+
+    With no key=value pairs and no --rm, prints the current annotations.
+
+    :param model: Policy specification (name:version).
+    :param pairs: Annotations to set.
+    :param remove: Annotation keys to remove.
+    """
+    try:
+        name, version = parse_versioned(model)
+        updates = parse_annotations(pairs or [])
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    policy = get_policy(name, version)
+    if policy is None:
+        print(f"[red]Error:[/red] Policy {name}:{version} not found in database")
+        raise typer.Exit(1)
+
+    annotations = dict(policy["annotations"])
+    if updates or remove:
+        missing = [key for key in remove or [] if key not in annotations]
+        if missing:
+            print(f"[red]Error:[/red] No annotation named {', '.join(missing)} on {name}:{version}")
+            raise typer.Exit(1)
+
+        for key in remove or []:
+            del annotations[key]
+        annotations.update(updates)
+        set_policy_annotations(name, version, annotations)
+
+    if not annotations:
+        print(f"[dim]No annotations on {name}:{version}[/dim]")
+        return
+
+    for key in sorted(annotations):
+        print(escape(f"{key}={annotations[key]}"))
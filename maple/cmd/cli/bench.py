@@ -0,0 +1,155 @@
+"""
+Benchmark command for the MAPLE CLI.
+
+This module measures inference throughput of a policy on the local machine
+so users can compare hardware and report reproducible numbers in issues.
+It serves the policy through the daemon, sends synthetic /policy/act
+requests with a fixed gray image at the policy's input size, and prints
+latency percentiles and actions per second.
+
+Commands:
+- bench: Benchmark inference latency and throughput of a policy
+"""
+
+import time
+import typer
+from rich import print
+from rich.table import Table
+from typing import List, Optional, Tuple
+from PIL import Image
+from concurrent.futures import ThreadPoolExecutor
+
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.utils.config import get_config
+from maple.utils.misc import load_kwargs
+from maple.utils.image import encode_png, policy_image_size
+from maple.cmd.cli.completion import complete_policy_spec
+
+# Input size used when the policy does not report one
+DEFAULT_IMAGE_SIZE = (224, 224)
+
+def percentile(samples: List[float], q: float) -> float:
+    """
+    Compute a percentile by linear interpolation between closest ranks.
+
+    :param samples: Measured values.
+    :param q: Percentile between 0 and 100.
+    :return: Value at the percentile, or 0.0 for no samples.
+    """
+    if not samples:
+        return 0.0
+    ordered = sorted(samples)
+    rank = (len(ordered) - 1) * q / 100
+    low = int(rank)
+    high = min(low + 1, len(ordered) - 1)
+    return ordered[low] + (ordered[high] - ordered[low]) * (rank - low)
+
+def _synthetic_image(size: Tuple[int, int]) -> str:
+    """
+    Build the fixed benchmark image.
+
+    :param size: (width, height) of the image.
+    :return: Base64 PNG of a uniform gray image.
+    """
+    return encode_png(Image.new("RGB", size, color=(128, 128, 128)))
+
+def bench(
+    model: str = typer.Argument(..., help="Policy to benchmark (e.g., openvla:7b)", autocompletion=complete_policy_spec),
+    num_requests: int = typer.Option(100, "--requests", "-n", min=1, help="Number of timed requests"),
+    concurrency: int = typer.Option(1, "--concurrency", "-c", min=1, help="Requests in flight at once"),
+    warmup: int = typer.Option(1, "--warmup", min=0, help="Untimed requests sent before measuring"),
+    instruction: str = typer.Option("pick up the object", "--instruction", "-i", help="Instruction sent with each request"),
+    device: Optional[str] = typer.Option(None, "--device", "-d", help="Device to load the policy on"),
+    model_load_kwargs: str = typer.Option(None, "--mdl-kwargs", "-m", help="Model-specific loading parameters"),
+    keep: bool = typer.Option(False, "--keep", help="Leave the policy loaded after the benchmark"),
+    port: int = typer.Option(None, "--port"),
+) -> None:
+    """
+    Benchmark inference latency and throughput of a policy.
+
+    Serves MODEL through the daemon, then sends synthetic requests with a
+    fixed gray image at the policy's input size. Warmup requests are not
+    timed. The policy is stopped afterwards unless --keep is given.
+
+    :param model: Policy specification (name or name:version).
+    :param num_requests: Number of timed requests.
+    :param concurrency: Number of requests in flight at once.
+    :param warmup: Untimed requests sent first, e.g. to compile kernels.
+    :param instruction: Instruction sent with each request.
+    :param device: Device to load the policy on. Defaults to the config.
+    :param model_load_kwargs: Model-specific loading parameters.
+    :param keep: If True, leave the policy loaded when done.
+    :param port: Daemon port number.
+    """
+    config = get_config()
+    port = port or config.daemon.port
+    device = device or config.policy.default_device
+    model_load_kwargs = load_kwargs(model_load_kwargs) or config.policy.model_load_kwargs
+    client = Client.from_config(port)
+
+    try:
+        print(f"[cyan]Loading {model} on {device}...[/cyan]")
+        served = client.serve_policy({"spec": model, "device": device, "model_load_kwargs": model_load_kwargs, "keep_alive": "-1"})
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    policy_id = served["policy_id"]
+    try:
+        try:
+            size = policy_image_size(client.policy_info(policy_id)) or DEFAULT_IMAGE_SIZE
+        except DaemonError:
+            size = DEFAULT_IMAGE_SIZE
+        payload = {"policy_id": policy_id, "image": _synthetic_image(size), "instruction": instruction}
+
+        for _ in range(warmup):
+            client.act(payload)
+
+        def timed_act(_: int) -> Optional[float]:
+            start = time.perf_counter()
+            try:
+                client.act(payload)
+            except (DaemonError, DaemonNotRunning):
+                return None
+            return time.perf_counter() - start
+
+        print(f"[cyan]Sending {num_requests} requests ({concurrency} concurrent, {size[0]}x{size[1]} image)...[/cyan]")
+        start = time.perf_counter()
+        with ThreadPoolExecutor(max_workers=concurrency) as pool:
+            results = list(pool.map(timed_act, range(num_requests)))
+        elapsed = time.perf_counter() - start
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    finally:
+        if not keep:
+            try:
+                client.stop_policy(policy_id)
+            except (DaemonError, DaemonNotRunning):
+                print(f"[yellow]Warning:[/yellow] Could not stop {policy_id}")
+
+    latencies = [r for r in results if r is not None]
+    failed = len(results) - len(latencies)
+    if not latencies:
+        print(f"[red]Error:[/red] All {failed} requests failed")
+        raise typer.Exit(1)
+
+    table = Table(title=f"Benchmark of {model} ({policy_id})")
+    table.add_column("METRIC")
+    table.add_column("VALUE", justify="right")
+    table.add_row("Device", device)
+    table.add_row("Requests", f"{len(latencies)} ok, {failed} failed")
+    table.add_row("Concurrency", str(concurrency))
+    for q in (50, 95, 99):
+        table.add_row(f"p{q} latency", f"{percentile(latencies, q) * 1000:.1f} ms")
+    table.add_row("Actions/sec", f"{len(latencies) / elapsed:.2f}")
+
+    print(table)
+    if failed:
+        raise typer.Exit(1)
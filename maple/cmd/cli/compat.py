@@ -0,0 +1,89 @@
+"""
+Compatibility command for the MAPLE CLI.
+
+This module shows which pulled policies can run in which environments, so
+a valid 'maple run' pairing can be picked without trial and error. A
+policy supports an environment when an adapter is registered for the
+pair; the environment must also be pulled before it can be served.
+
+Commands:
+- compat: Print the policy/environment compatibility matrix
+"""
+
+import json
+import typer
+from rich import print
+from rich.table import Table
+from typing import Dict, List
+
+from maple.state import store
+from maple.adapters import has_adapter
+from maple.backend.registry import ENV_BACKENDS
+from maple.utils.ui import info, NAME_STYLE
+
+# Cell values of the matrix
+SUPPORTED = "supported"
+NOT_INSTALLED = "not installed"
+UNSUPPORTED = "unsupported"
+
+_STYLES = {
+    SUPPORTED: "[green]✓ supported[/green]",
+    NOT_INSTALLED: "[yellow]not installed[/yellow]",
+    UNSUPPORTED: "[dim]-[/dim]",
+}
+
+def compat_matrix(policies: List[str], envs: List[str], installed: List[str]) -> Dict[str, Dict[str, str]]:
+    """
+    Work out which policies can run in which environments.
+
+    :param policies: Policy specs (name:version).
+    :param envs: Environment names to check.
+    :param installed: Environment names that are pulled.
+    :return: Mapping of policy spec to environment name to one of
+            'supported', 'not installed' (adapter exists but the
+            environment is not pulled) or 'unsupported'.
+    """
+    matrix = {}
+    for policy in policies:
+        row = {}
+        for env in envs:
+            if not has_adapter(policy, env):
+                row[env] = UNSUPPORTED
+            else:
+                row[env] = SUPPORTED if env in installed else NOT_INSTALLED
+        matrix[policy] = row
+    return matrix
+
+def compat(
+    as_json: bool = typer.Option(False, "--json", help="Print the matrix as JSON"),
+) -> None:
+    """
+    Print which pulled policies support which environments.
+
+    Rows are pulled policies and columns are every known environment.
+    'not installed' means an adapter exists but the environment has not
+    been pulled yet.
+
+    :param as_json: If True, print a JSON object instead of a table.
+    """
+    policies = [f"{p['name']}:{p['version']}" for p in store.list_policies()]
+    installed = [e["name"] for e in store.list_envs()]
+    envs = sorted(set(ENV_BACKENDS) | set(installed))
+    matrix = compat_matrix(sorted(policies), envs, installed)
+
+    if as_json:
+        typer.echo(json.dumps({"envs": envs, "installed": sorted(installed), "policies": matrix}, indent=2))
+        return
+
+    if not policies:
+        print("[yellow]No policies installed[/yellow]")
+        info("Pull one with: maple pull policy openvla:7b")
+        return
+
+    table = Table(title="Policy / environment compatibility")
+    table.add_column("POLICY", style=NAME_STYLE)
+    for env in envs:
+        table.add_column(env if env in installed else f"{env} [dim](not pulled)[/dim]")
+    for policy, row in matrix.items():
+        table.add_row(policy, *(_STYLES[row[env]] for env in envs))
+    print(table)
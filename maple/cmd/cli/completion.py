@@ -0,0 +1,78 @@
+"""
+Shell completion for the MAPLE CLI.
+
+This module provides the completion callbacks used by CLI arguments and a
+command that prints the completion script for a shell, so it can be added
+to a shell profile or sourced directly.
+
+Commands:
+- completion: Print the completion script for bash, zsh or fish
+
+Callbacks:
+- complete_policy_spec: Suggest pulled policies as name:version
+- complete_policy_id: Suggest policy IDs currently served by the daemon
+"""
+
+import click
+import typer
+import requests
+from typing import List
+from click.shell_completion import get_completion_class
+from rich import print
+from maple.utils.config import get_config
+from maple.utils.misc import daemon_url
+
+SHELLS = ("bash", "zsh", "fish")
+
+def complete_policy_spec(incomplete: str) -> List[str]:
+    """
+    Suggest pulled policies matching the text typed so far.
+
+    Reads the local database only, so it works without the daemon.
+
+    :param incomplete: Partially typed policy spec.
+    :return: Matching 'name:version' specs.
+    """
+    try:
+        from maple.state.store import list_policies
+        specs = [f"{p['name']}:{p['version']}" for p in list_policies()]
+    except Exception:
+        return []
+    return sorted(spec for spec in specs if spec.startswith(incomplete))
+
+def complete_policy_id(incomplete: str) -> List[str]:
+    """
+    Suggest IDs of policies currently served by the daemon.
+
+    :param incomplete: Partially typed policy ID.
+    :return: Matching policy IDs, or an empty list if the daemon is not reachable.
+    """
+    try:
+        r = requests.get(f"{daemon_url(get_config().daemon.port)}/status", timeout=1)
+        ids = r.json().get("serving", {}).get("policies", [])
+    except Exception:
+        return []
+    return sorted(policy_id for policy_id in ids if policy_id.startswith(incomplete))
+
+def completion(
+    shell: str = typer.Argument(..., help="Shell to generate completion for (bash, zsh or fish)"),
+) -> None:
+    """
+    Print the shell completion script.
+
+    Add the output to your shell profile, for example:
+    'maple completion bash >> ~/.bashrc'.
+
+    :param shell: Shell name.
+    """
+    # Typer registers its own bash, zsh and fish classes with click, so
+    # this is the same script 'maple --show-completion' prints
+    comp_cls = get_completion_class(shell) if shell in SHELLS else None
+    if comp_cls is None:
+        print(f"[red]Error:[/red] Unsupported shell '{shell}'. Choose from: {', '.join(SHELLS)}")
+        raise typer.Exit(1)
+
+    root = click.get_current_context().find_root().command
+    comp = comp_cls(root, {}, prog_name="maple", complete_var="_MAPLE_COMPLETE")
+    # Plain stdout so the script is not altered by rich markup
+    typer.echo(comp.source())
@@ -9,12 +9,14 @@ Commands:
 - show: Display current configuration with all settings
 - init: Create a default configuration file
 - path: Show the path to the configuration file
+- get: Print a single setting by dotted key
+- set: Persist a single setting to the configuration file
 """
 
 import yaml
 import typer 
 from rich import print
-from maple.utils.config import get_config, CONFIG_FILE
+from maple.utils.config import get_config, get_config_value, set_config_value, config_file
 
 # Create the config sub-application
 # no_args_is_help=True ensures help is shown when no command is given
@@ -48,16 +50,17 @@ def config_init(force: bool = typer.Option(False, "--force", "-f", help="Overwri
     :param force: If True, overwrite existing config file without prompting.
     """
     config = get_config()
+    path = config_file()
     # Check if config already exists
-    if CONFIG_FILE.exists() and not force:
+    if path.exists() and not force:
         # Warn user and exit without making changes
-        print(f"[yellow]Config already exists:[/yellow] {CONFIG_FILE}")
+        print(f"[yellow]Config already exists:[/yellow] {path}")
         print("Use --force to overwrite")
         return
     
     # Save config to file (creates parent directories if needed)
-    config.save(CONFIG_FILE)
-    print(f"[green]✓ Config created:[/green] {CONFIG_FILE}")
+    config.save(path)
+    print(f"[green]✓ Config created:[/green] {path}")
 
 @config_app.command("path")
 def config_path() -> None:
@@ -68,4 +71,52 @@ def config_path() -> None:
     Useful for locating the config file for manual editing or troubleshooting.
     """
     # Simply print the path - no additional formatting needed
-    print(CONFIG_FILE)
\ No newline at end of file
+    print(config_file())
+
+@config_app.command("get")
+def config_get(key: str = typer.Argument(..., help="Dotted key (e.g., daemon.port)")) -> None:
+    """
+    Show a single configuration value.
+    
+    Prints the effective value, including any MAPLE_* environment
+    variable override.
+    
+    :param key: Dotted key in 'section.field' form.
+    """
+    try:
+        value = get_config_value(key)
+    except KeyError as e:
+        print(f"[red]Error:[/red] {e.args[0]}")
+        raise typer.Exit(1)
+
+    # Dump nested values as YAML, scalars as-is
+    if isinstance(value, dict):
+        print(yaml.dump(value, default_flow_style=False, sort_keys=False).rstrip())
+    else:
+        print(value)
+
+@config_app.command("set")
+def config_set(
+    key: str = typer.Argument(..., help="Dotted key (e.g., daemon.port)"),
+    value: str = typer.Argument(..., help="New value"),
+) -> None:
+    """
+    Set a configuration value in the config file.
+    
+    The value is converted to the type of the existing setting. Environment
+    variables still take precedence over the file, and command line flags
+    over both.
+    
+    :param key: Dotted key in 'section.field' form.
+    :param value: New value as a string.
+    """
+    try:
+        converted = set_config_value(key, value)
+    except KeyError as e:
+        print(f"[red]Error:[/red] {e.args[0]}")
+        raise typer.Exit(1)
+    except ValueError as e:
+        print(f"[red]Error:[/red] Invalid value for {key}: {e}")
+        raise typer.Exit(1)
+
+    print(f"[green]✓ Set[/green] {key} = {converted}")
@@ -0,0 +1,130 @@
+"""
+Copy command for the MAPLE CLI.
+
+This module seeds the local MAPLE root with a policy from another root,
+such as a cache on a shared NFS mount, without downloading it again.
+Files already present locally with the same contents are skipped; every
+copied file is checked against its source digest before it is put in
+place. The source root is only read, never written.
+
+Commands:
+- cp: Copy a pulled policy from another MAPLE root
+"""
+
+import os
+import shutil
+import sqlite3
+import typer
+from rich import print
+from pathlib import Path
+from typing import Tuple
+
+from maple.utils.spec import parse_versioned
+from maple.utils.misc import format_size
+from maple.utils.download import file_sha256, is_cached, matches_digest
+from maple.utils.lock import lock_ref
+from maple.utils.paths import maple_home, policy_dir
+from maple.state.store import read_policy, add_policy, set_policy_annotations
+
+def copy_weights(src: Path, dst: Path) -> Tuple[int, int, int]:
+    """
+    Copy a weights directory, skipping files already present with the same contents.
+
+    A local file is skipped only if its size and SHA-256 digest match the
+    source. Each other file is copied to a '.part' file, checked against the source
+    digest and renamed into place.
+
+    :param src: Source weights directory.
+    :param dst: Destination weights directory.
+    :return: Tuple of (files copied, files skipped, bytes copied).
+    """
+    copied = skipped = copied_bytes = 0
+    for path in sorted(p for p in src.rglob("*") if p.is_file()):
+        if path.name.endswith(".part"):
+            continue
+        target = dst / path.relative_to(src)
+        size = path.stat().st_size
+        digest = f"sha256:{file_sha256(path)}"
+        if is_cached(target, size, digest):
+            skipped += 1
+            continue
+
+        target.parent.mkdir(parents=True, exist_ok=True)
+        part = target.with_name(target.name + ".part")
+        shutil.copyfile(path, part)
+        if not matches_digest(part, digest):
+            part.unlink()
+            raise RuntimeError(f"Digest mismatch copying {path}")
+        os.replace(part, target)
+        copied += 1
+        copied_bytes += size
+
+    return copied, skipped, copied_bytes
+
+def cp(
+    model: str = typer.Argument(..., help="Policy to copy (name:version)"),
+    source: Path = typer.Option(..., "--from", help="MAPLE root to copy from (e.g., /mnt/shared/.maple)"),
+) -> None:
+    """
+    Copy a pulled policy from another MAPLE root into this one.
+
+    The policy is registered locally with the repo, revision and
+    annotations recorded in the source root. The Docker image is not
+    copied; Docker fetches it when the policy is first served.
+
+    :param model: Policy specification (name:version).
+    :param source: Root directory of the source MAPLE installation.
+    """
+    try:
+        name, version = parse_versioned(model)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    source = source.expanduser().resolve()
+    if source == maple_home().resolve():
+        print(f"[red]Error:[/red] {source} is the current MAPLE root")
+        raise typer.Exit(1)
+    if not (source / "state.db").exists():
+        print(f"[red]Error:[/red] No MAPLE state found in {source}")
+        raise typer.Exit(1)
+
+    try:
+        policy = read_policy(source / "state.db", name, version)
+    except sqlite3.Error as e:
+        print(f"[red]Error:[/red] Cannot read {source / 'state.db'}: {e}")
+        raise typer.Exit(1)
+    if policy is None:
+        print(f"[red]Error:[/red] Policy {name}:{version} not found in {source}")
+        raise typer.Exit(1)
+
+    # Prefer the source root's own layout; the stored path may be from another mount
+    src = source / "models" / name / version
+    if not src.is_dir():
+        src = Path(policy["path"])
+    if not src.is_dir():
+        print(f"[red]Error:[/red] Weights for {name}:{version} not found under {source}")
+        raise typer.Exit(1)
+
+    dst = policy_dir(name, version)
+    with lock_ref(name, version):
+        try:
+            copied, skipped, copied_bytes = copy_weights(src, dst)
+        except (OSError, RuntimeError) as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+
+        add_policy(
+            name=name,
+            image=policy["image"],
+            version=version,
+            path=str(dst),
+            repo=policy.get("repo"),
+            revision=policy.get("revision"),
+            ref=policy.get("ref"),
+        )
+        if policy.get("annotations"):
+            set_policy_annotations(name, version, policy["annotations"])
+
+    print(f"[green]✓[/green] Copied {copied} file(s) ({format_size(copied_bytes)}), {skipped} already present")
+    print(f"[bold green]COPIED policy[/bold green] {name}:{version} from {source}")
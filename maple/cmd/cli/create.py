@@ -0,0 +1,62 @@
+"""
+Create command for the MAPLE CLI.
+
+This module registers a custom policy, such as a fine-tuned checkpoint,
+in the local store by assembling it from a Modelfile: a pulled base
+policy plus extra weight files, config.json overrides and labels. The
+result can be served, listed and removed like a pulled policy.
+
+Commands:
+- create: Build a policy from a Modelfile
+"""
+
+import typer
+from rich import print
+from pathlib import Path
+
+from maple.utils.misc import format_size
+from maple.utils.modelfile import parse_modelfile, create_policy, PolicyExistsError
+
+def create(
+    name: str = typer.Argument(..., help="Policy to create (e.g., openvla:kitchen-ft, or kitchen-ft for the base backend)"),
+    file: Path = typer.Option(Path("Modelfile"), "--file", "-f", help="Path to the Modelfile"),
+    force: bool = typer.Option(False, "--force", help="Replace the policy if it already exists"),
+) -> None:
+    """
+    Build a policy from a Modelfile.
+
+    The base policy named by FROM must already be pulled. Its files are
+    hard-linked where possible, so the new policy only takes extra disk
+    space for the weights the Modelfile adds. Relative WEIGHTS paths are
+    resolved against the Modelfile's directory.
+
+    :param name: 'backend:version', or a bare version of the base backend.
+    :param file: Path to the Modelfile.
+    :param force: If True, replace an existing policy of the same name.
+    """
+    try:
+        modelfile = parse_modelfile(file.read_text(), base_dir=file.resolve().parent)
+    except OSError as e:
+        print(f"[red]Error:[/red] Cannot read {file}: {e}")
+        raise typer.Exit(1)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {file}: {e}")
+        raise typer.Exit(1)
+
+    added = {}
+
+    def progress(rel: str, completed: int, total: int) -> None:
+        if completed == total:
+            added[rel] = total
+
+    try:
+        policy = create_policy(name, modelfile, overwrite=force, progress=progress)
+    except PolicyExistsError as e:
+        print(f"[red]Error:[/red] {e}. Use --force to replace it")
+        raise typer.Exit(1)
+    except (ValueError, OSError) as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    print(f"[green]✓[/green] Assembled {len(added)} file(s) ({format_size(sum(added.values()))}) from {modelfile.base}")
+    print(f"[bold green]CREATED policy[/bold green] {policy['name']}:{policy['version']}")
@@ -8,11 +8,13 @@ users quickly identify and resolve configuration problems.
 Checks performed:
 - Docker daemon availability and permissions
 - GPU/CUDA availability and driver version
+- Whether pulled policies fit in GPU memory
 - Disk space for images and data
 - Port availability for daemon
 - MAPLE daemon status
 - Container health
 - Network connectivity
+- Storage leftovers from interrupted pulls and creates (repaired with --fix)
 """
 
 import os
@@ -24,16 +26,19 @@ from pathlib import Path
 from typing import Optional, Tuple, List, Dict, Any
 
 import typer
-from rich import print
+from rich import print, get_console
 from rich.table import Table
 from rich.panel import Panel
-from rich.console import Console
 
 from maple.utils.config import get_config
 from maple.utils.lock import is_daemon_running
+from maple.utils.misc import estimate_vram, format_size
+from maple.utils.paths import maple_home, dir_size
 from maple.state import store
+from maple.state.repair import find_issues, fix_issue
+from maple.utils.ui import info
 
-console = Console()
+console = get_console()
 
 # Create the doctor sub-application
 doctor_app = typer.Typer(no_args_is_help=False, invoke_without_command=True)
@@ -229,23 +234,93 @@ def check_gpu() -> DiagnosticResult:
         )
 
 
+def query_gpu_memory() -> List[int]:
+    """
+    Get the total memory of each NVIDIA GPU.
+    
+    :return: Memory in bytes per GPU, or an empty list if nvidia-smi is unavailable.
+    """
+    if not shutil.which("nvidia-smi"):
+        return []
+    try:
+        result = subprocess.run(
+            ["nvidia-smi", "--query-gpu=memory.total", "--format=csv,noheader,nounits"],
+            capture_output=True,
+            text=True,
+            timeout=10
+        )
+    except Exception:
+        return []
+    if result.returncode != 0:
+        return []
+    # Values are reported in MiB
+    return [int(line.strip()) * 1024 ** 2 for line in result.stdout.splitlines() if line.strip().isdigit()]
+
+
+def check_gpu_fit(gpu_memory: Optional[List[int]] = None) -> DiagnosticResult:
+    """
+    Check which pulled policies fit in the largest GPU.
+    
+    :param gpu_memory: Memory in bytes per GPU. Queried from nvidia-smi if not given.
+    """
+    from maple.backend.registry import POLICY_BACKENDS
+
+    gpu_memory = query_gpu_memory() if gpu_memory is None else gpu_memory
+    if not gpu_memory:
+        return DiagnosticResult(
+            name="GPU Fit",
+            passed=False,
+            message="Could not read GPU memory from nvidia-smi",
+        )
+
+    available = max(gpu_memory)
+    fits, too_large = [], []
+    for policy in store.list_policies():
+        backend_cls = POLICY_BACKENDS.get(policy["name"])
+        needed = estimate_vram(backend_cls.param_size if backend_cls else None)
+        if needed is None:
+            continue
+        label = f"{policy['name']}:{policy['version']} (~{format_size(needed)})"
+        (fits if needed <= available else too_large).append(label)
+
+    if not fits and not too_large:
+        return DiagnosticResult(
+            name="GPU Fit",
+            passed=True,
+            message=f"No pulled policies to check ({format_size(available)} available)",
+        )
+
+    details = "\n  ".join([f"fits: {label}" for label in fits] + [f"too large: {label}" for label in too_large])
+    if too_large:
+        return DiagnosticResult(
+            name="GPU Fit",
+            passed=False,
+            message=f"{len(too_large)} of {len(fits) + len(too_large)} pulled policies may not fit in {format_size(available)}",
+            details=details,
+            fix="Serve them on CPU with --device cpu, or load quantized weights through --mdl-kwargs",
+        )
+    return DiagnosticResult(
+        name="GPU Fit",
+        passed=True,
+        message=f"All {len(fits)} pulled policies fit in {format_size(available)}",
+        details=details,
+    )
+
+
 def check_disk_space() -> DiagnosticResult:
     """Check available disk space."""
     home = Path.home()
-    maple_dir = home / ".maple"
+    maple_dir = maple_home()
     
     try:
-        # Get disk usage for home directory
-        stat = os.statvfs(home)
+        # Get disk usage for the drive holding the MAPLE directory
+        stat = os.statvfs(maple_dir if maple_dir.exists() else home)
         free_gb = (stat.f_frsize * stat.f_bavail) / (1024**3)
         total_gb = (stat.f_frsize * stat.f_blocks) / (1024**3)
         used_pct = ((total_gb - free_gb) / total_gb) * 100
         
         # Check MAPLE directory size if it exists
-        maple_size_gb = 0
-        if maple_dir.exists():
-            maple_size = sum(f.stat().st_size for f in maple_dir.rglob("*") if f.is_file())
-            maple_size_gb = maple_size / (1024**3)
+        maple_size_gb = dir_size(maple_dir) / (1024**3)
         
         if free_gb < 10:
             return DiagnosticResult(
@@ -375,6 +450,58 @@ def check_state_db() -> DiagnosticResult:
         )
 
 
+def check_storage() -> DiagnosticResult:
+    """Check the models directory for leftovers and entries out of step with the store."""
+    try:
+        issues = find_issues()
+    except Exception as e:
+        return DiagnosticResult(
+            name="Storage",
+            passed=False,
+            message=f"Error scanning storage: {e}"
+        )
+
+    if not issues:
+        return DiagnosticResult(
+            name="Storage",
+            passed=True,
+            message="No leftovers or missing weights"
+        )
+    return DiagnosticResult(
+        name="Storage",
+        passed=False,
+        message=f"{len(issues)} issue(s) found",
+        details="\n  ".join(issue.description for issue in issues),
+        fix="Run: maple doctor --fix"
+    )
+
+
+def repair_storage(assume_yes: bool = False) -> Tuple[int, int]:
+    """
+    Fix storage issues, asking before destructive ones.
+
+    :param assume_yes: If True, apply destructive fixes without asking.
+    :return: Tuple of (issues fixed, issues skipped).
+    """
+    fixed = skipped = 0
+    for issue in find_issues():
+        if issue.destructive and not assume_yes:
+            if not typer.confirm(f"{issue.description}. Fix it?", default=False):
+                print(f"  [dim]Skipped {issue.path}[/dim]")
+                skipped += 1
+                continue
+        try:
+            print(f"  [green]✓[/green] {fix_issue(issue)}")
+            fixed += 1
+        except BlockingIOError:
+            print(f"  [yellow]Skipped {issue.path}: in use by a running pull or create[/yellow]")
+            skipped += 1
+        except OSError as e:
+            print(f"  [red]✗[/red] Could not fix {issue.path}: {e}")
+            skipped += 1
+    return fixed, skipped
+
+
 def check_python() -> DiagnosticResult:
     """Check Python version."""
     version = sys.version_info
@@ -400,12 +527,17 @@ def doctor(
     ctx: typer.Context,
     verbose: bool = typer.Option(False, "--verbose", "-v", help="Show detailed output"),
     skip_gpu: bool = typer.Option(False, "--skip-gpu", help="Skip GPU checks (faster)"),
+    fix: bool = typer.Option(False, "--fix", help="Repair storage issues found"),
+    yes: bool = typer.Option(False, "--yes", "-y", help="With --fix, apply destructive fixes without asking"),
 ) -> None:
     """
     Run system diagnostics.
     
     Checks system configuration, dependencies, and common issues.
-    Provides actionable fixes for any problems found.
+    Provides actionable fixes for any problems found. With --fix, storage
+    issues are repaired: leftovers are removed and missing directories
+    created right away, while deleting unregistered weights or store
+    entries without weights is confirmed first.
     """
     if ctx.invoked_subcommand is not None:
         return
@@ -426,12 +558,17 @@ def doctor(
     
     if not skip_gpu:
         with console.status("[bold green]Checking GPU..."):
-            results.append(check_gpu())
+            gpu_result = check_gpu()
+            results.append(gpu_result)
         
         # Only check nvidia-docker if docker is working
         if results[1].passed:
             with console.status("[bold green]Checking NVIDIA Docker (may take a moment)..."):
                 results.append(check_nvidia_docker())
+        
+        if gpu_result.passed:
+            with console.status("[bold green]Checking GPU memory for pulled policies..."):
+                results.append(check_gpu_fit())
     
     with console.status("[bold green]Checking disk space..."):
         results.append(check_disk_space())
@@ -445,6 +582,9 @@ def doctor(
     with console.status("[bold green]Checking state database..."):
         results.append(check_state_db())
     
+    with console.status("[bold green]Checking storage..."):
+        results.append(check_storage())
+    
     # Display results
     print()
     
@@ -472,7 +612,16 @@ def doctor(
     else:
         print(f"[bold yellow]{passed} passed, {failed} failed[/bold yellow]")
         print()
-        print("[dim]Run with --verbose for more details[/dim]")
+        info("[dim]Run with --verbose for more details[/dim]")
+
+    if fix:
+        print()
+        print("[bold]Repairing storage[/bold]")
+        fixed, skipped = repair_storage(assume_yes=yes)
+        if fixed or skipped:
+            print(f"[bold]{fixed} fixed, {skipped} skipped[/bold]")
+        else:
+            print("[green]Nothing to repair[/green]")
 
 
 @doctor_app.command("containers")
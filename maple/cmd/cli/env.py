@@ -2,17 +2,18 @@
 Environment management commands for the MAPLE CLI.
 
 This module provides commands for interacting with running environment
-containers. It allows users to setup tasks, reset environments, step through
-episodes, query environment information, list available tasks, and stop
-environment containers.
+containers. It allows users to launch a standalone environment, setup tasks,
+reset environments, step through episodes, query environment information,
+list available tasks, and stop environment containers.
 
 Commands:
+- run: Launch a single environment container for manual testing
 - setup: Initialize an environment with a specific task
 - reset: Reset an environment to its initial state
 - step: Execute a single action step in the environment
 - info: Display information about an environment
 - tasks: List available tasks for an environment backend
-- stop: Stop one or all environment containers
+- stop: Stop one environment, every instance of an environment, or all of them
 """
 
 import json
@@ -22,11 +23,39 @@ from rich import print
 from typing import List, Optional
 from maple.utils.config import get_config
 from maple.utils.misc import daemon_url, parse_error_response, load_kwargs
+from maple.utils.ui import info
+from maple.cmd.cli.serve import request_envs
 
 # Create the env sub-application
 # no_args_is_help=True ensures help is shown when no command is given
 env_app = typer.Typer(no_args_is_help=True)
 
+@env_app.command("run")
+def run_env(
+    name: str = typer.Argument(..., help="Environment name (e.g., libero)"),
+    host_port: Optional[int] = typer.Option(None, "--host-port", "-p", help="Host port to bind the environment to"),
+    device: str = typer.Option(None, "--device", "-d"),
+    port: int = typer.Option(None, "--port"),
+) -> None:
+    """
+    Launch a single environment container for manual testing.
+    
+    Starts one instance of the environment without a policy, so it can be
+    driven by hand with 'maple env setup/reset/step' or by an external
+    client on its port. The daemon tracks it like any other environment,
+    so it shows up in 'maple ps env' until stopped.
+    
+    :param name: Environment name.
+    :param host_port: Optional host port for the environment server.
+    :param device: Device to run the environment on (e.g., 'cuda:0', 'cpu').
+    :param port: Daemon port number.
+    """
+    data = request_envs(name, port=port, device=device, num_envs=1, host_port=host_port)
+    env_id = data["env_ids"][0]
+    print(f"[green]✓ Running env:[/green] {env_id} on port {data['ports'][0]}")
+    info(f"Set up a task with: maple env setup {env_id} --task <suite/index>")
+    info(f"Stop it with: maple env stop {env_id}")
+
 @env_app.command("setup")
 def setup_env(
     env_id: str = typer.Argument(..., help="Environment ID (e.g., libero-x1y2z3w4)"),
@@ -279,18 +308,19 @@ def env_tasks(
 
 @env_app.command("stop")
 def stop_env(
-    env_id: str = typer.Argument(..., help="Environment ID (e.g., libero-x1y2z3w4)"),
+    env_id: Optional[str] = typer.Argument(None, help="Environment ID or name (e.g., libero-x1y2z3w4 or libero)"),
     port: int = typer.Option(None, "--port"),
 ) -> None:
     """
-    Stop one or all environment containers.
+    Stop one environment, every instance of an environment, or all of them.
     
-    Stops environment containers managed by the daemon. If env_id is provided,
-    stops only that specific environment. If env_id is None, stops all running
-    environment containers.
+    Stops environment containers managed by the daemon. If env_id is an
+    environment ID, stops only that instance. If it is an environment name
+    such as 'libero', stops every instance of it. If env_id is None, stops
+    all running environment containers.
     
     :param port: Daemon port number.
-    :param env_id: Optional identifier of specific environment to stop.
+    :param env_id: Optional identifier or name of the environment to stop.
     """
     config = get_config()
     # Use config default if port not specified
@@ -306,11 +336,12 @@ def stop_env(
         
         print("[green]All env stopped[/green]")
     else:
-        # Stop specific environment container
-        r = requests.post(f"{daemon_url(port)}/env/stop/{env_id}", params={"env_id": env_id})
+        # Stop a specific environment container, or every instance of a name
+        r = requests.post(f"{daemon_url(port)}/env/stop/{env_id}")
 
         if r.status_code != 200:
             print(f"[red]Error:[/red] {parse_error_response(r)}")
             raise typer.Exit(1)
         
-        print(f"[green]Env {env_id} stopped[/green]")
\ No newline at end of file
+        for stopped in r.json().get("stopped", []):
+            print(f"[green]Env {stopped} stopped[/green]")
\ No newline at end of file
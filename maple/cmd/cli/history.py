@@ -0,0 +1,54 @@
+"""
+History command for the MAPLE CLI.
+
+This module shows when each version of a policy was pulled or removed and
+which commit it pointed at, to help answer "what changed when the model
+started behaving differently?". Entries are recorded by the local store
+and capped per policy name.
+
+Commands:
+- history: Print the pull and removal history of a policy
+"""
+
+import time
+import typer
+from rich import print
+from rich.table import Table
+
+from maple.state.store import list_policy_history
+from maple.cmd.cli.completion import complete_policy_spec
+
+def history(
+    model: str = typer.Argument(..., help="Policy name or name:version (e.g., openvla or openvla:7b)", autocompletion=complete_policy_spec),
+) -> None:
+    """
+    Print the pull and removal history of a policy.
+
+    A bare name shows every version; name:version shows just that one.
+
+    :param model: Policy name or specification.
+    """
+    name, _, version = model.partition(":")
+    entries = list_policy_history(name, version or None)
+
+    if not entries:
+        print(f"[dim]No history for {model}[/dim]")
+        return
+
+    table = Table(title=f"History of {model}")
+    table.add_column("TIME")
+    table.add_column("VERSION")
+    table.add_column("EVENT")
+    table.add_column("REVISION")
+    table.add_column("REPO")
+
+    for entry in entries:
+        table.add_row(
+            time.strftime("%Y-%m-%d %H:%M:%S", time.localtime(entry["at"])),
+            entry["version"],
+            "[red]remove[/red]" if entry["event"] == "remove" else "pull",
+            (entry["revision"] or "-")[:12],
+            entry["repo"] or "-",
+        )
+
+    print(table)
@@ -2,62 +2,182 @@
 List commands for the MAPLE CLI.
 
 This module provides commands for listing available resources managed by
-the MAPLE daemon. It allows users to view registered policies and environments
-that are available for use in evaluations.
+MAPLE. It allows users to view registered policies and environments that are
+available for use in evaluations. Listings are read from local storage, so
+they work without a running daemon; --remote asks the daemon on another
+machine instead.
 
 Commands:
-- policy: List all available policy containers
-- env: List all available environment containers
+- policy: List pulled policies, optionally one page at a time or grouped by name
+- env: List pulled environments with image size and pull time
 """
 
 import typer 
-import requests
 from rich import print
-from maple.utils.config import get_config
-from maple.utils.misc import daemon_url
+from rich.table import Table
+from typing import Dict, List, Optional
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.state import listing
+from maple.utils.misc import format_size, format_ago
+from maple.utils.ui import info, NAME_STYLE, SIZE_STYLE
 
 # Create the list sub-application
 # no_args_is_help=True ensures help is shown when no command is given
 list_app = typer.Typer(no_args_is_help=True)
 
+def print_policy_groups(groups: List[Dict]) -> None:
+    """
+    Print policies grouped by name, one row per name followed by its versions.
+    
+    :param groups: Groups with name, versions and the deduplicated size
+                   of all versions.
+    """
+    if not groups:
+        print("[yellow]No policies installed[/yellow]")
+        info("Pull one with: maple pull policy openvla:7b")
+        return
+
+    table = Table()
+    table.add_column("NAME", style=NAME_STYLE)
+    table.add_column("REPO")
+    table.add_column("SIZE", justify="right", style=SIZE_STYLE)
+    table.add_column("MODIFIED")
+    table.add_column("LAST USED")
+    for group in groups:
+        versions = group["versions"]
+        table.add_row(
+            f"[bold]{group['name']}[/bold] ({len(versions)})",
+            "",
+            f"[bold]{format_size(group['size'])}[/bold]",
+            "",
+            "",
+        )
+        for policy in versions:
+            table.add_row(
+                f"  :{policy['version']}",
+                policy.get("repo") or "-",
+                format_size(policy.get("size")),
+                format_ago(policy.get("pulled_at")),
+                format_ago(policy.get("last_used")) if policy.get("last_used") else "never",
+            )
+    print(table)
+    print("Name sizes count weights shared between versions once")
+
 @list_app.command("policy")
-def list_policy(port: int = typer.Option(None, "--port")) -> None:
+def list_policy(
+    limit: Optional[int] = typer.Option(None, "--limit", min=1, help="Policies per page (default: all)"),
+    page: int = typer.Option(1, "--page", min=1, help="Page to show when --limit is set"),
+    all_tags: bool = typer.Option(False, "--all-tags", help="Group versions under each policy name"),
+    remote: Optional[str] = typer.Option(None, "--remote", help="List the policies of the daemon on this host instead"),
+    port: int = typer.Option(None, "--port", help="Daemon port with --remote"),
+) -> None:
     """
-    List all available policy containers.
+    List all pulled policies.
     
-    Queries the daemon and displays all registered policy containers that
-    are available for running evaluations. Shows policy identifiers and
-    their current status.
+    Reads local storage and displays every pulled policy with the repo it
+    was downloaded from, the size of its weights, when it was pulled and
+    when the daemon last served it. With --remote, the daemon on that host
+    is asked instead. With --limit, policies are
+    sorted by name and shown one page at a time. With --all-tags, each
+    name is shown once with its versions below it and the disk space they
+    take together, counting weights shared between versions once.
     
-    :param port: Daemon port number.
+    :param limit: Maximum number of policies per page.
+    :param page: 1-based page number.
+    :param all_tags: If True, group versions by policy name.
+    :param remote: Host of a remote daemon to query instead of local storage.
+    :param port: Daemon port number, used with remote.
     """
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
+    if all_tags and limit is not None:
+        print("[red]Error:[/red] --all-tags cannot be combined with --limit")
+        raise typer.Exit(1)
+
+    offset = (page - 1) * limit if limit is not None else 0
+
+    try:
+        if remote:
+            data = Client.from_config(port, host=remote).list_policies(limit=limit, offset=offset, group=all_tags)
+        else:
+            data = listing.list_policies(limit=limit, offset=offset, group=all_tags)
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
     
-    # Request policy list from daemon
-    r = requests.get(f"{daemon_url(port)}/policy/list")
+    if all_tags:
+        print_policy_groups(data["groups"])
+        return
+
+    policies = data["policies"]
+    total = data.get("total", len(policies))
+    if not policies:
+        if total:
+            print(f"[yellow]No policies on page {page}[/yellow] ({total} total)")
+        else:
+            print("[yellow]No policies installed[/yellow]")
+            info("Pull one with: maple pull policy openvla:7b")
+        return
     
     # Display policies
-    print("[yellow]Policies:[/yellow]", r.json()["policies"])
+    table = Table()
+    table.add_column("NAME", style=NAME_STYLE)
+    table.add_column("REPO")
+    table.add_column("SIZE", justify="right", style=SIZE_STYLE)
+    table.add_column("MODIFIED")
+    table.add_column("LAST USED")
+    for policy in policies:
+        table.add_row(
+            f"{policy['name']}:{policy['version']}",
+            policy.get("repo") or "-",
+            format_size(policy.get("size")),
+            format_ago(policy.get("pulled_at")),
+            format_ago(policy.get("last_used")) if policy.get("last_used") else "never",
+        )
+    print(table)
+
+    if limit is not None:
+        start = (page - 1) * limit + 1
+        print(f"Showing {start}-{start + len(policies) - 1} of {total}")
 
 @list_app.command("env")
-def list_env(port: int = typer.Option(None, "--port")) -> None:
+def list_env(
+    remote: Optional[str] = typer.Option(None, "--remote", help="List the environments of the daemon on this host instead"),
+    port: int = typer.Option(None, "--port", help="Daemon port with --remote"),
+) -> None:
     """
-    List all available environment containers.
+    List all pulled environments.
     
-    Queries the daemon and displays all registered environment containers
-    that are available for running evaluations. Shows environment identifiers
-    and their current status.
+    Reads local storage and displays every pulled environment with the
+    size of its Docker image and when it was pulled. With --remote, the
+    daemon on that host is asked instead.
     
-    :param port: Daemon port number.
+    :param remote: Host of a remote daemon to query instead of local storage.
+    :param port: Daemon port number, used with remote.
     """
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
-    
-    # Request environment list from daemon
-    r = requests.get(f"{daemon_url(port)}/env/list")
+    try:
+        if remote:
+            envs = Client.from_config(port, host=remote).list_envs()["envs"]
+        else:
+            envs = listing.list_envs()["envs"]
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    if not envs:
+        print("[yellow]No environments installed[/yellow]")
+        info("Pull one with: maple pull env libero")
+        return
     
     # Display environments
-    print("[yellow]Envs:[/yellow]", r.json()["envs"])
\ No newline at end of file
+    table = Table()
+    table.add_column("NAME", style=NAME_STYLE)
+    table.add_column("SIZE", justify="right", style=SIZE_STYLE)
+    table.add_column("MODIFIED")
+    for env in envs:
+        table.add_row(env["name"], format_size(env.get("size")), format_ago(env.get("pulled_at")))
+    print(table)
\ No newline at end of file
@@ -0,0 +1,167 @@
+"""
+Lockfile commands for the MAPLE CLI.
+
+This module pins a pulled policy to its exact content so a deployment can
+be reproduced and audited. A lockfile records the policy ref, the
+HuggingFace repo and commit it was pulled from, and the size and SHA-256
+digest of every weight file, plus a digest over that file list.
+
+Commands:
+- lock: Print or write the lockfile for a pulled policy
+- verify-lock: Check that the local policy matches a lockfile exactly
+"""
+
+import json
+import typer
+import hashlib
+from rich import print
+from pathlib import Path
+from typing import Dict, List, Optional
+
+from maple.utils.spec import parse_versioned
+from maple.utils.download import file_sha256
+from maple.state.store import get_policy
+from maple.cmd.cli.completion import complete_policy_spec
+
+LOCKFILE_VERSION = 1
+
+def _weight_files(root: Path) -> List[Dict]:
+    """
+    List the files of a weights directory with their sizes and digests.
+
+    Partial downloads ('.part') are skipped.
+
+    :param root: Weights directory.
+    :return: File entries sorted by relative path.
+    """
+    files = []
+    for path in sorted(p for p in root.rglob("*") if p.is_file()):
+        if path.name.endswith(".part"):
+            continue
+        files.append({
+            "path": path.relative_to(root).as_posix(),
+            "size": path.stat().st_size,
+            "digest": f"sha256:{file_sha256(path)}",
+        })
+    return files
+
+def _files_digest(files: List[Dict]) -> str:
+    """
+    Digest a file list so two lockfiles can be compared at a glance.
+
+    :param files: File entries as returned by _weight_files.
+    :return: Digest in 'sha256:<hex>' form.
+    """
+    canonical = json.dumps(files, sort_keys=True, separators=(",", ":"))
+    return f"sha256:{hashlib.sha256(canonical.encode()).hexdigest()}"
+
+def build_lockfile(name: str, version: str) -> Dict:
+    """
+    Build the lockfile for a pulled policy.
+
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy model.
+    :return: Lockfile dictionary.
+    """
+    policy = get_policy(name, version)
+    if policy is None:
+        raise ValueError(f"Policy {name}:{version} is not pulled")
+
+    path = Path(policy["path"])
+    if not path.is_dir():
+        raise ValueError(f"Weights for {name}:{version} not found at {path}")
+
+    files = _weight_files(path)
+    return {
+        "lockfile_version": LOCKFILE_VERSION,
+        "policy": f"{name}:{version}",
+        "repo": policy.get("repo"),
+        "revision": policy.get("revision"),
+        "image": policy.get("image"),
+        "digest": _files_digest(files),
+        "files": files,
+    }
+
+def diff_lockfile(lockfile: Dict) -> List[str]:
+    """
+    Compare a lockfile against the local store.
+
+    :param lockfile: Lockfile dictionary as written by 'maple lock'.
+    :return: One message per difference. Empty if the policy matches exactly.
+    """
+    name, version = parse_versioned(lockfile["policy"])
+    current = build_lockfile(name, version)
+    drift = []
+
+    for key in ("repo", "revision", "image"):
+        if lockfile.get(key) != current[key]:
+            drift.append(f"{key}: expected {lockfile.get(key)}, found {current[key]}")
+
+    expected = {f["path"]: f for f in lockfile.get("files", [])}
+    found = {f["path"]: f for f in current["files"]}
+
+    for path, entry in expected.items():
+        if path not in found:
+            drift.append(f"{path}: missing")
+        elif found[path]["size"] != entry["size"]:
+            drift.append(f"{path}: size {found[path]['size']} != {entry['size']}")
+        elif found[path]["digest"] != entry["digest"]:
+            drift.append(f"{path}: digest {found[path]['digest']} != {entry['digest']}")
+    for path in sorted(set(found) - set(expected)):
+        drift.append(f"{path}: not in lockfile")
+
+    return drift
+
+def lock(
+    model: str = typer.Argument(..., help="Policy to lock (name:version)", autocompletion=complete_policy_spec),
+    output: Optional[Path] = typer.Option(None, "--output", "-o", help="Write the lockfile here instead of stdout"),
+) -> None:
+    """
+    Print the lockfile for a pulled policy.
+
+    Hashes every weight file, so this can take a while for large models.
+
+    :param model: Policy specification (name:version).
+    :param output: Optional path to write the lockfile to.
+    """
+    try:
+        name, version = parse_versioned(model)
+        lockfile = build_lockfile(name, version)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    content = json.dumps(lockfile, indent=2)
+    if output is None:
+        # Plain stdout so the JSON can be redirected as-is
+        typer.echo(content)
+        return
+
+    output.write_text(content + "\n")
+    print(f"[green]✓[/green] Wrote lockfile for {lockfile['policy']} to {output}")
+
+def verify_lock(
+    file: Path = typer.Argument(..., help="Lockfile written by 'maple lock'"),
+) -> None:
+    """
+    Check that a pulled policy matches a lockfile exactly.
+
+    Exits with status 1 and lists every difference if the repo, revision,
+    image or any weight file has drifted.
+
+    :param file: Path to the lockfile.
+    """
+    try:
+        lockfile = json.loads(file.read_text())
+        drift = diff_lockfile(lockfile)
+    except (OSError, json.JSONDecodeError, KeyError, ValueError) as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    if drift:
+        print(f"[red]✗ {lockfile['policy']} does not match {file}[/red]")
+        for line in drift:
+            print(f"  {line}")
+        raise typer.Exit(1)
+
+    print(f"[green]✓[/green] {lockfile['policy']} matches {file}")
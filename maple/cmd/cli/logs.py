@@ -1,35 +1,247 @@
 """
 Logs command for MAPLE CLI.
 
-This module provides easy access to container logs for debugging.
-Users can view logs from policy and environment containers without
-needing to know Docker container IDs.
+This module provides easy access to daemon, policy and container logs for
+debugging. The daemon writes its own log to ~/.maple/logs/server.log and
+records about a policy to ~/.maple/logs/<model>.log; container output is
+read from Docker without needing to know container IDs.
 
 Commands:
-- logs <id>: View logs for a specific policy or environment
+- logs [MODEL]: Show the daemon log, or a policy's log
+- logs show <id>: View logs for a specific policy or environment container
 - logs daemon: View MAPLE daemon logs
 - logs list: List all available log sources
+- logs clear: Delete the daemon's log files
 """
 
 import os
 import sys
+import json
+import time
 import subprocess
 from pathlib import Path
-from typing import Optional
+from datetime import datetime, timedelta
+from typing import Any, Dict, Iterable, Iterator, List, Optional
 
 import typer
-from rich import print
+from rich import print, get_console
 from rich.table import Table
-from rich.console import Console
-from rich.syntax import Syntax
+from rich.markup import escape
 
-from maple.utils.config import get_config
 from maple.state import store
+from maple.utils.misc import parse_duration
+from maple.utils.logging import DATE_FORMAT, logs_dir, server_log_file, model_log_file
 
-console = Console()
+console = get_console()
 
 # Create the logs sub-application
-logs_app = typer.Typer(no_args_is_help=True)
+logs_app = typer.Typer(no_args_is_help=False)
+
+# Levels shown by 'maple logs daemon --errors'
+_ERROR_LEVELS = ("ERROR", "CRITICAL")
+
+# How often --follow checks the file for new lines
+_FOLLOW_INTERVAL = 0.25
+
+
+def parse_since(value: str, now: Optional[datetime] = None) -> datetime:
+    """
+    Parse a --since value into a point in time.
+    
+    :param value: A duration back from now (e.g., '10m', '2h') or a local
+                 timestamp (e.g., '2026-01-31 14:00', '2026-01-31T14:00:00').
+    :param now: Reference time for durations. Defaults to the current time.
+    :return: Earliest time of the lines to show.
+    :raises ValueError: If the value is neither a duration nor a timestamp.
+    """
+    try:
+        return datetime.fromisoformat(value.strip())
+    except ValueError:
+        pass
+    try:
+        seconds = parse_duration(value)
+    except ValueError:
+        seconds = None
+    if seconds is None:
+        raise ValueError(f"Invalid --since value: '{value}' (expected e.g. 10m, 2h or 2026-01-31 14:00)")
+    return (now or datetime.now()) - timedelta(seconds=seconds)
+
+
+def json_record(line: str) -> Optional[Dict[str, Any]]:
+    """
+    Parse a line written with --log-format json.
+    
+    :param line: Line from a MAPLE log file.
+    :return: The record, or None if the line is not a JSON log record.
+    """
+    if not line.startswith("{"):
+        return None
+    try:
+        record = json.loads(line)
+    except ValueError:
+        return None
+    return record if isinstance(record, dict) and "ts" in record else None
+
+
+def line_time(line: str) -> Optional[datetime]:
+    """
+    Read the timestamp at the start of a log line.
+    
+    JSON records carry a UTC time, which is converted to local time to
+    match text lines and --since.
+    
+    :param line: Line from a MAPLE log file.
+    :return: Time the record was written, or None for continuation lines
+            such as tracebacks.
+    """
+    record = json_record(line)
+    try:
+        if record is not None:
+            return datetime.fromisoformat(record["ts"]).astimezone().replace(tzinfo=None)
+        return datetime.strptime(line[:19], DATE_FORMAT)
+    except (TypeError, ValueError):
+        return None
+
+
+def line_level(line: str) -> Optional[str]:
+    """
+    Read the level of a log line.
+    
+    :param line: Line from a MAPLE log file.
+    :return: Level name (e.g., 'INFO'), or None for continuation lines.
+    """
+    record = json_record(line)
+    if record is not None:
+        return record.get("level")
+    parts = line.split(" | ", 2)
+    if len(parts) < 3 or line_time(line) is None:
+        return None
+    return parts[1].strip()
+
+
+def filter_lines(
+    lines: Iterable[str],
+    since: Optional[datetime] = None,
+    levels: Optional[Iterable[str]] = None,
+) -> Iterator[str]:
+    """
+    Select log lines by time and level.
+    
+    Continuation lines (tracebacks, multi-line messages) follow the
+    decision made for the record they belong to.
+    
+    :param lines: Lines of a log file, in order.
+    :param since: Drop records written before this time.
+    :param levels: Only keep records at these levels. None keeps all.
+    :return: The selected lines.
+    """
+    levels = set(levels) if levels is not None else None
+    keep = since is None and levels is None
+    for line in lines:
+        stamp = line_time(line)
+        if stamp is not None:
+            keep = (since is None or stamp >= since) and \
+                   (levels is None or line_level(line) in levels)
+        if keep:
+            yield line
+
+
+def read_log(
+    path: Path,
+    tail: int = 100,
+    since: Optional[datetime] = None,
+    levels: Optional[Iterable[str]] = None,
+) -> List[str]:
+    """
+    Read the last lines of a log file.
+    
+    :param path: Log file to read.
+    :param tail: Number of lines to return. 0 returns all selected lines.
+    :param since: Drop records written before this time.
+    :param levels: Only keep records at these levels. None keeps all.
+    :return: Selected lines, oldest first.
+    """
+    with open(path, "r", errors="replace") as f:
+        lines = list(filter_lines(f, since=since, levels=levels))
+    return lines[-tail:] if tail else lines
+
+
+def follow_log(path: Path, levels: Optional[Iterable[str]] = None) -> None:
+    """
+    Print lines as they are appended to a log file, like 'tail -f'.
+    
+    Starts at the current end of the file. If the file is replaced or
+    truncated (e.g., on rotation), reading restarts at its beginning.
+    Runs until interrupted.
+    
+    :param path: Log file to follow.
+    :param levels: Only print records at these levels. None prints all.
+    """
+    f = open(path, "r", errors="replace")
+    f.seek(0, 2)
+    keep = levels is None
+    try:
+        while True:
+            line = f.readline()
+            if line:
+                if levels is not None and line_time(line) is not None:
+                    keep = line_level(line) in levels
+                if keep:
+                    sys.stdout.write(line)
+                    sys.stdout.flush()
+                continue
+            time.sleep(_FOLLOW_INTERVAL)
+            try:
+                stat = path.stat()
+            except FileNotFoundError:
+                continue  # Between rotation's rename and the new file
+            if stat.st_ino != os.fstat(f.fileno()).st_ino:
+                # Rotated: the path now names a new file
+                f.close()
+                f = open(path, "r", errors="replace")
+            elif stat.st_size < f.tell():
+                # Truncated in place
+                f.seek(0)
+    except KeyboardInterrupt:
+        print("\n[dim]Stopped following logs[/dim]")
+    finally:
+        f.close()
+
+
+def show_log_file(
+    path: Path,
+    follow: bool = False,
+    tail: int = 100,
+    since: Optional[str] = None,
+    levels: Optional[Iterable[str]] = None,
+) -> None:
+    """
+    Print the end of a log file and optionally keep following it.
+    
+    :param path: Log file to show.
+    :param follow: If True, keep printing new lines until interrupted.
+    :param tail: Number of lines to show from the end. 0 shows all.
+    :param since: Only show records written after this time (duration or timestamp).
+    :param levels: Only show records at these levels. None shows all.
+    """
+    try:
+        start = parse_since(since) if since else None
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    try:
+        lines = read_log(path, tail=tail, since=start, levels=levels)
+    except OSError as e:
+        print(f"[red]Error reading {path}: {e}[/red]")
+        raise typer.Exit(1)
+
+    sys.stdout.writelines(lines)
+    sys.stdout.flush()
+
+    if follow:
+        print(f"[dim]Following {path}... (Ctrl+C to stop)[/dim]", file=sys.stderr)
+        follow_log(path, levels=levels)
 
 
 def get_container_id_by_maple_id(maple_id: str) -> Optional[str]:
@@ -130,70 +342,23 @@ def logs_show(
 @logs_app.command("daemon")
 def logs_daemon(
     follow: bool = typer.Option(False, "--follow", "-f", help="Follow log output"),
-    tail: int = typer.Option(100, "--tail", "-n", help="Number of lines to show"),
-    errors: bool = typer.Option(False, "--errors", "-e", help="Show only errors (stderr)"),
+    tail: int = typer.Option(100, "--tail", "-n", help="Number of lines to show (0 = all)"),
+    since: Optional[str] = typer.Option(None, "--since", help="Only show logs newer than a duration (10m) or timestamp"),
+    errors: bool = typer.Option(False, "--errors", "-e", help="Show only errors"),
 ) -> None:
     """
     View MAPLE daemon logs.
     
-    Shows logs from /tmp/vla.out and /tmp/vla.err when running in detached mode.
+    Shows ~/.maple/logs/server.log, written by the daemon whether it runs
+    in the foreground or detached.
     """
-    stdout_log = Path("/tmp/vla.out")
-    stderr_log = Path("/tmp/vla.err")
-    
-    if not stdout_log.exists() and not stderr_log.exists():
+    path = server_log_file()
+    if not path.exists():
         print("[yellow]No daemon logs found[/yellow]")
-        print("[dim]Logs are only created when running with --detach[/dim]")
-        print("[dim]If running in foreground, logs appear in the terminal[/dim]")
+        print(f"[dim]The daemon writes {path} once started with 'maple serve'[/dim]")
         return
-    
-    if follow:
-        # Use tail -f to follow logs
-        log_file = stderr_log if errors else stdout_log
-        if not log_file.exists():
-            print(f"[yellow]Log file not found: {log_file}[/yellow]")
-            return
-        
-        print(f"[dim]Following {log_file}... (Ctrl+C to stop)[/dim]")
-        try:
-            subprocess.run(["tail", "-f", str(log_file)])
-        except KeyboardInterrupt:
-            print("\n[dim]Stopped following logs[/dim]")
-        except FileNotFoundError:
-            # Fallback if tail not available
-            with open(log_file, "r") as f:
-                # Move to end
-                f.seek(0, 2)
-                while True:
-                    line = f.readline()
-                    if line:
-                        print(line, end="")
-                    else:
-                        import time
-                        time.sleep(0.1)
-    else:
-        # Show recent logs
-        if errors:
-            files = [stderr_log] if stderr_log.exists() else []
-        else:
-            files = [f for f in [stdout_log, stderr_log] if f.exists()]
-        
-        for log_file in files:
-            if log_file == stderr_log:
-                print("[bold red]═══ Errors (stderr) ═══[/bold red]")
-            else:
-                print("[bold cyan]═══ Output (stdout) ═══[/bold cyan]")
-            
-            try:
-                with open(log_file, "r") as f:
-                    lines = f.readlines()
-                    for line in lines[-tail:]:
-                        print(line, end="")
-            except Exception as e:
-                print(f"[red]Error reading {log_file}: {e}[/red]")
-        
-        if not files:
-            print("[yellow]No log files found[/yellow]")
+
+    show_log_file(path, follow=follow, tail=tail, since=since, levels=_ERROR_LEVELS if errors else None)
 
 
 @logs_app.command("list")
@@ -201,7 +366,8 @@ def logs_list() -> None:
     """
     List all available log sources.
     
-    Shows all running containers and their IDs for use with 'maple logs show'.
+    Shows the daemon and per-policy log files, and all running containers
+    and their IDs for use with 'maple logs show'.
     """
     # List MAPLE containers
     containers = store.list_containers()
@@ -225,23 +391,18 @@ def logs_list() -> None:
     else:
         print("[yellow]No MAPLE containers registered[/yellow]")
     
-    # Check for daemon logs
+    # Check for daemon and policy logs
     print()
-    print("[bold]Daemon Logs:[/bold]")
-    
-    stdout_log = Path("/tmp/vla.out")
-    stderr_log = Path("/tmp/vla.err")
-    
-    if stdout_log.exists() or stderr_log.exists():
-        if stdout_log.exists():
-            size = stdout_log.stat().st_size / 1024
-            print(f"  [green]✓[/green] stdout: {stdout_log} ({size:.1f} KB)")
-        if stderr_log.exists():
-            size = stderr_log.stat().st_size / 1024
-            print(f"  [green]✓[/green] stderr: {stderr_log} ({size:.1f} KB)")
-        print(f"  [dim]View with: maple logs daemon[/dim]")
+    print("[bold]Log Files:[/bold]")
+    
+    files = sorted(logs_dir().glob("*.log")) if logs_dir().is_dir() else []
+    if files:
+        for log_file in files:
+            size = log_file.stat().st_size / 1024
+            cmd = "maple logs" if log_file == server_log_file() else f"maple logs {log_file.stem}"
+            print(f"  [green]✓[/green] {log_file} ({size:.1f} KB)  [dim]{cmd}[/dim]")
     else:
-        print("  [dim]No daemon logs (running in foreground or not started)[/dim]")
+        print("  [dim]No log files (daemon not started yet)[/dim]")
 
 
 @logs_app.command("clear")
@@ -249,12 +410,9 @@ def logs_clear(
     force: bool = typer.Option(False, "--force", "-f", help="Skip confirmation"),
 ) -> None:
     """
-    Clear daemon log files.
+    Clear daemon and policy log files.
     """
-    stdout_log = Path("/tmp/vla.out")
-    stderr_log = Path("/tmp/vla.err")
-    
-    files = [f for f in [stdout_log, stderr_log] if f.exists()]
+    files = sorted(logs_dir().glob("*.log*")) if logs_dir().is_dir() else []
     
     if not files:
         print("[yellow]No log files to clear[/yellow]")
@@ -274,32 +432,47 @@ def logs_clear(
             print(f"[red]Error clearing {log_file}: {e}[/red]")
 
 
-# Default command when running 'maple logs <id>'
+# Default command when running 'maple logs [MODEL]'
 @logs_app.callback(invoke_without_command=True)
 def logs_default(
     ctx: typer.Context,
-    container_id: Optional[str] = typer.Argument(None, help="Policy/Env ID to show logs for"),
+    model: Optional[str] = typer.Argument(None, help="Policy name (e.g., openvla) or Policy/Env container ID"),
     follow: bool = typer.Option(False, "--follow", "-f", help="Follow log output"),
-    tail: int = typer.Option(100, "--tail", "-n", help="Number of lines to show"),
+    tail: int = typer.Option(100, "--tail", "-n", help="Number of lines to show (0 = all)"),
+    since: Optional[str] = typer.Option(None, "--since", help="Only show logs newer than a duration (10m) or timestamp"),
 ) -> None:
     """
-    View container logs.
+    View daemon, policy or container logs.
     
-    Quick access to policy and environment container logs.
+    Without an argument, shows the daemon log. With a policy name, shows
+    that policy's log; anything else is looked up as a container.
     
     Examples:
+        maple logs
+        maple logs openvla -f
+        maple logs --since 10m
         maple logs openvla-7b-a1b2c3
-        maple logs libero-x1y2z3w4 -f
     """
     if ctx.invoked_subcommand is not None:
         return
     
-    if container_id:
-        # Direct shortcut: maple logs <id>
-        logs_show(container_id, follow=follow, tail=tail)
-    else:
-        # No args, show help
-        print("[yellow]Usage: maple logs <container_id>[/yellow]")
-        print()
-        print("Use [cyan]maple logs list[/cyan] to see available containers")
-        print("Use [cyan]maple logs daemon[/cyan] to view daemon logs")
+    if model is None:
+        path = server_log_file()
+        if not path.exists():
+            print("[yellow]No daemon logs found[/yellow]")
+            print("Start the daemon with [cyan]maple serve[/cyan]")
+            print("Use [cyan]maple logs list[/cyan] to see available logs")
+            return
+        show_log_file(path, follow=follow, tail=tail, since=since)
+        return
+
+    path = model_log_file(model)
+    if path.exists():
+        show_log_file(path, follow=follow, tail=tail, since=since)
+        return
+
+    # Not a policy with a log file: fall back to container logs
+    if since:
+        print(f"[red]Error:[/red] No log file for '{escape(model)}'; --since only applies to daemon and policy logs")
+        raise typer.Exit(1)
+    logs_show(model, follow=follow, tail=tail)
@@ -10,10 +10,8 @@ Commands:
 """
 
 import typer 
-import requests
 from rich import print
-from maple.utils.config import get_config
-from maple.utils.misc import daemon_url, parse_error_response
+from maple.api import Client, DaemonError, DaemonNotRunning
 
 # Create the policy sub-application
 # no_args_is_help=True ensures help is shown when no command is given
@@ -34,19 +32,17 @@ def policy_info(
     :param port: Daemon port number.
     :param policy_id: Identifier of the policy container.
     """
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
-    
     # Request policy info from daemon
-    r = requests.get(f"{daemon_url(port)}/policy/info/{policy_id}")
-    
-    if r.status_code != 200:
-        print(f"[red]Error:[/red] {parse_error_response(r)}")
+    try:
+        data = Client.from_config(port).policy_info(policy_id)
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
         raise typer.Exit(1)
     
     # Display policy metadata
-    data = r.json()
     print(f"[cyan]Policy Info:[/cyan]")
     print(f"  Name: {data.get('name')}")
     print(f"  Loaded: {data.get('loaded')}")
@@ -68,21 +64,19 @@ def stop_policy(
     :param port: Daemon port number.
     :param policy_id: Identifier of the policy container to stop.
     """
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
-    
     # Validate required parameter
     if policy_id is None:
         print(f"[red]Error: Policy id is None[/red]")
         raise typer.Exit(1)
     
     # Send stop request to daemon
-    # Note: Uses env/stop endpoint (likely should be policy/stop)
-    r = requests.post(f"{daemon_url(port)}/policy/stop/{policy_id}", params={"env_id": policy_id})
-    
-    if r.status_code != 200:
-        print(f"[red]Error:[/red] {parse_error_response(r)}")
+    try:
+        Client.from_config(port).stop_policy(policy_id)
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
         raise typer.Exit(1)
     
     # Confirm successful stop
@@ -0,0 +1,78 @@
+"""
+Prune command for the MAPLE CLI.
+
+This module caps the disk space used by pulled policy weights by removing
+the least recently used policies until the models directory fits a size
+budget. Policies loaded by the daemon are never removed.
+
+Commands:
+- prune: Remove policies until model storage fits a budget
+"""
+
+import typer
+from rich import print
+from typing import Set, Tuple
+
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.utils.misc import parse_size, format_size
+from maple.state.prune import prune_to_size, models_usage
+
+def loaded_policies(port: int) -> Set[Tuple[str, str]]:
+    """
+    Ask the daemon which policies are loaded.
+
+    :param port: Daemon port number.
+    :return: Set of (name, version) pairs. Empty if the daemon is not running.
+    """
+    try:
+        policies = Client.from_config(port).ps()["policies"]
+    except DaemonNotRunning:
+        return set()
+    return {(p["backend"], p["version"]) for p in policies}
+
+def prune(
+    max_size: str = typer.Option(..., "--max-size", help="Budget for model storage (e.g., 50GB)"),
+    dry_run: bool = typer.Option(False, "--dry-run", help="Show what would be removed without removing it"),
+    port: int = typer.Option(None, "--port"),
+) -> None:
+    """
+    Remove policies until model storage fits a size budget.
+
+    Whole policies are removed, least recently used first, until the
+    models directory is at most --max-size. Policies loaded by the daemon
+    are kept even if that leaves storage over budget.
+
+    :param max_size: Size budget such as '50GB'.
+    :param dry_run: If True, only report what would be removed.
+    :param port: Daemon port number.
+    """
+    try:
+        max_bytes = parse_size(max_size)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    try:
+        in_use = loaded_policies(port)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] Could not list loaded policies: {e}")
+        raise typer.Exit(1)
+
+    usage = models_usage()
+    if usage <= max_bytes:
+        print(f"[green]Model storage {format_size(usage)} is within {format_size(max_bytes)}, nothing to prune[/green]")
+        return
+
+    removed, freed = prune_to_size(max_bytes, in_use=in_use, dry_run=dry_run)
+
+    verb = "Would remove" if dry_run else "Removed"
+    for spec in removed:
+        print(f"  {verb} {spec}")
+    print(f"[bold]{verb} {len(removed)} policy(s), freeing {format_size(freed)}[/bold]")
+
+    if usage - freed > max_bytes:
+        kept = ", ".join(f"{name}:{version}" for name, version in sorted(in_use)) or "none"
+        print(
+            f"[yellow]Warning:[/yellow] Model storage is still {format_size(usage - freed)}, "
+            f"over {format_size(max_bytes)}. Loaded policies ({kept}) and files outside pulled policies are kept"
+        )
@@ -0,0 +1,222 @@
+"""
+Process status commands for the MAPLE CLI.
+
+This module provides commands for inspecting what the daemon currently has
+loaded. Unlike `list`, which shows pulled resources, `ps` shows running
+containers and how long they will stay loaded.
+
+Commands:
+- policy: Show loaded policies with their keep-alive expiry and the act queue
+- env: Show running environments with their port and uptime
+"""
+
+import time
+import typer
+from rich import print, get_console
+from typing import Any, Callable, Dict, Optional
+from rich.table import Table
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.utils.misc import format_ago
+
+# Create the ps sub-application
+# no_args_is_help=True ensures help is shown when no command is given
+ps_app = typer.Typer(no_args_is_help=True)
+
+def _format_processor(device: Optional[str]) -> str:
+    """
+    Map a device string to a processor label.
+    
+    :param device: Device the policy is loaded on (e.g., 'cuda:0', 'cpu').
+    :return: 'gpu', 'cpu' or '-' if unknown.
+    """
+    if not device:
+        return "-"
+    return "gpu" if device.startswith("cuda") else "cpu"
+
+def _format_until(remaining: Optional[float]) -> str:
+    """
+    Describe how long a policy will stay loaded.
+    
+    :param remaining: Seconds until the policy is unloaded, or None if it never expires.
+    :return: Human readable expiry such as '4 minutes from now' or 'Forever'.
+    """
+    if remaining is None:
+        return "Forever"
+    if remaining < 1:
+        return "Unloading"
+    if remaining < 60:
+        return f"{int(remaining)} seconds from now"
+    if remaining < 3600:
+        minutes = int(remaining // 60)
+        return f"{minutes} minute{'s' if minutes != 1 else ''} from now"
+    hours = int(remaining // 3600)
+    return f"{hours} hour{'s' if hours != 1 else ''} from now"
+
+def _format_uptime(started_at: Optional[float], now: Optional[float] = None) -> str:
+    """
+    Describe how long a container has been running.
+    
+    :param started_at: Unix timestamp the container was started at.
+    :param now: Reference time. Defaults to the current time.
+    :return: Uptime such as 'Up 5 minutes', or '-' if unknown.
+    """
+    if started_at is None:
+        return "-"
+
+    elapsed = max(0.0, (now if now is not None else time.time()) - started_at)
+    for seconds, unit in ((86400, "day"), (3600, "hour"), (60, "minute")):
+        if elapsed >= seconds:
+            count = int(elapsed // seconds)
+            return f"Up {count} {unit}{'s' if count != 1 else ''}"
+    return f"Up {int(elapsed)} seconds"
+
+def _fetch(client: Client) -> Dict[str, Any]:
+    """
+    Fetch /ps, exiting with a message if the daemon cannot answer.
+    
+    :param client: Client for the daemon.
+    :return: Response of /ps.
+    """
+    try:
+        return client.ps()
+    except DaemonNotRunning as e:
+        print(f"[yellow]{e}[/yellow]")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+def _print_policies(status: Dict[str, Any]) -> None:
+    """
+    Print the loaded policy table and the act queue.
+    
+    :param status: Response of /ps.
+    """
+    policies = status.get("policies", [])
+    if not policies:
+        print("[dim]No policies loaded[/dim]")
+        return
+
+    table = Table(show_header=True, header_style="bold cyan")
+    table.add_column("Name")
+    table.add_column("ID")
+    table.add_column("Status")
+    table.add_column("Processor")
+    table.add_column("Until")
+    table.add_column("Last Used")
+
+    for policy in policies:
+        table.add_row(
+            f"{policy['backend']}:{policy['version']}",
+            policy["policy_id"],
+            policy.get("status", "unknown"),
+            _format_processor(policy.get("device")),
+            _format_until(policy.get("keep_alive_remaining")),
+            format_ago(policy.get("last_used")),
+        )
+
+    print(table)
+
+    # Older daemons do not report the queue
+    queue = status.get("act_queue")
+    if queue:
+        style = "yellow" if queue["queued"] else "dim"
+        print(
+            f"[{style}]Act requests: {queue['running']}/{queue['max_concurrent']} running, "
+            f"{queue['queued']}/{queue['max_queue']} queued[/{style}]"
+        )
+
+def _print_envs(status: Dict[str, Any]) -> None:
+    """
+    Print the running environment table.
+    
+    :param status: Response of /ps.
+    """
+    envs = status.get("envs", [])
+    if not envs:
+        print("[dim]No environments running[/dim]")
+        return
+
+    table = Table(show_header=True, header_style="bold cyan")
+    table.add_column("Name")
+    table.add_column("Status")
+    table.add_column("Port")
+    table.add_column("Uptime")
+    table.add_column("Container")
+
+    for env in envs:
+        table.add_row(
+            env["env_id"],
+            env.get("status", "unknown"),
+            str(env.get("port") or "-"),
+            _format_uptime(env.get("started_at")),
+            (env.get("container_id") or "-")[:12],
+        )
+
+    print(table)
+
+def _show(client: Client, command: str, render: Callable[[Dict[str, Any]], None], watch: bool, interval: float) -> None:
+    """
+    Print part of /ps once, or keep redrawing it until Ctrl-C.
+    
+    :param client: Client for the daemon.
+    :param command: 'policy' or 'env', shown in the watch header.
+    :param render: Function printing the /ps response as a table.
+    :param watch: If True, keep polling the daemon until interrupted.
+    :param interval: Seconds between refreshes in watch mode.
+    """
+    if not watch:
+        render(_fetch(client))
+        return
+
+    console = get_console()
+    try:
+        while True:
+            status = _fetch(client)
+            console.clear()
+            print(f"[dim]Every {interval:g}s: maple ps {command}    {time.strftime('%H:%M:%S')}[/dim]")
+            render(status)
+            time.sleep(interval)
+    except KeyboardInterrupt:
+        # Ctrl-C is the normal way to leave watch mode
+        pass
+
+@ps_app.command("policy")
+def ps_policy(
+    port: int = typer.Option(None, "--port"),
+    watch: bool = typer.Option(False, "--watch", "-w", help="Refresh the table until Ctrl-C"),
+    interval: float = typer.Option(1.0, "--interval", min=0.1, help="Seconds between refreshes with --watch"),
+) -> None:
+    """
+    Show policies currently loaded by the daemon.
+    
+    Displays each serving policy with its health status, the processor it
+    runs on and when it will be unloaded by the keep-alive timer, followed
+    by how many act requests are running and waiting for a slot. With
+    --watch the screen is cleared and the table redrawn every interval,
+    which is handy while tuning keep-alive.
+    
+    :param port: Daemon port number.
+    :param watch: If True, keep polling the daemon until interrupted.
+    :param interval: Seconds between refreshes in watch mode.
+    """
+    _show(Client.from_config(port), "policy", _print_policies, watch, interval)
+
+@ps_app.command("env")
+def ps_env(
+    port: int = typer.Option(None, "--port"),
+    watch: bool = typer.Option(False, "--watch", "-w", help="Refresh the table until Ctrl-C"),
+    interval: float = typer.Option(1.0, "--interval", min=0.1, help="Seconds between refreshes with --watch"),
+) -> None:
+    """
+    Show environments currently running in the daemon.
+    
+    Displays each environment instance started with 'maple env run' or
+    'maple serve env' with its health status, the host port it listens on,
+    how long it has been up and its Docker container.
+    
+    :param port: Daemon port number.
+    :param watch: If True, keep polling the daemon until interrupted.
+    :param interval: Seconds between refreshes in watch mode.
+    """
+    _show(Client.from_config(port), "env", _print_envs, watch, interval)
@@ -10,19 +10,214 @@ Commands:
 - env: Download an environment image
 """
 
+import os
+import time
 import typer 
 import requests
 from rich import print
-from maple.utils.config import get_config
-from maple.utils.misc import daemon_url, parse_error_response
+from pathlib import Path
+from rich.live import Live
+from rich.console import Group
+from typing import Dict, Iterable, Iterator, List, Optional
+from rich.progress import Progress, TextColumn, BarColumn, DownloadColumn, TaskProgressColumn
+from maple.state import store
+from maple.utils.auth import get_token, normalize_registry
+from maple.utils.config import get_config, set_registry_options, ConfigError
+from maple.api import Client, DaemonError, DaemonNotRunning, PullInterrupted
+from maple.utils.misc import daemon_url, parse_error_response, format_size
+from maple.utils.progress import TransferRate, format_eta
+from maple.utils.ui import progress_console
+from maple.utils.spec import parse_versioned, parse_pinned, parse_hf_spec
 
 # Create the pull sub-application
 # no_args_is_help=True ensures help is shown when no command is given
 pull_app = typer.Typer(no_args_is_help=True)
 
+def _progress_bar() -> Progress:
+    """
+    Build a progress display with percentage, size, smoothed rate and ETA.
+    
+    :return: Rich Progress instance. Not started.
+    """
+    return Progress(
+        TextColumn("{task.description}"),
+        BarColumn(),
+        TaskProgressColumn(),
+        DownloadColumn(),
+        TextColumn("{task.fields[rate]}"),
+        TextColumn("ETA {task.fields[eta]}"),
+    )
+
+def render_pull_events(events: Iterable[Dict]) -> Optional[Dict]:
+    """
+    Render streamed pull events as per-file progress bars plus a total line.
+    
+    Push progress uses the same events with an 'uploading' status.
+    
+    :param events: Iterable of decoded NDJSON pull or push events.
+    :return: The final 'success' event, or None if the stream ended without one.
+    """
+    files_progress = _progress_bar()
+    total_progress = _progress_bar()
+    total_task = total_progress.add_task("[bold]total[/bold]", total=0, rate="", eta="--")
+
+    tasks = {}          # file -> task id
+    rates = {}          # file -> TransferRate
+    files = {}          # file -> (completed, total)
+    total_rate = TransferRate()
+    result = None
+
+    with Live(Group(files_progress, total_progress), refresh_per_second=8, console=progress_console()):
+        for event in events:
+            status = event.get("status")
+
+            if status == "error":
+                raise RuntimeError(event.get("error", "Pull failed"))
+            if status == "success":
+                result = event
+                continue
+            if status not in ("downloading", "uploading"):
+                continue
+
+            name = event["file"]
+            completed, total = event.get("completed", 0), event.get("total", 0)
+            files[name] = (completed, total)
+
+            if name not in tasks:
+                tasks[name] = files_progress.add_task(name, total=total or None, rate="", eta="--")
+                rates[name] = TransferRate()
+
+            rate = rates[name].update(completed)
+            files_progress.update(
+                tasks[name], 
+                completed=completed, 
+                total=total or None,
+                rate=f"{format_size(int(rate))}/s" if rate else "",
+                eta=format_eta(rates[name].eta(total - completed) if total else None),
+            )
+
+            # Aggregate across all files
+            all_completed = sum(c for c, _ in files.values())
+            all_total = sum(t for _, t in files.values())
+            rate = total_rate.update(all_completed)
+            total_progress.update(
+                total_task,
+                completed=all_completed,
+                total=all_total or None,
+                rate=f"{format_size(int(rate))}/s" if rate else "",
+                eta=format_eta(total_rate.eta(all_total - all_completed)),
+            )
+
+    return result
+
+def print_pull_plan(plan: Dict) -> None:
+    """
+    Print the files a pull would fetch and the net download size.
+    
+    :param plan: Dry-run response from the daemon.
+    """
+    from rich.table import Table
+
+    table = Table(title=f"{plan['policy']} ({plan['repo']})")
+    table.add_column("FILE")
+    table.add_column("SIZE", justify="right")
+    table.add_column("STATUS")
+
+    for f in plan["files"]:
+        status = "[green]cached[/green]" if f["cached"] else "download"
+        table.add_row(f["file"], format_size(f["size"]), status)
+
+    print(table)
+    print(
+        f"Total: {format_size(plan['total'])}  "
+        f"Cached: {format_size(plan['cached'])}  "
+        f"[bold]To download: {format_size(plan['download'])}[/bold]"
+    )
+
+# Times a dropped progress stream is re-attached before giving up
+_REATTACH_ATTEMPTS = 5
+
+def stream_pull_events(client: Client, payload: Dict) -> Iterator[Dict]:
+    """
+    Stream pull events, re-attaching if the connection drops.
+    
+    The daemon keeps a pull running after its client disconnects, so
+    sending the same request again resumes the progress stream instead of
+    starting the download over.
+    
+    :param client: Client for the daemon.
+    :param payload: Pull request body with stream enabled.
+    :return: Iterator of decoded NDJSON pull events.
+    """
+    attached = False
+    attempts = 0
+    while True:
+        try:
+            for event in client.pull_events(payload):
+                attached, attempts = True, 0
+                yield event
+                if event.get("status") in ("success", "error"):
+                    return
+            raise PullInterrupted("Pull stream ended without a result")
+        except (PullInterrupted, DaemonNotRunning) as e:
+            # Only re-attach to a pull the daemon has already started
+            attempts += 1
+            if not attached:
+                raise
+            if attempts > _REATTACH_ATTEMPTS:
+                raise RuntimeError(str(e))
+            time.sleep(1)
+
+def check_tags(spec: str, tags: List[str], force: bool = False) -> None:
+    """
+    Validate extra tags for a pull before anything is downloaded.
+
+    A tag is 'backend:version', or a bare version of the pulled policy's
+    backend. It may not name the pulled policy itself, and without force
+    it may not name a policy that already exists. For 'hf.co/' specs the
+    backend is only known after the pull, so bare tags are only checked
+    for syntax there.
+
+    :param spec: Policy specification being pulled.
+    :param tags: Tags to register the pulled policy under.
+    :param force: If True, allow tags that replace existing policies.
+    :raises ValueError: If a tag is malformed, names another backend, names
+                        the pulled policy or, without force, already exists.
+    """
+    base, _ = parse_pinned(spec)
+    pulled = None if parse_hf_spec(base) else parse_versioned(base)
+    backend = pulled[0] if pulled else None
+    for tag in tags:
+        if ":" not in tag:
+            if not tag.strip():
+                raise ValueError("Tag cannot be empty")
+            if backend is None:
+                continue
+            ref = (backend, tag.strip())
+        else:
+            ref = parse_versioned(tag)
+        if backend and ref[0] != backend:
+            raise ValueError(f"Tag {tag} must use the pulled policy's backend '{backend}'")
+        if ref == pulled:
+            raise ValueError(f"Tag {tag} is the policy being pulled")
+        if not force and store.get_policy(*ref):
+            raise ValueError(f"Policy {ref[0]}:{ref[1]} already exists. Use --force to replace it")
+
 @pull_app.command("policy")
 def pull_policy(
-    name: str = typer.Argument(..., help="name (e.g., openvla:7b)"),
+    name: str = typer.Argument(..., help="name (e.g., openvla:7b or hf.co/openvla/openvla-7b)"),
+    concurrency: int = typer.Option(3, "--concurrency", min=1, help="Number of files to download in parallel"),
+    max_retries: int = typer.Option(3, "--max-retries", min=0, help="Retries per file on transient network errors"),
+    dry_run: bool = typer.Option(False, "--dry-run", help="Show the download size and cached files without pulling"),
+    registry: Optional[str] = typer.Option(None, "--registry", help="Registry host (default: from config, typically huggingface.co)"),
+    insecure: Optional[bool] = typer.Option(None, "--insecure/--no-insecure", help="Skip TLS certificate verification of the registry (remembered per registry)"),
+    ca_cert: Optional[Path] = typer.Option(
+        None, "--ca-cert", exists=True, dir_okay=False, readable=True, resolve_path=True,
+        help="PEM file of extra CAs to trust for the registry (remembered per registry)",
+    ),
+    proxy: Optional[str] = typer.Option(None, "--proxy", help="Proxy URL for registry downloads (default: HTTP_PROXY/HTTPS_PROXY of the daemon)"),
+    tag: Optional[List[str]] = typer.Option(None, "--tag", help="Also register the pulled policy as this tag (e.g., openvla:latest); repeatable"),
+    force: bool = typer.Option(False, "--force", help="Let --tag replace policies that already exist"),
     port: int = typer.Option(None, "--port")
 ) -> None:
     """
@@ -30,24 +225,117 @@ def pull_policy(
     
     Pulls a policy model from a remote repository or registry, making it
     available for serving and evaluation. The policy specification can
-    include version information (e.g., 'openvla:7b').
+    include version information (e.g., 'openvla:7b') or point directly at
+    a HuggingFace repo (e.g., 'hf.co/openvla/openvla-7b'). Private repos
+    are accessed with the token in $HF_TOKEN or the one saved by
+    'maple login'.
+    
+    With --dry-run only the repo file listing is fetched, and the total,
+    already cached and remaining download sizes are reported.
+    
+    --insecure and --ca-cert only change how the registry is contacted.
+    They are saved under policy.registries in the config file, so later
+    pulls from the same registry reuse them; --no-insecure undoes
+    --insecure.
+    
+    Registry requests go through the proxy in the daemon's HTTP_PROXY,
+    HTTPS_PROXY and NO_PROXY environment, or through --proxy if given.
+    
+    Each --tag registers the pulled weights under another name once the
+    pull succeeds, hard-linked so nothing is downloaded twice. Tags are
+    checked before the pull starts; one naming an existing policy fails
+    unless --force is given, in which case that policy is replaced.
     
     :param name: Policy specification string (name or name:version).
+    :param concurrency: Maximum number of files downloaded at once.
+    :param max_retries: Retries per file for transient network errors.
+    :param dry_run: If True, report what would be downloaded and exit.
+    :param registry: Registry host. Defaults to policy.registry.
+    :param insecure: Skip TLS certificate verification. None keeps the saved preference.
+    :param ca_cert: PEM file of extra CAs trusted for the registry.
+    :param proxy: Proxy URL overriding the daemon's environment.
+    :param tag: Extra 'name:version' refs for the pulled policy.
+    :param force: If True, tags replace existing policies of the same name.
     :param port: Daemon port number.
     """
+    tags = tag or []
+    try:
+        check_tags(name, tags, force=force)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
     config = get_config()
     # Use config default if port not specified
     port = port or config.daemon.port
+    host = normalize_registry(registry or config.policy.registry)
+
+    # Remember TLS options for this registry
+    updates = {}
+    if insecure is not None:
+        updates["insecure"] = True if insecure else None
+    if ca_cert is not None:
+        updates["ca_cert"] = str(ca_cert)
+    if updates:
+        try:
+            set_registry_options(host, **updates)
+        except ConfigError as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        config = get_config()
+
+    tls = config.policy.registries.get(host) or {}
+    if tls.get("insecure"):
+        print(
+            f"[bold yellow]WARNING:[/bold yellow] TLS certificate verification is disabled for {host}. "
+            f"Downloads can be intercepted or tampered with. Use --no-insecure to turn it back on."
+        )
     
-    # Send pull request to daemon with policy spec
-    r = requests.post(f"{daemon_url(port)}/policy/pull", json={"spec": name})
-    
-    if r.status_code != 200:
-        print(f"[red]Error:[/red] {parse_error_response(r)}")
+    options = {
+        "concurrency": concurrency, 
+        "max_retries": max_retries, 
+        "registry": host,
+        "insecure": bool(tls.get("insecure", False)),
+    }
+    if tls.get("ca_cert"):
+        options["ca_cert"] = tls["ca_cert"]
+    if proxy:
+        options["proxy"] = proxy
+    hf_token = os.environ.get("HF_TOKEN") or get_token(host)
+    if hf_token:
+        options["hf_token"] = hf_token
+
+    client = Client(daemon_url(port))
+    if dry_run:
+        try:
+            plan = client.pull(name, dry_run=True, **options)
+        except (DaemonError, DaemonNotRunning) as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        print_pull_plan(plan)
+        return
+
+    # Send pull request to daemon with policy spec, streaming progress events
+    payload = {"spec": name, "stream": True, **options}
+    events = stream_pull_events(client, payload)
+    try:
+        result = render_pull_events(events)
+    except (RuntimeError, DaemonError, DaemonNotRunning) as e:
+        print(f"[red]Error:[/red] {e}")
         raise typer.Exit(1)
     
     # Confirm successful pull
-    print(f"[green]PULLED policy[/green] {name}")
+    pulled = (result or {}).get("pulled", name)
+    print(f"[green]PULLED policy[/green] {pulled}")
+
+    # Tag only once the pull has succeeded; links the weights, no download
+    for alias in tags:
+        try:
+            created = client.create({"name": alias, "base": pulled, "overwrite": force})
+        except (DaemonError, DaemonNotRunning) as e:
+            print(f"[red]Error:[/red] Could not tag {pulled} as {alias}: {e}")
+            raise typer.Exit(1)
+        print(f"[green]TAGGED[/green] {pulled} as {created['created']}")
 
 @pull_app.command("env")
 def pull_env(
@@ -0,0 +1,111 @@
+"""
+Push command for the MAPLE CLI.
+
+This module publishes a local policy, pulled or created, to a model repo
+on a registry so others can pull it. Files the repo already has are
+skipped, and the rest are committed in one go.
+
+Commands:
+- push: Upload a policy to a registry
+"""
+
+import queue
+import typer
+import threading
+from rich import print
+from pathlib import Path
+from typing import Dict, Iterator, Optional
+
+from maple.state import store
+from maple.utils.hub import hub_for
+from maple.utils.upload import upload_repo
+from maple.utils.config import get_config
+from maple.utils.spec import parse_versioned
+from maple.utils.auth import get_token, normalize_registry
+from maple.cmd.cli.pull import render_pull_events
+
+def push_events(src: Path, repo: str, token: str, **options) -> Iterator[Dict]:
+    """
+    Run an upload in the background and yield its progress as pull-style events.
+
+    :param src: Policy weights directory.
+    :param repo: Target repo ID.
+    :param token: Access token.
+    :param options: Extra upload_repo arguments (revision, hub, message).
+    :return: Iterator of 'uploading' events, then one 'success' or 'error' event.
+    """
+    events: "queue.Queue[Optional[Dict]]" = queue.Queue()
+
+    def progress(file: str, completed: int, total: int) -> None:
+        events.put({"status": "uploading", "file": file, "completed": completed, "total": total})
+
+    def worker() -> None:
+        try:
+            events.put({"status": "success", **upload_repo(src, repo, token, progress=progress, **options)})
+        except Exception as e:
+            events.put({"status": "error", "error": str(e)})
+        finally:
+            events.put(None)
+
+    threading.Thread(target=worker, daemon=True).start()
+    while True:
+        event = events.get()
+        if event is None:
+            return
+        yield event
+
+def push(
+    spec: str = typer.Argument(..., help="Policy to push (e.g., openvla:kitchen-ft)"),
+    repo: str = typer.Argument(..., help="Target repo (e.g., myorg/openvla-kitchen)"),
+    registry: Optional[str] = typer.Option(None, "--registry", help="Registry host (default: policy.registry)"),
+    revision: Optional[str] = typer.Option(None, "--revision", help="Branch to push to (default: main)"),
+    message: Optional[str] = typer.Option(None, "--message", "-m", help="Commit message"),
+) -> None:
+    """
+    Upload a policy to a registry.
+
+    The repo is created if needed. Files it already has with the same
+    contents are skipped, and the rest are uploaded and committed
+    together. Requires a token stored with 'maple login' for the registry.
+
+    :param spec: Local policy 'name:version'.
+    :param repo: Target repo ID.
+    :param registry: Registry host to push to.
+    :param revision: Branch to push to.
+    :param message: Commit message.
+    """
+    name, version = parse_versioned(spec)
+    policy = store.get_policy(name, version)
+    if policy is None:
+        print(f"[red]Error:[/red] Policy {name}:{version} not found. Pull or create it first")
+        raise typer.Exit(1)
+
+    src = Path(policy["path"])
+    if not src.is_dir():
+        print(f"[red]Error:[/red] Weights of {name}:{version} are missing at {src}")
+        raise typer.Exit(1)
+
+    host = normalize_registry(registry or get_config().policy.registry)
+    token = get_token(host)
+    if not token:
+        print(f"[red]Error:[/red] Not logged in to {host}. Run [cyan]maple login {host}[/cyan] first")
+        raise typer.Exit(1)
+
+    try:
+        hub = hub_for(host)
+    except OSError as e:
+        print(f"[red]Error:[/red] Cannot read CA certificate: {e}")
+        raise typer.Exit(1)
+
+    print(f"Pushing {name}:{version} to {host}/{repo}")
+    try:
+        result = render_pull_events(push_events(src, repo, token, revision=revision, hub=hub, message=message))
+    except RuntimeError as e:
+        print(f"[red]Error:[/red] Push failed: {e}")
+        raise typer.Exit(1)
+
+    if result["commit"] is None:
+        print(f"[green]✓[/green] {repo} is already up to date ({len(result['skipped'])} file(s))")
+        return
+    print(f"[green]✓[/green] Uploaded {len(result['uploaded'])} file(s), skipped {len(result['skipped'])} already present")
+    print(f"[bold green]PUSHED[/bold green] {name}:{version} → {repo}@{result['commit'][:7]}")
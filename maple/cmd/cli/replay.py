@@ -0,0 +1,169 @@
+"""
+Replay command for the MAPLE CLI.
+
+This module re-sends the inputs of a trajectory recorded with
+'maple run --record' to a policy and compares the actions it returns
+with the recorded ones, to check that a model upgrade or a new machine
+does not change behavior. Each step is sent to /policy/act with the
+recorded input image and instruction, unchanged.
+
+Commands:
+- replay: Replay a recorded trajectory and report action deviations
+"""
+
+import math
+import typer
+from rich import print
+from rich.table import Table
+from pathlib import Path
+from typing import Dict, List, Optional, Sequence
+
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.utils.config import get_config
+from maple.utils.image import ImageError, load_image_file, encode_png
+from maple.utils.record import read_record
+from maple.cmd.cli.completion import complete_policy_spec
+
+def action_deviation(recorded: Sequence[float], replayed: Sequence[float]) -> float:
+    """
+    Measure how far a replayed action is from the recorded one.
+
+    :param recorded: Action in the trajectory.
+    :param replayed: Action returned by the replay.
+    :return: Largest absolute difference over the action dimensions, or
+             infinity if the actions have different lengths.
+    """
+    if len(recorded) != len(replayed):
+        return math.inf
+    return max((abs(a - b) for a, b in zip(recorded, replayed)), default=0.0)
+
+def _find_serving(client: Client, model: str) -> Optional[str]:
+    """
+    Find a loaded policy matching a policy ID or name:version spec.
+
+    :param client: Client for the daemon.
+    :param model: Policy ID or spec.
+    :return: Policy ID, or None if nothing matching is loaded.
+    """
+    for policy in client.ps().get("policies", []):
+        if model in (policy["policy_id"], f"{policy['backend']}:{policy['version']}"):
+            return policy["policy_id"]
+    return None
+
+def replay(
+    record: Path = typer.Argument(..., help="Trajectory file written by 'maple run --record'"),
+    model: Optional[str] = typer.Option(None, "--model", help="Policy to replay on, as an ID or spec (default: the recorded policy)", autocompletion=complete_policy_spec),
+    tolerance: float = typer.Option(1e-4, "--tolerance", min=0, help="Largest allowed difference per action dimension"),
+    device: Optional[str] = typer.Option(None, "--device", "-d", help="Device to load the policy on if it is not loaded"),
+    keep: bool = typer.Option(False, "--keep", help="Leave a policy loaded by the replay running afterwards"),
+    port: int = typer.Option(None, "--port"),
+) -> None:
+    """
+    Replay a recorded trajectory and compare the actions.
+
+    Every recorded step is sent to MODEL with the same input image and
+    instruction, and the returned action is compared with the recorded
+    one. The deviation of a step is the largest absolute difference over
+    the action dimensions. Exits with status 1 if any step deviates by
+    more than --tolerance.
+
+    An already loaded policy is used as is; otherwise MODEL is loaded for
+    the replay and stopped afterwards unless --keep is given.
+
+    :param record: Trajectory file.
+    :param model: Policy ID or name:version spec. Defaults to the policy in
+                 the trajectory's header.
+    :param tolerance: Largest deviation a step may have.
+    :param device: Device to load the policy on. Defaults to the config.
+    :param keep: If True, leave a policy loaded by the replay running.
+    :param port: Daemon port number.
+    """
+    try:
+        entries = list(read_record(record))
+    except OSError as e:
+        print(f"[red]Error:[/red] Cannot read {record}: {e.strerror}")
+        raise typer.Exit(1)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    # Pair each step with the header of its run for model_kwargs and labels
+    headers: List[Dict] = []
+    steps = []
+    for entry in entries:
+        if entry["type"] == "header":
+            headers.append(entry)
+        elif entry["type"] == "step" and headers:
+            steps.append((len(headers), headers[-1], entry))
+    if not steps:
+        print(f"[red]Error:[/red] No recorded steps in {record}")
+        raise typer.Exit(1)
+    missing = [entry["step"] for _, _, entry in steps if not entry.get("image") or not Path(entry["image"]).is_file()]
+    if missing:
+        print(f"[red]Error:[/red] Input images of {len(missing)} step(s) are missing (first: step {missing[0]}). Keep the .frames directory next to {record.name}")
+        raise typer.Exit(1)
+
+    model = model or headers[0].get("policy")
+    if not model:
+        print("[red]Error:[/red] The trajectory does not name its policy; pass --model")
+        raise typer.Exit(1)
+
+    config = get_config()
+    client = Client.from_config(port)
+    loaded_here = False
+    try:
+        policy_id = _find_serving(client, model)
+        if policy_id is None:
+            device = device or config.policy.default_device
+            print(f"[cyan]Loading {model} on {device}...[/cyan]")
+            policy_id = client.serve_policy({"spec": model, "device": device, "keep_alive": "-1"})["policy_id"]
+            loaded_here = True
+
+        deviations = []
+        for run, header, entry in steps:
+            action = client.act({
+                "policy_id": policy_id,
+                "image": encode_png(load_image_file(entry["image"])),
+                "instruction": entry["instruction"],
+                "model_kwargs": header.get("model_kwargs") or {},
+            })["action"]
+            label = f"{run}:{entry['step']}" if len(headers) > 1 else str(entry["step"])
+            deviations.append((label, action_deviation(entry["action"], action)))
+    except ImageError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    finally:
+        if loaded_here and not keep:
+            try:
+                client.stop_policy(policy_id)
+            except (DaemonError, DaemonNotRunning):
+                print(f"[yellow]Warning:[/yellow] Could not stop {policy_id}")
+
+    table = Table(title=f"Replay of {record.name} on {model}")
+    table.add_column("STEP", justify="right")
+    table.add_column("DEVIATION", justify="right")
+    table.add_column("")
+    for label, deviation in deviations:
+        within = deviation <= tolerance
+        table.add_row(
+            label,
+            "shape mismatch" if math.isinf(deviation) else f"{deviation:.3g}",
+            "[green]✓[/green]" if within else "[red]✗[/red]",
+        )
+    print(table)
+
+    values = [deviation for _, deviation in deviations]
+    exceeded = sum(1 for value in values if value > tolerance)
+    print(f"  Steps: {len(values)}")
+    print(f"  Max deviation: {max(values):.3g}")
+    print(f"  Mean deviation: {sum(values) / len(values):.3g}")
+    if exceeded:
+        print(f"[red]✗ {exceeded} of {len(values)} step(s) exceed the tolerance of {tolerance:g}[/red]")
+        raise typer.Exit(1)
+    print(f"[green]✓ All steps within {tolerance:g}[/green]")
@@ -0,0 +1,118 @@
+"""
+Restart command for the MAPLE CLI.
+
+This module restarts the daemon serving the current MAPLE home, for
+example to apply configuration changes. It composes 'maple stop' and
+'maple serve --detach': the running daemon is found through its PID file
+and shut down gracefully, and once its port is free a new daemon is
+started in the background with the flags recorded in the PID file, or
+with new ones given on the command line.
+
+Commands:
+- restart: Stop the running daemon and start a fresh one
+"""
+
+import time
+import socket
+import typer
+from rich import print
+from typing import Optional
+
+from maple.utils.misc import parse_duration
+from maple.utils.lock import running_daemon, stop_process
+from maple.cmd.cli.serve import start_detached
+
+# How long to wait for the new daemon to take the PID file
+_START_TIMEOUT = 30.0
+
+def port_free(port: int) -> bool:
+    """
+    Check whether a TCP port can be bound on all interfaces.
+    
+    :param port: Port number.
+    :return: True if nothing is listening on it.
+    """
+    with socket.socket(socket.AF_INET, socket.SOCK_STREAM) as sock:
+        sock.setsockopt(socket.SOL_SOCKET, socket.SO_REUSEADDR, 1)
+        try:
+            sock.bind(("0.0.0.0", port))
+        except OSError:
+            return False
+    return True
+
+def wait_for_port(port: int, timeout: float, poll: float = 0.2) -> bool:
+    """
+    Wait until a port has been released.
+    
+    :param port: Port number.
+    :param timeout: Seconds to wait.
+    :param poll: Seconds between checks.
+    :return: True if the port became free in time.
+    """
+    deadline = time.monotonic() + timeout
+    while not port_free(port):
+        if time.monotonic() >= deadline:
+            return False
+        time.sleep(poll)
+    return True
+
+def restart(
+    ctx: typer.Context,
+    timeout: str = typer.Option("30s", "--timeout", help="How long to wait for the old daemon to shut down before killing it"),
+) -> None:
+    """
+    Restart the MAPLE daemon.
+    
+    Stops the daemon recorded in ~/.maple/maple.pid and starts a new one in
+    the background with the same 'maple serve' flags. Flags after '--'
+    replace the previous ones.
+    
+    Examples:
+        maple restart
+        maple restart -- --port 9000 --metrics
+    
+    :param ctx: Typer context holding any replacement serve flags.
+    :param timeout: Grace period before the old daemon is killed (e.g., 30s).
+    """
+    try:
+        grace = parse_duration(timeout)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    running = running_daemon()
+    if running is None:
+        print("[yellow]No MAPLE daemon running[/yellow]")
+        print("Start one with [cyan]maple serve --detach[/cyan]")
+        raise typer.Exit(1)
+
+    serve_args = list(ctx.args) if ctx.args else running.get("args")
+    if serve_args is None:
+        print("[red]Error:[/red] The running daemon did not record its flags; pass them after '--', e.g. maple restart -- --port 8000")
+        raise typer.Exit(1)
+
+    pid = running["pid"]
+    print(f"Stopping MAPLE daemon (pid {pid})...")
+    if stop_process(pid, timeout=grace if grace is not None else float("inf")):
+        print("[green]✓ Stopped cleanly[/green]")
+    else:
+        print(f"[yellow]Did not shut down within {timeout} and was killed[/yellow]")
+
+    # The new daemon can't bind until the old socket is gone
+    port: Optional[int] = running.get("port")
+    if port and not wait_for_port(port, timeout=grace or _START_TIMEOUT):
+        print(f"[red]Error:[/red] Port {port} is still in use; start the daemon with [cyan]maple serve[/cyan] once it is free")
+        raise typer.Exit(1)
+
+    start_detached(serve_args)
+
+    deadline = time.monotonic() + _START_TIMEOUT
+    while time.monotonic() < deadline:
+        started = running_daemon()
+        if started and started["pid"] != pid:
+            print(f"[green]✓ MAPLE daemon restarted[/green] (pid {started['pid']}, port {started.get('port', '?')})")
+            return
+        time.sleep(0.2)
+
+    print("[red]Error:[/red] The new daemon did not start; see [cyan]maple logs[/cyan] and /tmp/vla.err")
+    raise typer.Exit(1)
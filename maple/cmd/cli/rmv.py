@@ -8,8 +8,8 @@ from the system. This includes:
 - Removing Docker images
 
 Commands:
-- policy: Remove a policy model and its weights
-- env: Remove an environment and its Docker image
+- policy: Remove one or more policy models and their weights
+- env: Remove an environment and its Docker image, unless it is in use
 """
 
 import typer
@@ -18,45 +18,67 @@ import shutil
 import requests
 from rich import print
 from pathlib import Path
+from typing import List, Optional, Tuple
 
 from maple.utils.config import get_config
+from maple.utils.lock import lock_ref
 from maple.utils.logging import get_logger
-from maple.utils.misc import daemon_url
+from maple.utils.misc import daemon_url, format_size
 from maple.utils.spec import parse_versioned
-from maple.state.store import remove_policy, remove_env, get_policy, get_env
+from maple.cmd.cli.completion import complete_policy_spec
+from maple.state.store import remove_policy, remove_env, get_policy, get_env, list_policies
 
 log = get_logger("remove")
 
 # Create the remove sub-application
 remove_app = typer.Typer(no_args_is_help=True)
 
-@remove_app.command("policy")
-def remove_policy_cmd(
-    name: str = typer.Argument(..., help="Policy name (e.g., openvla:7b)"),
-    port: int = typer.Option(None, "--port"),
-    keep_weights: bool = typer.Option(False, "--keep-weights", help="Keep model weights on disk"),
-) -> None:
+def _stop_serving_policies(name: str, version: str, port: int) -> None:
     """
-    Remove a policy model from the system.
+    Stop any running containers serving a policy.
     
-    This command will:
-    1. Remove the policy from the database
-    2. Delete model weights from disk (unless --keep-weights is specified)
-    3. Stop any running containers using this policy
+    :param name: Policy name.
+    :param version: Policy version.
+    :param port: Daemon port number.
+    """
+    try:
+        # The daemon matches the spec exactly, so openvla:7b never stops openvla:7b-ft
+        r = requests.post(f"{daemon_url(port)}/policy/stop/{name}:{version}")
+    except requests.exceptions.ConnectionError:
+        return  # No daemon, nothing is serving
+    except Exception as e:
+        log.warning(f"Could not check for running containers: {e}")
+        return
+
+    if r.status_code == 200:
+        for policy_id in r.json().get("stopped", []):
+            print(f"  Stopped policy container: {policy_id}")
+    elif r.status_code != 400:  # 400: not loaded
+        log.warning(f"Failed to stop {name}:{version}: {r.text}")
+
+def _remove_one_policy(spec: str, port: int, keep_weights: bool) -> Optional[str]:
+    """
+    Remove a single policy's database entry, weights and containers.
+    
+    The Docker image is left in place so it can be removed once for the
+    whole batch.
     
-    :param name: Name of the policy model to remove.
-    :param version: Version identifier of the policy.
+    :param spec: Policy specification (name:version).
     :param port: Daemon port number.
     :param keep_weights: If True, keep the model weights on disk.
+    :return: Docker image used by the policy, or None if the removal failed.
     """
-    config = get_config()
-    port = port or config.daemon.port
-    name, version = parse_versioned(name)
+    try:
+        name, version = parse_versioned(spec)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        return None
+
     # Check if policy exists
     policy = get_policy(name, version)
     if not policy:
         print(f"[red]Error:[/red] Policy {name}:{version} not found in database")
-        raise typer.Exit(1)
+        return None
     
     image_name = policy['image']
     # Get policy path
@@ -70,74 +92,170 @@ def remove_policy_cmd(
     print(f"  Docker image: {image_name}")
     print(f"  Delete weights: {'No (--keep-weights)' if keep_weights else 'Yes'}")
     
-    try:
-        # Get daemon status which includes serving policies
-        r = requests.get(f"{daemon_url(port)}/status")
-        if r.status_code == 200:
-            status_data = r.json()
-            serving_policies = status_data.get('serving', {}).get('policies', [])
-            
-            # Check if this policy is currently being served
-            policy_key = f"{name}-{version}"
-            matching_policies = [p for p in serving_policies if p.startswith(policy_key)]
-            
-            # Stop each matching policy container
-            for policy_id in matching_policies:
-                print(f"  Stopping policy container: {policy_id}")
-                try:
-                    requests.post(f"{daemon_url(port)}/policy/stop/{policy_id}")
-                except Exception as e:
-                    log.warning(f"Failed to stop policy {policy_id}: {e}")
-    except Exception as e:
-        log.warning(f"Could not check for running containers: {e}")
+    _stop_serving_policies(name, version, port)
     
-    # Remove from database
-    removed = remove_policy(name, version)
-    if removed:
-        print(f"[green]✓[/green] Removed from database")
-    else:
-        print(f"[yellow]Warning:[/yellow] Policy not found in database")
+    # Serialize with pulls of the same ref so weights are not deleted mid-download
+    with lock_ref(name, version):
+        # Remove from database
+        removed = remove_policy(name, version)
+        if removed:
+            print(f"[green]✓[/green] Removed from database")
+        else:
+            print(f"[yellow]Warning:[/yellow] Policy not found in database")
     
-    # Delete weights from disk
-    if not keep_weights and weights_path.exists():
-        try:
-            if weights_path.is_dir():
-                shutil.rmtree(weights_path)
-            else:
-                weights_path.unlink()
-            print(f"[green]✓[/green] Deleted weights from {weights_path}")
-        except Exception as e:
-            print(f"[red]Error deleting weights:[/red] {e}")
-            log.error(f"Failed to delete weights: {e}")
-    elif not weights_path.exists():
-        print(f"[yellow]Warning:[/yellow] Weights path does not exist: {weights_path}")
+        # Delete weights from disk
+        if not keep_weights and weights_path.exists():
+            try:
+                if weights_path.is_dir():
+                    shutil.rmtree(weights_path)
+                else:
+                    weights_path.unlink()
+                print(f"[green]✓[/green] Deleted weights from {weights_path}")
+            except Exception as e:
+                print(f"[red]Error deleting weights:[/red] {e}")
+                log.error(f"Failed to delete weights: {e}")
+        elif not weights_path.exists():
+            print(f"[yellow]Warning:[/yellow] Weights path does not exist: {weights_path}")
+
+    return image_name
+
+def _remove_unused_images(images: List[str]) -> None:
+    """
+    Remove Docker images no longer used by any pulled policy.
+    
+    :param images: Candidate image names collected from removed policies.
+    """
+    in_use = {p['image'] for p in list_policies()}
+    candidates = [image for image in dict.fromkeys(images) if image not in in_use]
+    if not candidates:
+        return
 
     try:
         client = docker.from_env()
-        client.images.remove(image_name, force=True)
-        print(f"[green]✓[/green] Removed Docker image: {image_name}")
-    except docker.errors.ImageNotFound:
-        print(f"[yellow]Warning:[/yellow] Docker image not found: {image_name}")
     except Exception as e:
         print(f"[red]Error removing Docker image:[/red] {e}")
-        log.error(f"Failed to remove Docker image: {e}")
+        log.error(f"Failed to connect to Docker: {e}")
+        return
+
+    for image_name in candidates:
+        try:
+            client.images.remove(image_name, force=True)
+            print(f"[green]✓[/green] Removed Docker image: {image_name}")
+        except docker.errors.ImageNotFound:
+            print(f"[yellow]Warning:[/yellow] Docker image not found: {image_name}")
+        except Exception as e:
+            print(f"[red]Error removing Docker image:[/red] {e}")
+            log.error(f"Failed to remove Docker image: {e}")
+
+def _expand_policy_specs(names: List[str]) -> Tuple[List[str], bool]:
+    """
+    Expand bare policy names into every pulled version of that name.
     
-    print(f"\n[bold green]✓ Policy {name}:{version} removed successfully[/bold green]")
+    :param names: Policy specifications, either 'name:version' or 'name'.
+    :return: Tuple of (expanded specs, whether any bare name was expanded).
+    """
+    pulled = list_policies()
+    specs, expanded = [], False
+    for spec in names:
+        if ":" in spec:
+            specs.append(spec)
+            continue
+        versions = [f"{p['name']}:{p['version']}" for p in pulled if p['name'] == spec.strip()]
+        if versions:
+            specs.extend(versions)
+            expanded = True
+        else:
+            # Keep it so it is reported as not found
+            specs.append(spec)
+    return list(dict.fromkeys(specs)), expanded
+
+@remove_app.command("policy")
+def remove_policy_cmd(
+    names: Optional[List[str]] = typer.Argument(None, help="Policy names (e.g., openvla:7b, or openvla for every version)", autocompletion=complete_policy_spec),
+    all_policies: bool = typer.Option(False, "--all", help="Remove every pulled policy"),
+    force: bool = typer.Option(False, "--force", "-f", help="Skip confirmation"),
+    port: int = typer.Option(None, "--port"),
+    keep_weights: bool = typer.Option(False, "--keep-weights", help="Keep model weights on disk"),
+) -> None:
+    """
+    Remove one or more policy models from the system.
+    
+    For each policy this command will:
+    1. Remove the policy from the database
+    2. Delete model weights from disk (unless --keep-weights is specified)
+    3. Stop any running containers using this policy
+    
+    A name without a version (e.g., 'openvla') removes every pulled version
+    of it, and --all removes every pulled policy. Both ask for confirmation
+    unless --force is given. Policies that cannot be found are reported and
+    skipped. Docker images no longer used by any remaining policy are
+    removed once at the end.
+    
+    :param names: Policy specifications (name:version or name) to remove.
+    :param all_policies: If True, remove every pulled policy.
+    :param force: If True, skip the confirmation prompt.
+    :param port: Daemon port number.
+    :param keep_weights: If True, keep the model weights on disk.
+    """
+    config = get_config()
+    port = port or config.daemon.port
+    
+    if all_policies:
+        if names:
+            print("[red]Error:[/red] Pass either policy names or --all, not both")
+            raise typer.Exit(1)
+        names = [f"{p['name']}:{p['version']}" for p in list_policies()]
+        if not names:
+            print("[yellow]No policies to remove[/yellow]")
+            return
+        expanded = True
+    elif names:
+        names, expanded = _expand_policy_specs(names)
+    else:
+        print("[red]Error:[/red] Specify at least one policy or use --all")
+        raise typer.Exit(1)
+
+    if expanded and not force:
+        print(f"[yellow]Policies to remove:[/yellow] {', '.join(names)}")
+        confirm = typer.confirm(f"Remove {len(names)} policy(s)?")
+        if not confirm:
+            print("[dim]Cancelled[/dim]")
+            return
+    
+    removed, failed, images = [], [], []
+    for spec in names:
+        image_name = _remove_one_policy(spec, port, keep_weights)
+        if image_name is None:
+            failed.append(spec)
+            continue
+        removed.append(spec)
+        images.append(image_name)
+
+    _remove_unused_images(images)
+
+    for spec in removed:
+        print(f"\n[bold green]✓ Policy {spec} removed successfully[/bold green]")
+    if failed:
+        print(f"\n[red]Failed to remove:[/red] {', '.join(failed)}")
+        raise typer.Exit(1)
 
 @remove_app.command("env")
 def remove_env_cmd(
     name: str = typer.Argument(..., help="Environment name (e.g., libero)"),
+    force: bool = typer.Option(False, "--force", "-f", help="Stop running containers of this environment and remove it anyway"),
     port: int = typer.Option(None, "--port"),
 ) -> None:
     """
     Remove an environment from the system.
     
     This command will:
-    1. Remove the environment from the database
-    2. Remove the Docker image (unless --keep-image is specified)
-    3. Stop any running containers using this environment
+    1. Refuse to continue if the environment is being served (unless --force)
+    2. Stop any running containers using this environment (with --force)
+    3. Remove the environment from the database
+    4. Remove the Docker image and report the space freed
     
     :param name: Name of the environment to remove.
+    :param force: If True, stop running containers instead of refusing.
     :param port: Daemon port number.
     """
     config = get_config()
@@ -151,32 +269,36 @@ def remove_env_cmd(
     
     image_name = env['image']
     
-    # Show what will be deleted
-    print(f"\n[yellow]The following will be removed:[/yellow]")
-    print(f"  Environment: {name}")
-    print(f"  Database entry: Yes")
-    print(f"  Docker image: {image_name}")
-
-    # Try to stop any running containers with this environment
+    # Find containers currently serving this environment
+    matching_envs = []
     try:
         # Get daemon status which includes serving environments
         r = requests.get(f"{daemon_url(port)}/status")
         if r.status_code == 200:
             status_data = r.json()
             serving_envs = status_data.get('serving', {}).get('envs', [])
-            
-            # Check if this environment is currently being served
-            matching_envs = [e for e in serving_envs if e.startswith(name)]
-            
-            # Stop each matching environment container
-            for env_id in matching_envs:
-                print(f"  Stopping environment container: {env_id}")
-                try:
-                    requests.post(f"{daemon_url(port)}/env/stop/{env_id}")
-                except Exception as e:
-                    log.warning(f"Failed to stop environment {env_id}: {e}")
+            matching_envs = [e for e in serving_envs if e.startswith(f"{name}-")]
     except Exception as e:
         log.warning(f"Could not check for running containers: {e}")
+
+    if matching_envs and not force:
+        print(f"[red]Error:[/red] Environment {name} is in use by: {', '.join(matching_envs)}")
+        print("Stop them with 'maple env stop' first, or pass --force to stop them and remove anyway")
+        raise typer.Exit(1)
+    
+    # Show what will be deleted
+    print(f"\n[yellow]The following will be removed:[/yellow]")
+    print(f"  Environment: {name}")
+    print(f"  Database entry: Yes")
+    print(f"  Docker image: {image_name}")
+
+    # Stop each matching environment container
+    for env_id in matching_envs:
+        print(f"  Stopping environment container: {env_id}")
+        try:
+            requests.post(f"{daemon_url(port)}/env/stop/{env_id}")
+        except Exception as e:
+            log.warning(f"Failed to stop environment {env_id}: {e}")
     
     # Remove from database
     removed = remove_env(name)
@@ -186,14 +308,20 @@ def remove_env_cmd(
         print(f"[yellow]Warning:[/yellow] Environment not found in database")
     
     # Remove Docker image
+    freed = None
     try:
         client = docker.from_env()
+        freed = client.images.get(image_name).attrs.get("Size")
         client.images.remove(image_name, force=True)
         print(f"[green]✓[/green] Removed Docker image: {image_name}")
     except docker.errors.ImageNotFound:
         print(f"[yellow]Warning:[/yellow] Docker image not found: {image_name}")
+        freed = None
     except Exception as e:
         print(f"[red]Error removing Docker image:[/red] {e}")
         log.error(f"Failed to remove Docker image: {e}")
+        freed = None
 
-    print(f"\n[bold green]✓ Environment {name} removed successfully[/bold green]")
\ No newline at end of file
+    print(f"\n[bold green]✓ Environment {name} removed successfully[/bold green]")
+    if freed:
+        print(f"  Freed: {format_size(freed)}")
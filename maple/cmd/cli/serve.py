@@ -14,16 +14,24 @@ The daemon can run in the foreground (blocking) or be detached to run in
 the background as a separate process.
 """
 
+import os
 import json
 import typer 
 import shutil
 import requests
 import subprocess
 from rich import print
-from typing import Optional, Dict, Any
+from pathlib import Path
+from typing import Optional, Dict, Any, List
 from maple.utils.config import get_config
+from maple.utils.logging import LOG_FORMATS
+from maple.utils.paths import models_dir, maple_home
+from maple.utils.lock import running_daemon
 from maple.server.daemon import VLADaemon
-from maple.utils.misc import daemon_url, parse_error_response, load_kwargs
+from maple.cmd.cli.doctor import check_docker, query_gpu_memory
+from maple.server.admission import DEFAULT_MAX_QUEUE
+from maple.cmd.cli.completion import complete_policy_spec
+from maple.utils.misc import daemon_url, parse_error_response, load_kwargs, parse_duration, parse_size
 
 # Create the serve sub-application
 # no_args_is_help=False allows running without subcommand to start daemon
@@ -35,7 +43,19 @@ def serve_root(
     ctx: typer.Context,
     port: int = typer.Option(None, "--port"),
     device: str = typer.Option(None, "--device"),
-    detach: bool = typer.Option(False, "--detach")
+    detach: bool = typer.Option(False, "--detach"),
+    keep_alive: str = typer.Option(None, "--keep-alive", help="Unload idle policies after this duration (e.g., 5m, 1h, -1 = never)"),
+    models_at: Optional[Path] = typer.Option(None, "--models-at", help="Read policy weights from this directory instead of <root>/models (overrides $MAPLE_MODELS_DIR)"),
+    metrics: bool = typer.Option(False, "--metrics", help="Expose Prometheus metrics at /metrics"),
+    max_batch: int = typer.Option(32, "--max-batch", min=1, help="Largest batch accepted by /policy/act_batch"),
+    log_max_size: Optional[str] = typer.Option(None, "--log-max-size", help="Rotate log files at this size (e.g., 10MB, 0 = never)"),
+    log_max_backups: Optional[int] = typer.Option(None, "--log-max-backups", min=1, help="Compressed rotated log files to keep"),
+    log_max_age: Optional[str] = typer.Option(None, "--log-max-age", help="Delete rotated log files older than this (e.g., 7d)"),
+    log_format: Optional[str] = typer.Option(None, "--log-format", help="Daemon log format: text or json"),
+    act_rate: Optional[float] = typer.Option(None, "--act-rate", min=0, min_open=True, help="Act requests per second allowed per client (default: unlimited)"),
+    act_burst: Optional[int] = typer.Option(None, "--act-burst", min=1, help="Act requests a client may send at once (default: --act-rate rounded up)"),
+    max_concurrent_act: Optional[int] = typer.Option(None, "--max-concurrent-act", min=1, help="Act requests run at once (default: one per detected GPU)"),
+    max_act_queue: int = typer.Option(DEFAULT_MAX_QUEUE, "--max-act-queue", min=0, help="Act requests allowed to wait for a slot before 503"),
 ) -> None:
     """
     Start the MAPLE daemon.
@@ -44,61 +64,171 @@ def serve_root(
     and provides the API for running evaluations. Can run in foreground
     (blocking) mode or detached as a background process.
     
-    When detached, the daemon runs in a new session and logs to /tmp/vla.out
-    and /tmp/vla.err.
+    The daemon logs to ~/.maple/logs/server.log (see 'maple logs'). When
+    detached, it runs in a new session and its console output goes to
+    /tmp/vla.out and /tmp/vla.err.
     
     :param ctx: Typer context for checking if subcommand was invoked.
     :param port: Port number for the daemon to listen on.
     :param device: Default device for policy containers (e.g., 'cuda:0', 'cpu').
     :param detach: If True, run daemon in background as separate process.
+    :param keep_alive: Default idle duration before a served policy is unloaded.
+    :param models_at: Directory holding policy weights, e.g. a shared read-only volume.
+    :param metrics: If True, serve request and model metrics at /metrics.
+    :param max_batch: Maximum number of observations per batched act request.
+    :param log_max_size: Size at which server.log and policy logs are rotated.
+    :param log_max_backups: Number of compressed backups kept per log file.
+    :param log_max_age: Age after which rotated log files are deleted.
+    :param log_format: 'text', or 'json' for one JSON object per log line.
+    :param act_rate: Per-client limit on /policy/act requests per second.
+    :param act_burst: Act requests a client may send at once before being limited.
+    :param max_concurrent_act: Act requests run at once. Defaults to the number of GPUs.
+    :param max_act_queue: Act requests queued behind them before new ones get 503.
     """
     config = get_config()
     # If a subcommand was invoked (policy/env), don't start daemon
     if ctx.invoked_subcommand is not None:
         return
+
+    if models_at is not None:
+        # Exported so a detached daemon reads from the same place
+        os.environ["MAPLE_MODELS_DIR"] = str(models_at.expanduser().resolve())
+    if os.environ.get("MAPLE_MODELS_DIR"):
+        weights = models_dir()
+        if not weights.is_dir() or not os.access(weights, os.R_OK | os.X_OK):
+            print(f"[red]Error:[/red] Models directory {weights} does not exist or is not readable")
+            raise typer.Exit(1)
     
     # Use config defaults for unspecified parameters
     port = port or config.daemon.port
     device = device or config.policy.default_device
+    keep_alive = keep_alive or config.policy.keep_alive
+    log_max_size = log_max_size or config.logging.max_size
+    log_max_backups = log_max_backups or config.logging.max_backups
+    log_max_age = log_max_age or config.logging.max_age
+    log_format = log_format or config.logging.format
+
+    if log_format not in LOG_FORMATS:
+        print(f"[red]Error:[/red] Unknown log format '{log_format}', expected one of: {', '.join(LOG_FORMATS)}")
+        raise typer.Exit(1)
+    if act_burst is not None and act_rate is None:
+        print("[red]Error:[/red] --act-burst needs --act-rate")
+        raise typer.Exit(1)
+
+    try:
+        keep_alive_seconds = parse_duration(keep_alive)
+        log_max_bytes = parse_size(log_max_size)
+        log_max_age_seconds = parse_duration(log_max_age)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    # Refuse early, before spawning a detached daemon that would fail anyway
+    running = running_daemon()
+    if running:
+        print(f"[red]Error:[/red] A MAPLE daemon is already running for {maple_home()} (pid {running['pid']}, port {running.get('port', '?')})")
+        print("  Stop it with [cyan]maple stop[/cyan], or use --root for a separate MAPLE home")
+        raise typer.Exit(1)
+
+    # Docker is required to run any policy or environment backend
+    docker_check = check_docker()
+    if not docker_check.passed:
+        print(f"[red]Error:[/red] {docker_check.message}")
+        if docker_check.fix:
+            print(f"  [yellow]Fix:[/yellow] {docker_check.fix}")
+        raise typer.Exit(1)
     
+    # Flags that reproduce this daemon, for detaching and for 'maple restart'
+    serve_args = [
+        "--port",
+        str(port),
+        "--device",
+        device,
+        "--keep-alive",
+        keep_alive,
+        "--max-batch",
+        str(max_batch),
+        "--log-max-size",
+        log_max_size,
+        "--log-max-backups",
+        str(log_max_backups),
+        "--log-format",
+        log_format,
+        "--max-act-queue",
+        str(max_act_queue),
+    ]
+    if log_max_age:
+        serve_args.extend(["--log-max-age", log_max_age])
+    if act_rate:
+        serve_args.extend(["--act-rate", f"{act_rate:g}"])
+    if act_burst:
+        serve_args.extend(["--act-burst", str(act_burst)])
+    # Left out when detected, so a restart on other hardware detects again
+    if max_concurrent_act:
+        serve_args.extend(["--max-concurrent-act", str(max_concurrent_act)])
+    if metrics:
+        serve_args.append("--metrics")
+    if os.environ.get("MAPLE_MODELS_DIR"):
+        serve_args.extend(["--models-at", os.environ["MAPLE_MODELS_DIR"]])
+
     if detach:
         # Detached mode - run daemon in background
-        
-        # Find the 'vla' executable in PATH
-        vla_bin = shutil.which("vla")
-        if vla_bin is None:
-            print("[red]Could not find 'vla' executable in PATH[/red]")
-            raise typer.Exit(1)
-        
-        # Start daemon as a background process with new session
-        # start_new_session=True ensures daemon survives terminal closure
-        subprocess.Popen(
-            [
-                vla_bin,
-                "serve",
-                "--port",
-                str(port),
-                "--device",
-                device,
-            ],
-            stdout=open("/tmp/vla.out", "a"),  # Redirect stdout to log file
-            stderr=open("/tmp/vla.err", "a"),  # Redirect stderr to log file
-            start_new_session=True,  # Detach from current session
-        )
+        start_detached(serve_args)
         print("[green]MAPLE daemon started in background[/green]")
         return
     
+    # One inference per GPU keeps latency predictable; CPU-only hosts get one
+    max_concurrent_act = max_concurrent_act or max(1, len(query_gpu_memory()))
+
     # Foreground mode - run daemon blocking
-    daemon = VLADaemon(port=port, device=device)
+    daemon = VLADaemon(
+        port=port,
+        device=device,
+        keep_alive=keep_alive_seconds,
+        metrics=metrics,
+        max_batch=max_batch,
+        log_max_bytes=log_max_bytes,
+        log_max_backups=log_max_backups,
+        log_max_age=log_max_age_seconds,
+        log_format=log_format,
+        act_rate=act_rate,
+        act_burst=act_burst,
+        max_concurrent_act=max_concurrent_act,
+        max_act_queue=max_act_queue,
+        serve_args=serve_args,
+    )
     daemon.start()
 
+def start_detached(serve_args: List[str]) -> None:
+    """
+    Start the daemon as a background process.
+    
+    The process runs in a new session so it survives the terminal closing,
+    with its console output appended to /tmp/vla.out and /tmp/vla.err.
+    
+    :param serve_args: Flags for 'maple serve', without --detach.
+    """
+    # Find the 'maple' executable in PATH
+    maple_bin = shutil.which("maple")
+    if maple_bin is None:
+        print("[red]Could not find 'maple' executable in PATH[/red]")
+        raise typer.Exit(1)
+
+    subprocess.Popen(
+        [maple_bin, "serve", *serve_args],
+        stdout=open("/tmp/vla.out", "a"),  # Redirect stdout to log file
+        stderr=open("/tmp/vla.err", "a"),  # Redirect stderr to log file
+        start_new_session=True,  # Detach from current session
+    )
+
 @serve_app.command("policy")
 def serve_policy(
-    name: str = typer.Argument(..., help="name (e.g., openvla:latest)"),
+    name: str = typer.Argument(..., help="name (e.g., openvla:latest)", autocompletion=complete_policy_spec),
     port: int = typer.Option(None, "--port"),
     device: str = typer.Option(None, "--device", "-d"),
     host_port: Optional[int] = typer.Option(None, "--host-port", "-p", help="Bind to specific port"),
-    model_load_kwargs: str = typer.Option(None, "--mdl-kwargs", "-m", help="Model-specific loading parameters")
+    model_load_kwargs: str = typer.Option(None, "--mdl-kwargs", "-m", help="Model-specific loading parameters"),
+    keep_alive: Optional[str] = typer.Option(None, "--keep-alive", help="How long to keep the policy loaded when idle (e.g., 5m, 0, -1)"),
 ) -> None:
     """
     Serve a policy model in a container.
@@ -112,6 +242,7 @@ def serve_policy(
     :param device: Device to load policy on (e.g., 'cuda:0', 'cpu').
     :param host_port: Optional specific port to bind the policy container to.
     :param model_load_kwargs: Model-specific loading parameters.
+    :param keep_alive: Idle duration before the policy is unloaded. Defaults to the daemon setting.
     """
     
     config = get_config()
@@ -127,6 +258,8 @@ def serve_policy(
     # Add optional host port if specified
     if host_port is not None:
         payload["host_port"] = host_port
+    if keep_alive is not None:
+        payload["keep_alive"] = keep_alive
     
     # Send serve request to daemon
     r = requests.post(f"{daemon_url(port)}/policy/serve", json=payload)
@@ -145,28 +278,26 @@ def serve_policy(
     for key, val in model_load_kwargs.items():
         print(f"    {key} : {val}")
 
-@serve_app.command("env")
-def serve_env(
-    name: str = typer.Argument(..., help="name (e.g., libero)"),
-    port: int = typer.Option(None, "--port"),
-    device: str = typer.Option(None, "--device", "-d"),
-    num_envs: int = typer.Option(None, "--num-envs", min=1),
-    host_port: Optional[int] = typer.Option(None, "--host-port", "-p", help="Bind to specific port (only with num_envs=1)")
-) -> None:
+def request_envs(
+    name: str,
+    port: Optional[int] = None,
+    device: Optional[str] = None,
+    num_envs: Optional[int] = None,
+    host_port: Optional[int] = None,
+) -> Dict[str, Any]:
     """
-    Serve an environment in a container.
+    Ask the daemon to start environment containers.
     
-    Requests the daemon to start one or more environment containers with
-    the specified environment backend. Multiple instances can be created
-    for parallel evaluation.
+    Shared by 'maple serve env' and 'maple env run'. Options left as None
+    fall back to the config. Exits with an error if the daemon refuses.
     
     :param name: Environment name or image specification.
     :param port: Daemon port number.
     :param device: Device to load env on (e.g., 'cuda:0', 'cpu').
     :param num_envs: Number of environment instances to create.
     :param host_port: Optional specific port (only valid when num_envs=1).
+    :return: Daemon response with 'env_ids', 'ports' and 'num_envs'.
     """
-
     config = get_config()
     # Use config defaults for unspecified parameters
     device = device or config.policy.default_device
@@ -190,9 +321,32 @@ def serve_env(
     if r.status_code != 200:
         print(f"[red]Error:[/red] {parse_error_response(r)}")
         raise typer.Exit(1)
+    return r.json()
+
+@serve_app.command("env")
+def serve_env(
+    name: str = typer.Argument(..., help="name (e.g., libero)"),
+    port: int = typer.Option(None, "--port"),
+    device: str = typer.Option(None, "--device", "-d"),
+    num_envs: int = typer.Option(None, "--num-envs", min=1),
+    host_port: Optional[int] = typer.Option(None, "--host-port", "-p", help="Bind to specific port (only with num_envs=1)")
+) -> None:
+    """
+    Serve an environment in a container.
+    
+    Requests the daemon to start one or more environment containers with
+    the specified environment backend. Multiple instances can be created
+    for parallel evaluation.
+    
+    :param name: Environment name or image specification.
+    :param port: Daemon port number.
+    :param device: Device to load env on (e.g., 'cuda:0', 'cpu').
+    :param num_envs: Number of environment instances to create.
+    :param host_port: Optional specific port (only valid when num_envs=1).
+    """
+    data = request_envs(name, port=port, device=device, num_envs=num_envs, host_port=host_port)
     
     # Display serving confirmation with environment IDs
-    data = r.json()
     print(f"[green]✓ Serving env:[/green] {name} ({data['num_envs']} instance(s))")
     
     # List all created environment instance IDs
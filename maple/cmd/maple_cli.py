@@ -28,22 +28,49 @@ Commands:
 - run: Execute a single episode
 - eval: Run batch evaluations
 - status: Check daemon status
-- stop: Stop the daemon
+- ps: Show loaded policies
+- stop: Stop a policy or the daemon
+- login/logout: Manage registry credentials
+- search: Find policies that can be pulled
+- completion: Print the shell completion script
+- lock/verify-lock: Pin a pulled policy to its exact content
+- annotate: Attach key/value notes to a pulled policy
+- history: Show when each version of a policy was pulled or removed
+- cp: Copy a pulled policy from another MAPLE root
+- bench: Benchmark inference latency and throughput of a policy
+- restart: Restart the daemon, keeping or replacing its serve flags
+- prune: Remove least recently used policies to fit a storage budget
+- create: Build a custom policy from a Modelfile
+- push: Upload a policy to a registry
+- compat: Show which policies support which environments
+- replay: Re-run a trajectory recorded with run --record and compare actions
 """
 
+import os
+import sys
 import json
 import typer 
 import requests
 from rich import print
 from pathlib import Path
-from typing import Optional
+from typing import Dict, Optional, Tuple
 from rich.progress import Progress, SpinnerColumn, TextColumn
 
-from maple.utils.config import get_config, load_config
+from maple.utils.config import get_config, load_config, ConfigError, config_file as default_config_file
 from maple.utils.logging import setup_logging, get_logger
-from maple.utils.misc import daemon_url, parse_error_response, load_kwargs
+from maple.utils.ui import set_quiet, set_color, info, progress_console, NAME_STYLE, SIZE_STYLE
+from maple.utils.auth import save_token, remove_token, normalize_registry, get_token
+from maple.utils.misc import daemon_url, load_kwargs, format_size, estimate_vram, parse_duration
+from maple.utils.lock import running_daemon, stop_process
 from maple.utils.eval import BatchEvaluator, format_results_markdown, format_results_csv
-from maple.cmd.cli import pull_app, serve_app, list_app, env_app, config_app, policy_app, remove_app, sync_app, doctor_app, logs_app
+from maple.api import Client, DaemonError, DaemonNotRunning
+from maple.api.client import CONNECT_TIMEOUT_ENV
+from maple.utils.image import ImageError, load_image_file, policy_image_size, preprocess
+from maple.utils.record import Recorder, frames_dir
+from maple.state import store
+from maple.cmd.cli.lockfile import build_lockfile
+from maple.cmd.cli import pull_app, serve_app, list_app, env_app, config_app, policy_app, remove_app, sync_app, doctor_app, logs_app, ps_app
+from maple.cmd.cli import completion, complete_policy_id, lock, verify_lock, annotate, history, cp, bench, restart, prune, create, push, compat, replay
 
 log = get_logger("cli")
 
@@ -53,9 +80,20 @@ app = typer.Typer(no_args_is_help= True)
 
 @app.callback()
 def main_callback(
+    ctx: typer.Context,
     verbose: bool = typer.Option(False, "--verbose", "-v", help="Enable verbose logging"),
     log_file: Optional[Path] = typer.Option(None, "--log-file", help="Write logs to file"),
     config_file: Optional[Path] = typer.Option(None, "--config", "-c", help="Config file path"),
+    root: Optional[Path] = typer.Option(
+        None, "--root", 
+        help="MAPLE home directory for this invocation (precedence: --root > $MAPLE_HOME > ~/.maple)",
+    ),
+    quiet: bool = typer.Option(False, "--quiet", "-q", help="Hide progress bars, banners and hints"),
+    no_color: bool = typer.Option(False, "--no-color", help="Print without colors (also set by NO_COLOR)"),
+    connect_timeout: Optional[float] = typer.Option(
+        None, "--connect-timeout", min=0.1,
+        help="Seconds to wait for the daemon to accept a connection (default: 3)",
+    ),
 ) -> None:
     """
     Global callback for CLI initialization.
@@ -66,10 +104,35 @@ def main_callback(
     :param verbose: Enable verbose (DEBUG level) logging output.
     :param log_file: Path to write logs to file instead of stderr.
     :param config_file: Path to custom configuration file.
+    :param root: MAPLE home directory overriding $MAPLE_HOME and ~/.maple.
+    :param quiet: Suppress informational output. Errors and results are still printed.
+    :param no_color: Disable colored output. Color is also off when stdout
+                     is not a terminal.
+    :param connect_timeout: Connect timeout for daemon requests, for slow networks.
     """
+    if quiet:
+        set_quiet(True)
+    if no_color:
+        set_color(False)
+    if connect_timeout is not None:
+        os.environ[CONNECT_TIMEOUT_ENV] = str(connect_timeout)
+
+    if root is not None:
+        # Exported so a detached daemon started by this command uses it too
+        os.environ["MAPLE_HOME"] = str(root.expanduser().resolve())
     
     # Load configuration from file (or use defaults)
-    config = load_config(config_file)
+    try:
+        config = load_config(config_file)
+    except ConfigError as e:
+        # Still allow 'maple config ...' so the file can be inspected and fixed
+        if ctx.invoked_subcommand != "config":
+            print(f"[red]Error:[/red] Invalid configuration in {config_file or default_config_file()}:")
+            for message in e.errors:
+                print(f"  - {message}")
+            raise typer.Exit(1)
+        print(f"[yellow]Warning:[/yellow] Invalid configuration: {e}")
+        config = get_config()
     
     # Override logging settings with CLI args
     # Verbose flag takes precedence over config file
@@ -89,13 +152,258 @@ app.add_typer(sync_app, name="sync", help="Update the database if manually delet
 app.add_typer(remove_app, name="remove", help="Remove policy and env")
 app.add_typer(doctor_app, name="doctor", help="Run system diagnostics")
 app.add_typer(logs_app, name="logs", help="View container and daemon logs")
+app.add_typer(ps_app, name="ps", help="Show loaded policies and environments")
+app.command("completion")(completion)
+app.command("lock")(lock)
+app.command("verify-lock")(verify_lock)
+app.command("annotate")(annotate)
+app.command("history")(history)
+app.command("cp")(cp)
+app.command("bench")(bench)
+app.command("restart", context_settings={"allow_extra_args": True, "ignore_unknown_options": True})(restart)
+app.command("prune")(prune)
+app.command("create")(create)
+app.command("push")(push)
+app.command("compat")(compat)
+app.command("replay")(replay)
+
+def _expected_image_size(client: Client, policy_id: str) -> Optional[Tuple[int, int]]:
+    """
+    Ask a serving policy which image size it expects.
+    
+    :param client: Client for the daemon.
+    :param policy_id: Serving policy ID.
+    :return: (width, height), or None if the policy does not report one.
+    """
+    try:
+        return policy_image_size(client.policy_info(policy_id))
+    except (DaemonError, DaemonNotRunning) as e:
+        log.debug(f"Could not read image size of {policy_id}: {e}")
+        return None
+
+def _expected_action_dim(client: Client, policy_id: str) -> Optional[int]:
+    """
+    Ask a serving policy how many values each action has.
+    
+    :param client: Client for the daemon.
+    :param policy_id: Serving policy ID.
+    :return: The policy's action_dim, or None if it does not report one.
+    """
+    try:
+        dim = client.policy_info(policy_id).get("action_dim")
+    except (DaemonError, DaemonNotRunning) as e:
+        log.debug(f"Could not read action dimension of {policy_id}: {e}")
+        return None
+    return dim if isinstance(dim, int) and not isinstance(dim, bool) else None
+
+def _check_action_dim(event: Dict, action_dim: Optional[int]) -> None:
+    """
+    Stop a JSON run whose policy returns actions of the wrong length.
+    
+    A controller reading the stream would otherwise act on a malformed
+    command. Action chunks are checked row by row.
+    
+    :param event: Step event from the daemon.
+    :param action_dim: Expected action length, or None to skip the check.
+    """
+    action = event.get("action")
+    if action_dim is None or not isinstance(action, list):
+        return
+    rows = action if action and isinstance(action[0], list) else [action]
+    for row in rows:
+        if len(row) != action_dim:
+            typer.echo(
+                f"Error: Step {event.get('step')} returned an action of length {len(row)}, "
+                f"but the policy's action_dim is {action_dim}",
+                err=True,
+            )
+            raise typer.Exit(1)
+
+def _act_once(
+    client: Client,
+    policy_id: str,
+    image: Path,
+    instruction: str,
+    model_kwargs: Optional[Dict],
+    keep_alive: Optional[str],
+    output: str,
+) -> None:
+    """
+    Run one inference on an image and print the action.
+    
+    :param client: Client for the daemon.
+    :param policy_id: Serving policy ID.
+    :param image: Image file.
+    :param instruction: Language instruction.
+    :param model_kwargs: Model-specific parameters.
+    :param keep_alive: Idle duration before the policy is unloaded.
+    :param output: 'text' or 'json'.
+    """
+    try:
+        img = load_image_file(image)
+        size = _expected_image_size(client, policy_id)
+        if size is not None and img.size != size:
+            print(f"[yellow]Warning:[/yellow] Cropping and resizing image from {img.size[0]}x{img.size[1]} to {size[0]}x{size[1]}", file=sys.stderr)
+        payload = {
+            "policy_id": policy_id,
+            "image": preprocess(img, size),
+            "instruction": instruction,
+            "model_kwargs": model_kwargs or {},
+        }
+        if keep_alive is not None:
+            payload["keep_alive"] = keep_alive
+        action = client.act(payload)["action"]
+    except ImageError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    if output == "json":
+        typer.echo(json.dumps({"action": action}))
+    else:
+        print(f"[cyan]Action:[/cyan] {action}")
+
+def _run_json(client: Client, payload: Dict, timeout: int) -> None:
+    """
+    Stream a run as JSON lines for piping into a controller.
+    
+    Prints one {"step", "action", "timestamp"} object per inference step
+    and then the final 'success' event with the episode results. Stdout
+    carries nothing else; errors go to stderr. The run stops with an error
+    if an action's length differs from the policy's action_dim; policies
+    that do not report one are not checked.
+    
+    :param client: Client for the daemon.
+    :param payload: Run request body.
+    :param timeout: Request timeout in seconds.
+    """
+    action_dim = _expected_action_dim(client, payload["policy_id"])
+    try:
+        for event in client.run_events(payload, timeout=timeout):
+            if event.get("status") == "error":
+                typer.echo(f"Error: {event.get('error')}", err=True)
+                raise typer.Exit(1)
+            _check_action_dim(event, action_dim)
+            typer.echo(json.dumps(event))
+    except requests.exceptions.Timeout:
+        typer.echo(f"Error: Request timed out after {timeout}s", err=True)
+        raise typer.Exit(1)
+    except (DaemonError, DaemonNotRunning) as e:
+        typer.echo(f"Error: {e}", err=True)
+        raise typer.Exit(1)
+
+def _policy_identity(client: Client, policy_id: str) -> Dict:
+    """
+    Describe the weights behind a serving policy for a trajectory header.
+    
+    The digest is the one 'maple lock' writes, so a record can be checked
+    against a lockfile. Computing it reads every weight file once.
+    
+    :param client: Client for the daemon.
+    :param policy_id: Serving policy ID.
+    :return: Dictionary with 'policy' (name:version), 'repo', 'revision'
+            and 'digest'; fields that cannot be determined are None.
+    """
+    serving = next((p for p in client.ps().get("policies", []) if p["policy_id"] == policy_id), None)
+    if serving is None:
+        return {"policy": None, "repo": None, "revision": None, "digest": None}
+
+    name, version = serving["backend"], serving["version"]
+    pulled = store.get_policy(name, version) or {}
+    try:
+        digest = build_lockfile(name, version)["digest"]
+    except ValueError as e:
+        print(f"[yellow]Warning:[/yellow] Recording without a weights digest: {e}", file=sys.stderr)
+        digest = None
+    return {"policy": f"{name}:{version}", "repo": pulled.get("repo"), "revision": pulled.get("revision"), "digest": digest}
+
+def _run_recorded(client: Client, payload: Dict, timeout: int, record: Path, output: str) -> Dict:
+    """
+    Run an episode and append its trajectory to a JSONL file.
+    
+    The daemon saves each step's policy input image next to the file, so
+    the daemon must be able to write there.
+    
+    :param client: Client for the daemon.
+    :param payload: Run request body.
+    :param timeout: Request timeout in seconds.
+    :param record: Trajectory file to append to.
+    :param output: 'json' to also print each step as a JSON line.
+    :return: Episode result.
+    """
+    record = record.expanduser().resolve()
+    action_dim = _expected_action_dim(client, payload["policy_id"]) if output == "json" else None
+    try:
+        identity = _policy_identity(client, payload["policy_id"])
+        with Recorder(record) as recorder:
+            recorder.header(
+                policy_id=payload["policy_id"],
+                **identity,
+                env_id=payload["env_id"],
+                task=payload["task"],
+                seed=payload.get("seed"),
+                max_steps=payload["max_steps"],
+                model_kwargs=payload.get("model_kwargs", {}),
+            )
+            for event in client.run_events({**payload, "record_dir": str(frames_dir(record))}, timeout=timeout):
+                if event.get("status") == "error":
+                    print(f"[red]Error:[/red] {event.get('error')}", file=sys.stderr)
+                    raise typer.Exit(1)
+                if output == "json":
+                    _check_action_dim(event, action_dim)
+                    typer.echo(json.dumps(event))
+                if event.get("status") == "success":
+                    result = {k: v for k, v in event.items() if k != "status"}
+                    recorder.result(result)
+                    return result
+                recorder.step(event["step"], event["timestamp"], event.get("instruction", ""), event.get("image"), event["action"])
+    except requests.exceptions.Timeout:
+        print(f"[red]Error:[/red] Request timed out after {timeout}s", file=sys.stderr)
+        raise typer.Exit(1)
+    except (DaemonError, DaemonNotRunning) as e:
+        print(f"[red]Error:[/red] {e}", file=sys.stderr)
+        raise typer.Exit(1)
+
+    print("[red]Error:[/red] Run ended without a result", file=sys.stderr)
+    raise typer.Exit(1)
+
+def _print_run_result(result: Dict) -> None:
+    """
+    Print the summary of an episode.
+    
+    :param result: Episode result from the daemon.
+    """
+    # Display success/failure status
+    success = result.get("success", False)
+    if success:
+        print(f"\n[bold green]✓ Task completed successfully![/bold green]")
+    else:
+        print(f"\n[yellow]Task finished (not successful)[/yellow]")
+    
+    # Display detailed results
+    print(f"\n[cyan]Results:[/cyan]")
+    print(f"  Run ID: {result.get('run_id')}")
+    print(f"  Steps: {result.get('steps')}")
+    print(f"  Total Reward: {result.get('total_reward', 0):.4f}")
+    print(f"  Terminated: {result.get('terminated')}")
+    print(f"  Truncated: {result.get('truncated')}")
+    
+    # Show video path if video was saved
+    if result.get("video_path"):
+        print(f"  Video saved: {result.get('video_path')}")
 
 @app.command("run")
 def run(
-    policy_id: str = typer.Argument(..., help="Policy ID (e.g., openvla-7b-a1b2c3d4)"),
-    env_id: str = typer.Argument(..., help="Environment ID (e.g., libero-x1y2z3w4)"),
-    task: str = typer.Option(..., "--task", "-t", help="Task spec (e.g., libero_10/0)"),
+    policy_id: str = typer.Argument(..., help="Policy ID (e.g., openvla-7b-a1b2c3d4)", autocompletion=complete_policy_id),
+    env_id: Optional[str] = typer.Argument(None, help="Environment ID (e.g., libero-x1y2z3w4). Omit with --image"),
+    task: Optional[str] = typer.Option(None, "--task", "-t", help="Task spec (e.g., libero_10/0)"),
     instruction: Optional[str] = typer.Option(None, "--instruction", "-i", help="Override task instruction"),
+    image: Optional[Path] = typer.Option(None, "--image", help="Run one inference on this image instead of an episode (needs --instruction)"),
     max_steps: int = typer.Option(None, "--max-steps", "-m", help="Maximum steps per episode"),
     seed: Optional[int] = typer.Option(None, "--seed", "-s", help="Random seed"),
     env_kwargs: str = typer.Option(None, "--env-kwargs", "-e", help="Env-specific parameters"),
@@ -103,6 +411,10 @@ def run(
     save_video: bool = typer.Option(False, "--save-video", "-v", help="Save rollout video"),
     video_dir: Optional[str] = typer.Option(None, "--video-path", help="Custom video output path"),
     timeout: Optional[int] = typer.Option(None, "--timeout", help="Constant multiplied with the max_steps to determine the timeout"),
+    keep_alive: Optional[str] = typer.Option(None, "--keep-alive", help="How long to keep the policy loaded after the run (e.g., 5m, 0)"),
+    force: bool = typer.Option(False, "--force", help="Run even if the policy has no adapter for this environment"),
+    output: str = typer.Option("text", "--output", "-o", help="Output format: text, or json for one action per line"),
+    record: Optional[Path] = typer.Option(None, "--record", help="Append each step's input image, instruction and action to this JSONL file"),
     port: int = typer.Option(None, "--port"),
 ) -> None:
     """
@@ -113,10 +425,19 @@ def run(
     real-time progress and results including success status, steps taken,
     rewards, and video paths.
     
+    With --image, no environment is needed: the policy runs once on the
+    image and instruction and the predicted action is printed.
+    
+    With --record, the trajectory is appended to a JSONL file: a header
+    with the policy's revision and weights digest, one line per step with
+    the input image saved next to the file, and the result. 'maple replay'
+    feeds the recorded inputs to a policy again.
+    
     :param policy_id: Identifier of the policy container to use.
     :param env_id: Identifier of the environment container to use.
     :param task: Task specification string.
     :param instruction: Optional instruction to override default task instruction.
+    :param image: Image file for single-shot inference without an environment.
     :param max_steps: Maximum number of steps before truncation.
     :param seed: Random seed for reproducibility.
     :param env_kwargs: Model-specific parameters.
@@ -124,10 +445,39 @@ def run(
     :param save_video: Whether to record and save episode video.
     :param video_dir: Directory path for saving videos.
     :param timeout: Timeout multiplier for HTTP request.
+    :param keep_alive: Idle duration before the policy is unloaded after the run.
+    :param force: Skip the policy/environment compatibility check.
+    :param output: 'text' for a summary, or 'json' to stream each step as a JSON line.
+    :param record: Trajectory file to append the run to.
     :param port: Daemon port number.
     """
     config = get_config()
 
+    if output not in ("text", "json"):
+        print(f"[red]Error:[/red] Unknown output format '{output}' (expected text or json)")
+        raise typer.Exit(1)
+
+    if image is not None:
+        if env_id is not None or task is not None:
+            print("[red]Error:[/red] --image runs without an environment; drop ENV_ID and --task")
+            raise typer.Exit(1)
+        if not instruction:
+            print("[red]Error:[/red] --image needs --instruction")
+            raise typer.Exit(1)
+        if record is not None:
+            print("[red]Error:[/red] --record records episodes; drop --image")
+            raise typer.Exit(1)
+        _act_once(
+            Client.from_config(port), policy_id, image, instruction,
+            model_kwargs=load_kwargs(model_kwargs) or config.policy.model_kwargs,
+            keep_alive=keep_alive,
+            output=output,
+        )
+        return
+    if env_id is None or task is None:
+        print("[red]Error:[/red] Missing ENV_ID and --task (or use --image for single-shot inference)")
+        raise typer.Exit(1)
+
     env_kwargs = load_kwargs(env_kwargs)    
     env_kwargs = env_kwargs or config.env.env_kwargs
 
@@ -161,54 +511,48 @@ def run(
         payload["model_kwargs"] = model_kwargs
     if video_dir:
         payload["video_dir"] = video_dir
-    
+    if keep_alive is not None:
+        payload["keep_alive"] = keep_alive
+    if force:
+        payload["force"] = True
+    
+    if record is not None:
+        if output == "text":
+            info(f"Recording to {record}")
+        result = _run_recorded(Client.from_config(port), payload, int(max_steps * timeout), record, output)
+        if output == "text":
+            _print_run_result(result)
+        return
+
+    if output == "json":
+        _run_json(Client.from_config(port), payload, timeout=int(max_steps * timeout))
+        return
+
     # Execute the run with a progress indicator
     try:
-        with Progress(SpinnerColumn(), TextColumn("[progress.description]{task.description}")) as progress:
+        with Progress(SpinnerColumn(), TextColumn("[progress.description]{task.description}"), console=progress_console()) as progress:
             task = progress.add_task(f"Running policy on task...")
             # Display run configuration
-            print(f"  Policy: {policy_id}")
-            print(f"  Env: {env_id}")
-            print(f"  Task: {task}")
-            print(f"  Max steps: {max_steps}")
+            info(f"  Policy: {policy_id}")
+            info(f"  Env: {env_id}")
+            info(f"  Task: {task}")
+            info(f"  Max steps: {max_steps}")
             
-            # Send POST request to daemon with generous timeout
+            # Send run request to daemon with generous timeout
             # Timeout is max_steps * timeout_multiplier to allow long episodes
-            r = requests.post(
-                f"{daemon_url(port)}/run",
-                json=payload,
-                timeout=int(max_steps * timeout),
-            )
+            result = Client.from_config(port).run(payload, timeout=int(max_steps * timeout))
     except requests.exceptions.Timeout:
         # Handle timeout gracefully
         print(f"[red]Error:[/red] Request timed out after {max_steps * timeout}s")
         raise typer.Exit(1)
-
-    # Check for HTTP errors
-    if r.status_code != 200:
-        print(f"[red]Error:[/red] {parse_error_response(r)}")
+    except DaemonNotRunning as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
         raise typer.Exit(1)
-
-    result = r.json()
-    
-    # Display success/failure status
-    success = result.get("success", False)
-    if success:
-        print(f"\n[bold green]✓ Task completed successfully![/bold green]")
-    else:
-        print(f"\n[yellow]Task finished (not successful)[/yellow]")
-    
-    # Display detailed results
-    print(f"\n[cyan]Results:[/cyan]")
-    print(f"  Run ID: {result.get('run_id')}")
-    print(f"  Steps: {result.get('steps')}")
-    print(f"  Total Reward: {result.get('total_reward', 0):.4f}")
-    print(f"  Terminated: {result.get('terminated')}")
-    print(f"  Truncated: {result.get('truncated')}")
     
-    # Show video path if video was saved
-    if result.get("video_path"):
-        print(f"  Video saved: {result.get('video_path')}")
+    _print_run_result(result)
 
 @app.command("status")
 def status(port: int = typer.Option(None, "--port")) -> None:
@@ -221,46 +565,197 @@ def status(port: int = typer.Option(None, "--port")) -> None:
     :param port: Daemon port number.
     """
 
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
-    
     try:
         # Try to connect to daemon with short timeout
-        r = requests.get(f"http://0.0.0.0:{port}/status", timeout=1)
-        data = r.json()
+        data = Client.from_config(port).status(timeout=1)
         print("[bold green]MAPLE daemon running[/bold green]")
         print(data)
-    except requests.exceptions.ConnectionError:
+    except DaemonNotRunning:
         # Daemon not reachable
         print("[red]MAPLE daemon not running[/red]")
+    except DaemonError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
 
 @app.command("stop")
-def stop(port: int = typer.Option(None, "--port")) -> None:
+def stop(
+    policy: Optional[str] = typer.Argument(None, help="Policy ID or spec to unload (e.g., openvla:7b). Omit to stop the daemon", autocompletion=complete_policy_id),
+    port: int = typer.Option(None, "--port"),
+    timeout: str = typer.Option("30s", "--timeout", help="How long to wait for a graceful shutdown before killing the daemon"),
+) -> None:
     """
-    Stop the MAPLE daemon.
+    Stop a running policy or the MAPLE daemon.
     
-    Sends a shutdown request to the daemon, stopping all managed containers
-    and terminating the daemon process.
+    With a policy argument, unloads that policy and frees its memory while
+    the daemon keeps running. Without one, sends SIGTERM to the daemon
+    recorded in ~/.maple/maple.pid, which stops all managed containers and
+    exits. If it is still running after the timeout it is killed.
     
+    :param policy: Optional policy ID or name:version spec to unload.
     :param port: Daemon port number.
+    :param timeout: Grace period before SIGKILL (e.g., 30s, 2m).
     """
 
-    config = get_config()
-    # Use config default if port not specified
-    port = port or config.daemon.port
-    
+    client = Client.from_config(port)
+
+    if policy:
+        try:
+            data = client.stop_policy(policy)
+        except DaemonNotRunning as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        except DaemonError as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+
+        for policy_id in data.get("stopped", []):
+            print(f"[green]✓ Stopped policy:[/green] {policy_id}")
+        if data.get("freed_memory"):
+            print(f"  Freed memory: ~{format_size(data['freed_memory'])}")
+        return
+
     try:
-        # Send stop request to daemon
-        requests.post(f"{daemon_url(port)}/stop")
-        print("[green]MAPLE daemon stopped[/green]")
-    except requests.exceptions.ConnectionError:
-        # Daemon already stopped or not running
-        print("[red]Daemon not running[/red]")
+        grace = parse_duration(timeout)
+    except ValueError as e:
+        print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    running = running_daemon()
+    if running is None:
+        # No daemon for this root; one started elsewhere may still answer on the port
+        try:
+            client.shutdown()
+            print("[green]MAPLE daemon stopped[/green]")
+        except DaemonNotRunning:
+            print("[yellow]No MAPLE daemon running[/yellow]")
+        except DaemonError as e:
+            print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        return
+
+    pid = running["pid"]
+    print(f"Stopping MAPLE daemon (pid {pid})...")
+    if stop_process(pid, timeout=grace if grace is not None else float("inf")):
+        print("[green]✓ MAPLE daemon stopped cleanly[/green]")
+    else:
+        print(f"[yellow]MAPLE daemon did not shut down within {timeout} and was killed[/yellow]")
+        print("  Containers it managed may still be running; check with [cyan]docker ps[/cyan]")
+
+@app.command("login")
+def login(
+    registry: Optional[str] = typer.Argument(None, help="Registry host (default: from config, typically huggingface.co)"),
+    token: Optional[str] = typer.Option(None, "--token", help="Access token (prompted if omitted)"),
+) -> None:
+    """
+    Store an access token for a registry.
+    
+    Saves the token to ~/.maple/auth.json (readable only by you) so pulls
+    from private repositories are authenticated automatically.
+    
+    :param registry: Registry host to log in to.
+    :param token: Access token. Prompted for without echo when not given.
+    """
+    registry = registry or get_config().policy.registry
+
+    if token is None:
+        token = typer.prompt("Token", hide_input=True)
+
+    token = token.strip()
+    if not token:
+        print("[red]Error:[/red] Token cannot be empty")
+        raise typer.Exit(1)
+
+    host = save_token(registry, token)
+    print(f"[green]✓ Logged in to[/green] {host}")
+
+@app.command("logout")
+def logout(
+    registry: Optional[str] = typer.Argument(None, help="Registry host (default: from config, typically huggingface.co)"),
+) -> None:
+    """
+    Remove the stored access token for a registry.
+    
+    :param registry: Registry host to log out of.
+    """
+    host = normalize_registry(registry or get_config().policy.registry)
+
+    if not remove_token(host):
+        print(f"[yellow]Not logged in to[/yellow] {host}")
+        return
+
+    print(f"[green]✓ Logged out of[/green] {host}")
+
+@app.command("search")
+def search(
+    query: Optional[str] = typer.Argument(None, help="Text to search for (omit to list featured models)"),
+    arch: Optional[str] = typer.Option(None, "--arch", help="Only show models served by this backend (e.g., openvla)"),
+    limit: int = typer.Option(20, "--limit", min=1, help="Maximum number of results"),
+) -> None:
+    """
+    Search for policies that can be pulled.
+    
+    Without a query, lists the featured models built into MAPLE. With a
+    query, matches the built-in catalog and searches the HuggingFace Hub
+    for repos whose architecture a MAPLE backend can serve.
+    
+    :param query: Optional search text.
+    :param arch: Optional backend name to filter by.
+    :param limit: Maximum number of rows to print.
+    """
+    from rich.table import Table
+    from maple.backend.registry import POLICY_BACKENDS, policy_catalog, hub_entry
+
+    if arch and arch not in POLICY_BACKENDS:
+        print(f"[red]Error:[/red] Unknown architecture '{arch}'. Available: {', '.join(POLICY_BACKENDS)}")
+        raise typer.Exit(1)
+
+    results = policy_catalog()
+    if query:
+        text = query.lower()
+        results = [
+            entry for entry in results 
+            if text in entry["name"].lower() or text in entry["description"].lower()
+        ]
+
+        try:
+            from maple.utils.download import search_repos
+            token = os.environ.get("HF_TOKEN") or get_token("huggingface.co")
+            for repo_id in search_repos(query, limit=limit, token=token):
+                entry = hub_entry(repo_id)
+                if entry:
+                    results.append(entry)
+        except Exception as e:
+            log.debug(f"HuggingFace search failed: {e}")
+            print("[yellow]Warning:[/yellow] Could not reach the HuggingFace Hub, showing built-in models only")
+
+    if arch:
+        results = [entry for entry in results if entry["arch"] == arch]
+    results = results[:limit]
+
+    if not results:
+        print(f"[yellow]No models found[/yellow]{f' matching {query!r}' if query else ''}")
+        return
+
+    table = Table(title="Featured Models" if not query else None)
+    table.add_column("NAME", style=NAME_STYLE)
+    table.add_column("ARCH")
+    table.add_column("SIZE", style=SIZE_STYLE)
+    table.add_column("VRAM")
+    table.add_column("DESCRIPTION")
+    for entry in results:
+        vram = estimate_vram(entry["size"])
+        table.add_row(
+            entry["name"], 
+            entry["arch"], 
+            entry["size"] or "-", 
+            f"~{format_size(vram)}" if vram else "-",
+            entry["description"] or "-",
+        )
+    print(table)
 
 @app.command("eval")
 def eval_cmd(
-    policy_id: str = typer.Argument(..., help="Policy ID (e.g., openvla-7b-a1b2c3d4)"),
+    policy_id: str = typer.Argument(..., help="Policy ID (e.g., openvla-7b-a1b2c3d4)", autocompletion=complete_policy_id),
     env_id: str = typer.Argument(..., help="Environment ID (e.g., libero-x1y2z3w4)"),
     backend: str = typer.Argument(..., help="Environment backend name"),
     tasks: str = typer.Option(..., "--tasks", "-t", help="Tasks (comma-separated or suite name like libero_10)"),
@@ -274,6 +769,7 @@ def eval_cmd(
     output: Optional[Path] = typer.Option(None, "--output", "-o", help="Output directory for results"),
     format: str = typer.Option("json", "--format", "-f", help="Output format: json, markdown, csv"),
     parallel: int = typer.Option(1, "--parallel", "-p", help="Parallel evaluations (experimental)"),
+    force: bool = typer.Option(False, "--force", help="Run even if the policy has no adapter for this environment"),
     port: int = typer.Option(None, "--port"),
 ) -> None:
     """
@@ -300,6 +796,7 @@ def eval_cmd(
     :param output: Output directory for results files.
     :param format: Output format (json, markdown, csv, or all).
     :param parallel: Number of parallel evaluations to run.
+    :param force: Skip the policy/environment compatibility check.
     :param port: Daemon port number.
     """
     
@@ -348,23 +845,24 @@ def eval_cmd(
         raise typer.Exit(1)
     
     total_episodes = len(task_list) * len(seed_list)
-    print(f"\n[bold cyan]Batch Evaluation[/bold cyan]")
-    print(f"  Policy: {policy_id}")
-    print(f"  Environment: {env_id}")
-    print(f"  Tasks: {len(task_list)}")
-    print(f"  Seeds: {seed_list}")
-    print(f"  Total episodes: {total_episodes}")
-    print(f"  Max steps: {max_steps}")
+    info(f"\n[bold cyan]Batch Evaluation[/bold cyan]")
+    info(f"  Policy: {policy_id}")
+    info(f"  Environment: {env_id}")
+    info(f"  Tasks: {len(task_list)}")
+    info(f"  Seeds: {seed_list}")
+    info(f"  Total episodes: {total_episodes}")
+    info(f"  Max steps: {max_steps}")
     if save_video:
-        print(f"  Videos: {video_dir}")
-    print()
+        info(f"  Videos: {video_dir}")
+    info()
     
-    evaluator = BatchEvaluator(daemon_url=daemon_url(port))
+    evaluator = BatchEvaluator(daemon_url=daemon_url(port), force=force)
     
     try:
         with Progress(
             SpinnerColumn(),
             TextColumn("[progress.description]{task.description}"),
+            console=progress_console(),
         ) as progress:
             task = progress.add_task(f"Running {total_episodes} episodes...", total=total_episodes)
             
@@ -0,0 +1,88 @@
+"""
+Concurrency limit for inference requests.
+
+A GPU runs one or two inferences at a time efficiently; more only make
+every request slower. ActQueue lets a fixed number of act requests run at
+once and holds the rest in a bounded FIFO queue, rejecting requests once
+the queue is full so callers can back off instead of piling up.
+
+Requests wait on a thread of the server's pool, so the queue should stay
+well below the pool's size (40 by default) or waiting requests would
+starve every other endpoint.
+"""
+
+import threading
+from collections import deque
+from contextlib import contextmanager
+from typing import Dict, Iterator
+
+# Default number of act requests allowed to wait for a slot
+DEFAULT_MAX_QUEUE = 16
+
+class QueueFull(Exception):
+    """Raised when an act request arrives while the queue is full."""
+
+class ActQueue:
+    """
+    Thread-safe slots for concurrent act requests with a bounded wait queue.
+    """
+
+    def __init__(self, max_concurrent: int, max_queue: int = DEFAULT_MAX_QUEUE):
+        """
+        Initialize the queue.
+
+        :param max_concurrent: Act requests allowed to run at once.
+        :param max_queue: Act requests allowed to wait for a slot. 0 rejects
+                          every request that cannot start immediately.
+        """
+        if max_concurrent < 1:
+            raise ValueError(f"max_concurrent must be at least 1, got {max_concurrent}")
+        if max_queue < 0:
+            raise ValueError(f"max_queue must not be negative, got {max_queue}")
+        self.max_concurrent = max_concurrent
+        self.max_queue = max_queue
+        self._active = 0
+        self._waiting: deque = deque()  # One event per queued request, oldest first
+        self._lock = threading.Lock()
+
+    @contextmanager
+    def slot(self) -> Iterator[None]:
+        """
+        Hold a slot for one act request, waiting in turn if all are taken.
+
+        :raises QueueFull: If every slot is taken and the queue is full.
+        """
+        with self._lock:
+            if self._active < self.max_concurrent and not self._waiting:
+                self._active += 1
+                turn = None
+            elif len(self._waiting) >= self.max_queue:
+                raise QueueFull(f"Act queue is full ({len(self._waiting)} waiting, {self._active} running)")
+            else:
+                turn = threading.Event()
+                self._waiting.append(turn)
+        if turn is not None:
+            # The releasing request hands its slot over, so _active is already counted
+            turn.wait()
+        try:
+            yield
+        finally:
+            with self._lock:
+                if self._waiting:
+                    self._waiting.popleft().set()
+                else:
+                    self._active -= 1
+
+    def stats(self) -> Dict[str, int]:
+        """
+        Get the current load.
+
+        :return: Dictionary with 'running', 'queued', 'max_concurrent' and 'max_queue'.
+        """
+        with self._lock:
+            return {
+                "running": self._active,
+                "queued": len(self._waiting),
+                "max_concurrent": self.max_concurrent,
+                "max_queue": self.max_queue,
+            }
@@ -19,38 +19,93 @@ Key components:
 - Policy and environment backend management
 - Run orchestration with video recording
 - Signal handling for graceful shutdown
+- Liveness and readiness probes at /healthz and /readyz
+- Optional Prometheus metrics at /metrics
+- Log files under ~/.maple/logs (server.log and one <model>.log per policy)
+- An access log line per request, as text or JSON (--log-format)
+- Optional per-client rate limit on the act endpoints (--act-rate)
+- A concurrency limit on inference, queuing excess act requests
 """
 
 import os
 import sys
+import json
+import math
 import uuid
+import queue
 import time
 import numpy as np
 import mediapy
 import signal
+import docker
 import uvicorn
+import tempfile
+import ipaddress
 import threading
 from tqdm import tqdm
 from rich import print
+from PIL import Image
 from pathlib import Path
 from pydantic import BaseModel
-from fastapi import FastAPI, HTTPException
-from typing import Optional, List, Dict, Any
-
-from maple.state import store
-from maple.adapters import get_adapter
-from maple.utils.paths import policy_dir
-from maple.utils.logging import get_logger
-from maple.utils.spec import parse_versioned
+from fastapi import FastAPI, HTTPException, Request
+from fastapi.responses import StreamingResponse, Response, JSONResponse
+from typing import Optional, List, Dict, Any, Callable, Iterable, Iterator, Tuple
+
+from maple import __version__
+from maple.state import store, listing
+from maple.adapters import get_adapter, has_adapter, supported_envs
+from maple.utils.paths import policy_dir, maple_home, models_dir, dir_size
+from maple.utils.hub import hub_for
+from maple.utils.config import get_config
+from maple.utils.modelfile import Modelfile, PolicyExistsError, create_policy
+from maple.utils.upload import upload_repo
+from maple.utils.auth import get_token, normalize_registry
+from maple.utils.download import bytes_transferred
+from maple.utils.logging import get_logger, enable_server_logs, DEFAULT_MAX_BYTES, DEFAULT_BACKUP_COUNT
+from maple.utils.image import ImageError, decode_base64_image
+from maple.utils.misc import parse_duration
+from maple.utils.ui import info
+from maple.utils.spec import parse_versioned, parse_hf_spec, parse_pinned
 from maple.backend.envs.base import EnvHandle
 from maple.backend.policy.base import PolicyHandle
 from maple.utils.health import HealthMonitor, HealthStatus
-from maple.utils.lock import DaemonLock, is_daemon_running
-from maple.backend.registry import POLICY_BACKENDS, ENV_BACKENDS
+from maple.utils.lock import DaemonLock, PidFile, is_daemon_running, lock_ref, read_pid_file
+from maple.server.pulls import PullRegistry, PullJob
+from maple.server.metrics import Metrics, CONTENT_TYPE
+from maple.server.ratelimit import RateLimiter, client_key
+from maple.server.admission import ActQueue, QueueFull, DEFAULT_MAX_QUEUE
+from maple.backend.registry import POLICY_BACKENDS, ENV_BACKENDS, infer_policy_backend
 from maple.utils.cleanup import CleanupManager, register_cleanup_handler
 from maple.utils.timeout import run_with_timeout, TimeoutError, OperationTimer
 
 log = get_logger("daemon")
+access_log = get_logger("access")
+
+# Seconds between writes of a serving policy's last use to the store
+USAGE_RECORD_INTERVAL = 60.0
+
+# Paths polled by probes and scrapers, left out of the access log
+QUIET_PATHS = ("/healthz", "/readyz", "/metrics")
+
+# Endpoints limited by --act-rate; a batch counts as one request
+RATE_LIMITED_PATHS = ("/policy/act", "/policy/act_batch")
+
+def is_local_request(request: Request) -> bool:
+    """
+    Check whether a request comes from the daemon's own host.
+    
+    The daemon listens on every interface and has no authentication, so
+    endpoints that act with the owner's credentials or read arbitrary
+    files serve only loopback clients.
+    
+    :param request: Incoming request.
+    :return: True if the client address is a loopback address.
+    """
+    host = request.client.host if request.client else None
+    try:
+        return ipaddress.ip_address(host).is_loopback
+    except ValueError:
+        return False
 
 class RunRequest(BaseModel):
     """Request model for running a policy on an environment task."""
@@ -67,10 +122,42 @@ class RunRequest(BaseModel):
     video_dir: Optional[str] = None
     step_timeout: float = 60.0  # Timeout per step in seconds
     setup_timeout: float = 30.0  # Timeout for env setup/reset
+    keep_alive: Optional[str] = None  # e.g., "5m", "0" to unload after the run
+    force: bool = False  # Run even if no adapter exists for the policy/env pair
+    stream: bool = False  # Stream each step's action as NDJSON before the result
+    record_dir: Optional[str] = None  # Save each step's policy input image under <record_dir>/<run_id>
 
 class PullPolicyRequest(BaseModel):
     """Request model for pulling a policy."""
-    spec: str  # e.g., "openvla:7b"
+    spec: str  # e.g., "openvla:7b" or "hf.co/openvla/openvla-7b"
+    hf_token: Optional[str] = None  # For private HuggingFace repos
+    stream: bool = False  # Stream NDJSON progress events instead of a single response
+    concurrency: int = 3  # Maximum files downloaded at once
+    max_retries: int = 3  # Retries per file for transient network errors
+    dry_run: bool = False  # Only report what would be downloaded
+    registry: Optional[str] = None  # Registry host, defaults to policy.registry
+    insecure: Optional[bool] = None  # Skip TLS verification of the registry
+    ca_cert: Optional[str] = None  # Extra CA certificates (PEM) trusted for the registry
+    proxy: Optional[str] = None  # Proxy for registry requests, overriding HTTP(S)_PROXY
+
+class CreatePolicyRequest(BaseModel):
+    """Request model for creating a policy from a pulled base."""
+    name: str  # e.g., "openvla:kitchen-ft" or a bare version of the base backend
+    base: str  # Pulled policy to start from, like a Modelfile's FROM
+    weights: List[str] = []  # Files or directories on the daemon host laid over the base
+    config: Dict[str, Any] = {}  # Keys set in the policy's config.json
+    labels: Dict[str, str] = {}  # Annotations stored with the policy
+    overwrite: bool = False  # Replace the policy if it already exists
+    stream: bool = False  # Stream NDJSON progress events instead of a single response
+
+class PushPolicyRequest(BaseModel):
+    """Request model for pushing a policy to a registry."""
+    spec: str  # e.g., "openvla:kitchen-ft"
+    repo: str  # Target repo, e.g., "myorg/openvla-kitchen"
+    registry: Optional[str] = None  # Registry host, defaults to policy.registry
+    revision: Optional[str] = None  # Branch to push to, defaults to main
+    message: Optional[str] = None  # Commit message
+    stream: bool = False  # Stream NDJSON progress events instead of a single response
 
 class ServePolicyRequest(BaseModel):
     """Request model for serving a policy container."""
@@ -78,6 +165,7 @@ class ServePolicyRequest(BaseModel):
     device: str = "cpu"
     host_port: Optional[int] = None
     model_load_kwargs: Optional[Dict[str, Any]] = {}
+    keep_alive: Optional[str] = None  # e.g., "5m", "-1" to keep loaded forever
 
 class ActRequest(BaseModel):
     """Request model for single policy inference."""
@@ -85,6 +173,7 @@ class ActRequest(BaseModel):
     image: str  # base64 encoded
     instruction: str
     model_kwargs: Optional[Dict[str, Any]] = {}
+    keep_alive: Optional[str] = None  # e.g., "5m", "0" to unload after the request
 
 class ActBatchRequest(BaseModel):
     """Request model for batched policy inference."""
@@ -92,6 +181,7 @@ class ActBatchRequest(BaseModel):
     image: List[str]  # base64 encoded
     instruction: List[str]
     model_kwargs: Optional[Dict[str, Any]] = {}
+    keep_alive: Optional[str] = None  # e.g., "5m", "0" to unload after the request
 
 class ServeEnvRequest(BaseModel):
     """Request model for serving environment containers."""
@@ -136,7 +226,24 @@ class VLADaemon:
     allowing extensibility to different model types and simulation platforms.
     """
 
-    def __init__(self, port: int, device: str, health_check_interval: float = 30.0):
+    def __init__(
+        self, 
+        port: int, 
+        device: str, 
+        health_check_interval: float = 30.0,
+        keep_alive: Optional[float] = 300.0,
+        metrics: bool = False,
+        max_batch: int = 32,
+        log_max_bytes: int = DEFAULT_MAX_BYTES,
+        log_max_backups: int = DEFAULT_BACKUP_COUNT,
+        log_max_age: Optional[float] = None,
+        log_format: str = "text",
+        act_rate: Optional[float] = None,
+        act_burst: Optional[int] = None,
+        max_concurrent_act: int = 1,
+        max_act_queue: int = DEFAULT_MAX_QUEUE,
+        serve_args: Optional[List[str]] = None,
+    ):
         """
         Initialize the MAPLE daemon.
         
@@ -146,11 +253,33 @@ class VLADaemon:
         :param port: Port number for the HTTP server to listen on.
         :param device: Default device for policy containers (e.g., 'cuda:0', 'cpu').
         :param health_check_interval: Interval in seconds between health checks.
+        :param keep_alive: Default seconds a policy stays loaded after its last
+                          request. None keeps policies loaded until stopped.
+        :param metrics: If True, record request statistics and serve them at /metrics.
+        :param max_batch: Largest number of observations accepted by /policy/act_batch.
+        :param log_max_bytes: Size at which log files are rotated. 0 never rotates.
+        :param log_max_backups: Compressed backups kept per log file.
+        :param log_max_age: Seconds after which rotated log files are deleted, or None.
+        :param log_format: 'text' or 'json' for one JSON object per log line.
+        :param act_rate: Act requests per second allowed per client (remote
+                        address). None disables the limit.
+        :param act_burst: Act requests a client may send at once. Defaults
+                         to the rate rounded up.
+        :param max_concurrent_act: Act requests run at once across all policies.
+        :param max_act_queue: Act requests allowed to wait for a slot; more
+                             are rejected with 503.
+        :param serve_args: 'maple serve' flags that started this daemon, recorded
+                          in the PID file so 'maple restart' can reproduce them.
         """
 
         self.running = True
         self.port = port
         self.device = device 
+        self.keep_alive = keep_alive
+        self.max_batch = max_batch
+        self.serve_args = serve_args or []
+        self.log_rotation = {"max_bytes": log_max_bytes, "backup_count": log_max_backups, "max_age": log_max_age}
+        self.log_format = log_format
         health_interval = health_check_interval
 
         # Clear stale container records from previous daemon sessions
@@ -166,9 +295,29 @@ class VLADaemon:
         self._policy_backends = {}  # name -> backend instance
         self._policy_handles = {}   # policy_id -> (backend_name, PolicyHandle)
 
+        # Keep-alive tracking for idle policy unloading
+        self._policy_keep_alive = {}  # policy_id -> keep-alive seconds (None = forever)
+        self._policy_expiry = {}      # policy_id -> unload deadline (None = never)
+        self._policy_active = {}      # policy_id -> number of in-flight requests
+        self._policy_last_used = {}   # policy_id -> time of the last request
+        self._policy_recorded = {}    # policy_id -> time last use was written to the store
+        self._keep_alive_lock = threading.Lock()
+
         # Event for coordinating graceful shutdown
         self.shutdown_event = threading.Event()
 
+        # Policy pulls running in the background, one per ref
+        self._pulls = PullRegistry()
+
+        # Request statistics for /metrics, None when metrics are disabled
+        self._metrics = Metrics() if metrics else None
+
+        # Per-client limit on act requests, None when unlimited
+        self._act_limiter = RateLimiter(act_rate, act_burst) if act_rate else None
+
+        # Bounds concurrent inference; excess act requests wait in turn
+        self._act_queue = ActQueue(max_concurrent_act, max_act_queue)
+
         # Health monitoring for container liveness
         self._health_monitor = HealthMonitor(
             check_interval=health_interval,
@@ -183,6 +332,125 @@ class VLADaemon:
         # Initialize FastAPI application
         self.app = FastAPI(title="MAPLE Daemon")
 
+        # Registered first so it runs innermost: the access log and metrics see 429s
+        if self._act_limiter is not None:
+            @self.app.middleware("http")
+            async def limit_act(request: Request, call_next):
+                """
+                Reject act requests from clients over their rate limit.
+                """
+                if request.url.path in RATE_LIMITED_PATHS:
+                    remote_addr = request.client.host if request.client else None
+                    retry_after = self._act_limiter.acquire(client_key(remote_addr))
+                    if retry_after is not None:
+                        return JSONResponse(
+                            {"detail": f"Rate limit of {self._act_limiter.rate:g} act requests/s exceeded, retry in {retry_after:.2f}s"},
+                            status_code=429,
+                            headers={"Retry-After": str(max(1, math.ceil(retry_after)))},
+                        )
+                return await call_next(request)
+
+        @self.app.middleware("http")
+        async def log_request(request: Request, call_next):
+            """
+            Write an access log line for every request.
+
+            Streaming responses are timed until their headers are sent.
+            """
+            start = time.monotonic()
+            status = 500
+            try:
+                response = await call_next(request)
+                status = response.status_code
+                return response
+            finally:
+                path = request.url.path
+                if path not in QUIET_PATHS:
+                    duration_ms = round((time.monotonic() - start) * 1000, 1)
+                    remote_addr = request.client.host if request.client else None
+                    access_log.info(
+                        f"{request.method} {path} {status} {duration_ms}ms",
+                        extra={
+                            "method": request.method,
+                            "path": path,
+                            "status": status,
+                            "duration_ms": duration_ms,
+                            "remote_addr": remote_addr,
+                        },
+                    )
+
+        if self._metrics is not None:
+            @self.app.middleware("http")
+            async def record_request(request: Request, call_next):
+                """
+                Record the count and latency of every request.
+
+                Streaming responses are timed until their headers are sent.
+                """
+                start = time.monotonic()
+                status = 500
+                try:
+                    response = await call_next(request)
+                    status = response.status_code
+                    return response
+                finally:
+                    # Label by route template so IDs in the URL don't explode cardinality
+                    route = request.scope.get("route")
+                    path = getattr(route, "path", "unmatched")
+                    self._metrics.observe_request(request.method, path, status, time.monotonic() - start)
+
+            @self.app.get("/metrics")
+            def metrics() -> Response:
+                """
+                Expose daemon metrics in the Prometheus text format.
+
+                :return: Plain-text exposition of request stats, loaded models,
+                        weight bytes on disk and bytes pulled.
+                """
+                samples = [
+                    ("maple_policies_loaded", "gauge", "Policies currently served.", len(self._policy_handles)),
+                    ("maple_envs_running", "gauge", "Environment instances currently running.", len(self._env_handles)),
+                    ("maple_pulls_active", "gauge", "Policy pulls in progress.", len(self._pulls.active())),
+                    ("maple_model_bytes", "gauge", "Bytes of policy weights in the models directory.", dir_size(models_dir())),
+                    ("maple_pull_bytes_total", "counter", "Bytes downloaded by policy pulls since the daemon started.", bytes_transferred()),
+                ]
+                return Response(self._metrics.render(samples), media_type=CONTENT_TYPE)
+
+        @self.app.get("/healthz")
+        def healthz() -> Dict[str, Any]:
+            """
+            Liveness probe.
+
+            Answers as long as the API thread is serving requests. It does
+            not check storage or Docker - use /readyz for that.
+
+            :return: Dictionary with status 'ok'.
+            """
+            return {"status": "ok"}
+
+        @self.app.get("/readyz")
+        def readyz() -> JSONResponse:
+            """
+            Readiness probe.
+
+            Checks that the data directory is writable, the database can be
+            read and the Docker daemon answers a ping.
+
+            :return: 200 with every check passing, or 503 with the reason
+                    of the first failing check.
+            """
+            checks = self._check_ready()
+            failed = {name: error for name, error in checks.items() if error}
+            body = {
+                "ready": not failed,
+                "checks": {name: error or "ok" for name, error in checks.items()},
+            }
+            if failed:
+                name, error = next(iter(failed.items()))
+                body["reason"] = f"{name}: {error}"
+                return JSONResponse(body, status_code=503)
+            return JSONResponse(body)
+
         @self.app.get("/status")
         def status() -> Dict[str, Any]:
             """
@@ -195,6 +463,7 @@ class VLADaemon:
             """
             return {
                 "running": True,
+                "version": __version__,
                 "port": self.port,
                 "device": self.device,
                 "pulled": {
@@ -212,7 +481,7 @@ class VLADaemon:
             }
         
         @self.app.post("/run")
-        def run(req: RunRequest) -> Dict[str, Any]:
+        def run(req: RunRequest) -> Any:
             """
             Run a policy on an environment task.
             
@@ -224,8 +493,18 @@ class VLADaemon:
             5. Optionally records video
             6. Returns episode results and metrics
             
+            With stream set, the response is NDJSON: one
+            {"step", "action", "timestamp", "instruction"} event per policy
+            inference with the raw policy action, then a final 'success'
+            event carrying the episode results or an 'error' event.
+            
+            With record_dir set, the policy input image of every step is
+            saved as <record_dir>/<run_id>/step_NNNNN.png and its path is
+            added to the step events as 'image'.
+            
             :param req: Run request with policy, env, task, and configuration.
-            :return: Dictionary with episode results including success, steps, reward, and video path.
+            :return: Dictionary with episode results including success, steps, reward, and video path,
+                    or a streaming NDJSON response.
             """
 
             # Validate policy exists and is serving
@@ -242,13 +521,22 @@ class VLADaemon:
                     detail=f"Env '{req.env_id}' not found. Available: {list(self._env_handles.keys())}"
                 )
             
-            # Get policy backend and handle
-            policy_backend_name, policy_handle = self._policy_handles[req.policy_id]
-            policy_backend = self._policy_backends[policy_backend_name]
+            keep_alive = self._resolve_keep_alive(req.policy_id, req.keep_alive)
 
-            # Get environment backend and handle
-            env_backend_name, env_handle = self._env_handles[req.env_id]
-            env_backend = self._env_backends[env_backend_name]
+            policy_backend_name = self._policy_handles[req.policy_id][0]
+            env_backend_name = self._env_handles[req.env_id][0]
+
+            # Refuse pairs without an adapter before touching the containers
+            if not req.force and not has_adapter(policy_backend_name, env_backend_name):
+                supported = supported_envs(policy_backend_name)
+                raise HTTPException(
+                    status_code=400,
+                    detail=(
+                        f"Policy '{policy_backend_name}' does not support env '{env_backend_name}'. "
+                        f"Supported envs: {', '.join(supported) or 'none'}. "
+                        f"Use force to run with the pass-through adapter."
+                    ),
+                )
 
             # Load adapter for policy-environment transformation
             try:
@@ -262,194 +550,133 @@ class VLADaemon:
             # Generate unique run identifier
             run_id = f"run-{uuid.uuid4().hex[:8]}"
 
-            try:
-                # Setup environment with task
-                try:
-                    setup_result = run_with_timeout(
-                        lambda: env_backend.setup(
-                            handle=env_handle,
-                            task=req.task,
-                            seed=req.seed,
-                            env_kwargs=req.env_kwargs
-                        ),
-                        timeout=req.setup_timeout,
-                        operation="Environment setup"
-                    )
-                except TimeoutError as e:
-                    raise HTTPException(
-                        status_code=504,
-                        detail=f"Environment setup timed out after {req.setup_timeout}s. The environment may be unresponsive."
-                    )
+            if not req.stream:
+                return self._run_episode(req, run_id, adapter, keep_alive)
 
-                # Get instruction from request or task default
-                instruction = req.instruction or setup_result.get("instruction", "")
-                if not instruction:
-                    raise HTTPException(status_code=400, detail="No instruction provided and task has no default instruction")
-                
-                # Reset environment and get initial observation
+            # Stream one event per step from a worker thread. The episode runs
+            # to completion even if the client disconnects, so the policy is
+            # always released.
+            events: "queue.Queue[Optional[Dict[str, Any]]]" = queue.Queue()
+
+            def on_step(step: int, action: List[float], instruction: str, image: Optional[str]) -> None:
+                event = {"step": step, "action": action, "timestamp": time.time(), "instruction": instruction}
+                if image is not None:
+                    event["image"] = image
+                events.put(event)
+
+            def worker() -> None:
                 try:
-                    reset_result = run_with_timeout(
-                        lambda: env_backend.reset(handle=env_handle, seed=req.seed),
-                        timeout=req.setup_timeout,
-                        operation="Environment reset"
-                    )
-                    observation = reset_result.get("observation", {})
-                except TimeoutError as e:
-                    raise HTTPException(
-                        status_code=504,
-                        detail=f"Environment reset timed out after {req.setup_timeout}s. The environment may be unresponsive."
-                    )
-                
-                total_reward = 0
-                frames = []  # For video recording
-
-                # Episode loop - run until max_steps or episode ends
-                for step in tqdm(range(req.max_steps)):                    
-                    # Transform observation to policy input format
-                    try:
-                        payload = adapter.transform_obs(observation)
-                    except Exception as e:
-                        raise HTTPException(
-                            status_code=500,
-                            detail=f"Failed to transform observation: {e}. Keys: {list(observation.keys())}"
-                        )
-                    
-                    # Capture frame for video if requested
-                    if req.save_video:
-                        frames.append(self.get_image(payload))
+                    events.put({"status": "success", **self._run_episode(req, run_id, adapter, keep_alive, on_step)})
+                except HTTPException as e:
+                    events.put({"status": "error", "error": e.detail})
+                finally:
+                    events.put(None)
 
-                    # Get action from policy
-                    try:
-                        raw_action = run_with_timeout(
-                            lambda: policy_backend.act(
-                                handle=policy_handle,
-                                payload=payload,
-                                instruction=instruction,
-                                model_kwargs=req.model_kwargs,
-                            ),
-                            timeout=req.step_timeout,
-                            operation="Policy inference"
-                        )
-                    except TimeoutError as e:
-                        log.error(f"Policy inference timed out at step {step}")
-                        raise HTTPException(
-                            status_code=504,
-                            detail=f"Policy inference timed out at step {step} after {req.step_timeout}s. "
-                                   f"The policy container may be unresponsive or overloaded."
-                        )
-                    
-                    # Transform action to environment format
-                    env_action = adapter.transform_action(raw_action)
-                    
-                    # Step environment with transformed action
-                    try:
-                        step_result = run_with_timeout(
-                            lambda: env_backend.step(handle=env_handle, action=env_action),
-                            timeout=req.step_timeout,
-                            operation="Environment step"
-                        )
-                    except TimeoutError as e:
-                        log.error(f"Environment step timed out at step {step}")
-                        raise HTTPException(
-                            status_code=504,
-                            detail=f"Environment step timed out at step {step} after {req.step_timeout}s. "
-                                   f"The environment container may be unresponsive."
-                        )
-                    
-                    # Extract step results
-                    observation = step_result.get("observation", {})
-                    reward = step_result.get("reward", 0.0)
-                    terminated = step_result.get("terminated", False)
-                    truncated = step_result.get("truncated", False)
-                    
-                    total_reward += reward
-                    
-                    # Check if episode is done
-                    if terminated or truncated:
-                        break
-                
-                # Save video if requested and frames were captured
-                video_saved_path = None
-                if req.save_video and frames:
-                    try:                        
-                        # Determine output path
-                        if req.video_dir:
-                            output_dir = req.video_dir
-                            output_path = Path(req.video_dir) / f"{run_id}.mp4"
-                        else:
-                            home_dir = os.path.expanduser("~")
-                            output_dir = os.path.join(home_dir, ".maple", "videos")
-                            output_path = os.path.join(output_dir, f"{run_id}.mp4")
-
-                        # Create directory if it doesn't exist
-                        os.makedirs(output_dir, exist_ok=True)
-
-                        # Write video at 15 fps
-                        mediapy.write_video(output_path, frames, fps=15)
-                        video_saved_path = output_path
-
-                    except Exception as video_err:
-                        log.warning(f"Failed to save video: {video_err}")
-                
-                # Return episode results
-                return {
-                    "run_id": run_id,
-                    "success": terminated,
-                    "policy_id": req.policy_id,
-                    "env_id": req.env_id,
-                    "task": req.task,
-                    "instruction": instruction,
-                    "steps": step,
-                    "total_reward": total_reward,
-                    "terminated": terminated,
-                    "truncated": truncated,
-                    "video_path": video_saved_path,
-                    "adapter": adapter.get_info(),
-                }
-            
-            except HTTPException:
-                # Re-raise HTTP exceptions without wrapping
-                raise
-            except Exception as e:
-                # Log full traceback and return error
-                import traceback
-                traceback.print_exc()
-                raise HTTPException(
-                    status_code=500,
-                    detail=f"Run failed: {str(e)}"
-                )
+            threading.Thread(target=worker, daemon=True).start()
+            return StreamingResponse(self._stream_queue(events), media_type="application/x-ndjson")
 
         @self.app.get("/policy/list")
-        def policies() -> Dict[str, Any]:
+        def policies(request: Request, limit: Optional[int] = None, offset: int = 0, group: bool = False) -> Any:
             """
             List all pulled policies.
             
-            :return: Dictionary containing list of pulled policy records.
+            With limit or offset, policies are sorted by name and version
+            before slicing so pages are stable; without them every policy
+            is returned, most recently pulled first. Each record includes
+            the size in bytes of the complete files at its path. Clients
+            sending 'Accept: application/x-ndjson' receive one JSON object
+            per line as each record is read instead of a single body.
+            
+            With group=True the records are grouped by name instead. Each
+            group has its versions and the size of their distinct files,
+            so weights shared between versions are counted once.
+            
+            :param request: Incoming request, used for content negotiation.
+            :param limit: Optional maximum number of policies to return.
+            :param offset: Number of policies to skip.
+            :param group: Group versions by policy name.
+            :return: Dictionary containing the pulled policy records (or
+                    groups) and the total count, or a streaming NDJSON response.
             """
-            return {"policies": store.list_policies()}
+            if (limit is not None and limit < 0) or offset < 0:
+                raise HTTPException(status_code=400, detail="limit and offset must not be negative")
+
+            records, total = listing.page_policies(limit, offset)
+
+            # Sized after slicing so a page only walks its own weights
+            records = (listing.policy_record(policy) for policy in records)
+            if group:
+                return {"groups": listing.group_policies(records), "total": total}
+            if self._wants_ndjson(request):
+                return StreamingResponse(self._stream_records(records), media_type="application/x-ndjson")
+            return {"policies": list(records), "total": total}
 
         @self.app.get("/env/list")
-        def envs() -> Dict[str, Any]:
+        def envs(request: Request) -> Any:
             """
             List all pulled environments.
             
-            :return: Dictionary containing list of pulled environment records.
+            Each record includes the size of its Docker image in bytes, or
+            None when the image cannot be inspected. Clients sending
+            'Accept: application/x-ndjson' receive each record as soon as
+            its image has been inspected.
+            
+            :param request: Incoming request, used for content negotiation.
+            :return: Dictionary containing list of pulled environment records,
+                    or a streaming NDJSON response.
             """
-            return {"envs": store.list_envs()}
+            records = (listing.env_record(env) for env in store.list_envs())
+            if self._wants_ndjson(request):
+                return StreamingResponse(self._stream_records(records), media_type="application/x-ndjson")
+            return {"envs": list(records)}
         
         @self.app.post("/policy/pull")
-        def pull_policy(req: PullPolicyRequest) -> Dict[str, Any]: 
+        def pull_policy(req: PullPolicyRequest) -> Any: 
             """
             Pull (download) a policy model.
             
             Downloads the policy model from a remote repository and registers
-            it in the local store for later serving.
+            it in the local store for later serving. With stream=True the
+            response is newline-delimited JSON: one 'downloading' event per
+            progress update, followed by a final 'success' or 'error' event.
+            
+            The pull runs in the background and keeps going if the client
+            disconnects. Pulling a ref that is already being pulled attaches
+            to the running download, replaying the latest progress first.
+            With dry_run=True nothing is downloaded or registered; the
+            response lists the repo files and which are already on disk.
             
             :param req: Pull request with policy specification.
-            :return: Dictionary with pull confirmation and manifest information.
+            :return: Dictionary with pull confirmation and manifest information,
+                    or a streaming NDJSON response.
             """
-            # Parse version from spec
-            name, version = parse_versioned(req.spec)
+            try:
+                # An optional '@revision' pins the pull to a branch, tag or commit
+                spec, revision = parse_pinned(req.spec)
+                hf_repo = parse_hf_spec(spec)
+            except ValueError as e:
+                raise HTTPException(status_code=400, detail=str(e))
+
+            # TLS and proxy options of the registry client; the daemon's own server is unaffected
+            try:
+                hub = hub_for(req.registry, insecure=req.insecure, ca_cert=req.ca_cert, proxy=req.proxy)
+            except OSError as e:
+                raise HTTPException(status_code=400, detail=f"Cannot read CA certificate: {e}")
+            if hub.insecure:
+                log.warning(f"TLS certificate verification is disabled for {hub.endpoint}")
+
+            if hf_repo:
+                # Direct HuggingFace reference - infer which backend serves it
+                resolved = infer_policy_backend(hf_repo, token=req.hf_token, hub=hub)
+                if resolved is None:
+                    raise HTTPException(
+                        status_code=400,
+                        detail=f"Could not infer a policy backend for '{hf_repo}'. Supported: {list(POLICY_BACKENDS.keys())}"
+                    )
+                name, version = resolved
+            else:
+                # Parse version from spec
+                name, version = parse_versioned(spec)
 
             # Validate backend exists
             if name not in POLICY_BACKENDS:
@@ -460,23 +687,208 @@ class VLADaemon:
 
             # Determine destination path
             dst = policy_dir(name, version)
-            
-            # Pull model to destination
+
+            if req.dry_run:
+                try:
+                    plan = backend.plan_pull(version, dst, repo=hf_repo, token=req.hf_token, revision=revision, hub=hub)
+                except Exception as e:
+                    raise HTTPException(status_code=400, detail=str(e))
+                return {"policy": f"{name}:{version}", **plan}
+            
+            def do_pull(progress: Callable[[str, int, int], None], cancel: threading.Event) -> Dict[str, Any]:
+                # Serialize with other pulls and removals of the same ref
+                with lock_ref(name, version):
+                    # Weights of another revision are replaced, not overwritten in place
+                    previous = store.get_policy(name, version)
+                    # Pull model to destination
+                    manifest = backend.pull(
+                        version=version, 
+                        dst=dst, 
+                        repo=hf_repo, 
+                        token=req.hf_token,
+                        progress=progress,
+                        concurrency=req.concurrency,
+                        max_retries=req.max_retries,
+                        revision=revision,
+                        cancel=cancel,
+                        hub=hub,
+                        pulled_revision=previous.get("revision") if previous else None,
+                    )
+
+                    # Register in store
+                    store.add_policy(
+                        name=name,
+                        version=version,
+                        path=str(dst),
+                        repo=manifest.get("repo"),
+                        image=manifest.get("image"),
+                        revision=manifest.get("revision"),
+                        ref=revision,
+                    )
+
+                return {"pulled": f"{name}:{version}", "manifest": manifest}
+
             try:
-                manifest = backend.pull(version=version, dst=dst)
-            except Exception as e:
-                raise HTTPException(status_code=400, detail=str(e))
+                job = self._pulls.start_or_attach(f"{name}:{version}", revision, do_pull)
+            except ValueError as e:
+                raise HTTPException(status_code=409, detail=str(e))
 
-            # Register in store
-            store.add_policy(
-                name=name,
-                version=version,
-                path=str(dst),
-                repo=manifest.get("repo"),
-                image=manifest.get("image")
+            if req.stream:
+                return StreamingResponse(self._stream_pull(job), media_type="application/x-ndjson")
+
+            result = job.wait()
+            if result["status"] == "error":
+                raise HTTPException(status_code=400, detail=result["error"])
+            return {k: v for k, v in result.items() if k != "status"}
+
+        @self.app.post("/policy/create")
+        def create(req: CreatePolicyRequest, request: Request) -> Any:
+            """
+            Create a policy from a pulled base, like 'maple create'.
+            
+            The fields mirror a Modelfile: base is FROM, weights are WEIGHTS
+            paths on the daemon host, config holds CONFIG keys and labels
+            the LABEL annotations. Since weights can be any file the daemon
+            can read, only clients on the daemon's host may send them;
+            others get 403. With stream=True the response is
+            newline-delimited JSON: one 'creating' event per file placed,
+            followed by a final 'success' or 'error' event.
+            
+            :param req: Create request with the policy name and Modelfile fields.
+            :param request: Incoming request, used for the client address.
+            :return: Dictionary with the created policy record, or a
+                    streaming NDJSON response.
+            """
+            if req.weights and not is_local_request(request):
+                raise HTTPException(status_code=403, detail="WEIGHTS paths are only accepted from the daemon host; run 'maple create' there")
+
+            modelfile = Modelfile(
+                base=req.base,
+                weights=[Path(w).expanduser() for w in req.weights],
+                config=req.config,
+                labels=req.labels,
             )
 
-            return {"pulled": f"{name}:{version}", "manifest": manifest}
+            def build(progress: Optional[Callable[[str, int, int], None]] = None) -> Dict[str, Any]:
+                try:
+                    policy = create_policy(req.name, modelfile, overwrite=req.overwrite, progress=progress)
+                except PolicyExistsError as e:
+                    raise HTTPException(status_code=409, detail=f"{e}. Set overwrite to replace it")
+                except ValueError as e:
+                    raise HTTPException(status_code=400, detail=str(e))
+                return {"created": f"{policy['name']}:{policy['version']}", "policy": policy}
+
+            if not req.stream:
+                return build()
+
+            events: "queue.Queue[Optional[Dict[str, Any]]]" = queue.Queue()
+
+            def on_file(file: str, completed: int, total: int) -> None:
+                events.put({"status": "creating", "file": file, "completed": completed, "total": total})
+
+            def worker() -> None:
+                try:
+                    events.put({"status": "success", **build(on_file)})
+                except HTTPException as e:
+                    events.put({"status": "error", "error": e.detail})
+                except Exception as e:
+                    log.error(f"Create of {req.name} failed: {e}")
+                    events.put({"status": "error", "error": str(e)})
+                finally:
+                    events.put(None)
+
+            threading.Thread(target=worker, daemon=True).start()
+            return StreamingResponse(self._stream_queue(events), media_type="application/x-ndjson")
+
+        @self.app.post("/policy/push")
+        def push_policy(req: PushPolicyRequest, request: Request) -> Any:
+            """
+            Upload a local policy to a registry, like 'maple push'.
+            
+            The token comes from the request's 'Authorization: Bearer'
+            header. Clients on the daemon's host may leave it out to use
+            the one stored with 'maple login' for the registry; anyone else
+            gets 401 without it. With stream=True the response is newline-delimited
+            JSON: one 'uploading' event per progress update, followed by a
+            final 'success' or 'error' event.
+            
+            :param req: Push request with the policy spec and target repo.
+            :param request: Incoming request, used for the Authorization header.
+            :return: Dictionary with the repo, commit hash and uploaded and
+                    skipped files, or a streaming NDJSON response.
+            """
+            try:
+                name, version = parse_versioned(req.spec)
+            except ValueError as e:
+                raise HTTPException(status_code=400, detail=str(e))
+
+            policy = store.get_policy(name, version)
+            if policy is None:
+                raise HTTPException(status_code=404, detail=f"Policy {name}:{version} not found")
+            src = Path(policy["path"])
+            if not src.is_dir():
+                raise HTTPException(status_code=400, detail=f"Weights of {name}:{version} are missing at {src}")
+
+            host = normalize_registry(req.registry or get_config().policy.registry)
+            auth = request.headers.get("authorization", "")
+            if auth.lower().startswith("bearer "):
+                token = auth[len("Bearer "):].strip()
+            elif is_local_request(request):
+                token = get_token(host)
+            else:
+                raise HTTPException(status_code=401, detail=f"Send an Authorization header with a token for {host}")
+            if not token:
+                raise HTTPException(status_code=401, detail=f"No token for {host}. Send an Authorization header or run 'maple login {host}'")
+
+            try:
+                hub = hub_for(host)
+            except OSError as e:
+                raise HTTPException(status_code=400, detail=f"Cannot read CA certificate: {e}")
+
+            def upload(progress: Optional[Callable[[str, int, int], None]] = None) -> Dict[str, Any]:
+                # Hold the ref so the weights are not removed or re-pulled mid-upload
+                with lock_ref(name, version):
+                    result = upload_repo(
+                        src, req.repo, token,
+                        revision=req.revision,
+                        hub=hub,
+                        progress=progress,
+                        message=req.message,
+                    )
+                return {"pushed": f"{name}:{version}", **result}
+
+            if not req.stream:
+                try:
+                    return upload()
+                except Exception as e:
+                    raise HTTPException(status_code=502, detail=f"Push to {host} failed: {e}")
+
+            events: "queue.Queue[Optional[Dict[str, Any]]]" = queue.Queue()
+
+            def on_progress(file: str, completed: int, total: int) -> None:
+                events.put({"status": "uploading", "file": file, "completed": completed, "total": total})
+
+            def worker() -> None:
+                try:
+                    events.put({"status": "success", **upload(on_progress)})
+                except Exception as e:
+                    log.error(f"Push of {name}:{version} failed: {e}")
+                    events.put({"status": "error", "error": f"Push to {host} failed: {e}"})
+                finally:
+                    events.put(None)
+
+            threading.Thread(target=worker, daemon=True).start()
+            return StreamingResponse(self._stream_queue(events), media_type="application/x-ndjson")
+
+        @self.app.get("/policy/pulls")
+        def list_pulls() -> Dict[str, Any]:
+            """
+            List policy pulls still in progress.
+            
+            :return: Dictionary with one entry per pull: policy, revision,
+                    completed and total bytes.
+            """
+            return {"pulls": [job.snapshot() for job in self._pulls.active().values()]}
 
         @self.app.post("/env/pull")
         def pull_env(name: str) -> Dict[str, Any]:
@@ -524,18 +936,36 @@ class VLADaemon:
             :param req: Serve request with policy spec and configuration.
             :return: Dictionary with serving confirmation and container details.
             """
-            # Parse version from spec
-            name, version = parse_versioned(req.spec)
+            # Parse version and optional revision pin from spec
+            try:
+                spec, pin = parse_pinned(req.spec)
+                name, version = parse_versioned(spec)
+            except ValueError as e:
+                raise HTTPException(status_code=400, detail=str(e))
             policy_id = f"{name}:{version}"
+            keep_alive = self._parse_keep_alive(req.keep_alive)
 
             # Validate backend exists
             if name not in POLICY_BACKENDS:
                 raise HTTPException(status_code=400, detail=f"Unknown policy backend '{name}'")
 
             # Validate policy was pulled
-            if not store.get_policy(name, version):
+            policy = store.get_policy(name, version)
+            if not policy:
                 raise HTTPException(status_code=400, detail=f"Policy '{policy_id}' not pulled. Run 'maple pull policy {req.spec}' first.")
 
+            # A pinned spec must match the commit the weights were pulled at,
+            # or the branch or tag that pull asked for
+            pulled_revision = policy.get("revision") or ""
+            if pin and not (pulled_revision.startswith(pin) or pin == policy.get("ref")):
+                raise HTTPException(
+                    status_code=400,
+                    detail=(
+                        f"Policy '{policy_id}' was pulled at revision {pulled_revision[:12] or 'unknown'}, not '{pin}'. "
+                        f"Run 'maple pull policy {req.spec}' to pull the pinned revision."
+                    ),
+                )
+
             # Instantiate backend
             backend = POLICY_BACKENDS[name]()
             self._policy_backends[name] = backend
@@ -553,11 +983,11 @@ class VLADaemon:
                     model_load_kwargs=req.model_load_kwargs
                 )
             except Exception as e:
+                log.error(f"Failed to load {policy_id}: {e}", extra={"model": name})
                 raise HTTPException(status_code=400, detail=f"Failed to load '{policy_id}': {e}")
 
-            # Register handle for future requests
-            self._policy_handles[handle.policy_id] = (name, handle)
-            
+            log.info(f"Serving {handle.policy_id} on port {handle.port} ({handle.device})", extra={"model": name})
+
             # Store container information
             store.add_container(
                 container_id=handle.container_id,
@@ -579,12 +1009,22 @@ class VLADaemon:
                 auto_restart=False,
             )
 
+            # Register handle for future requests and start the keep-alive
+            # countdown from load time, together so requests never see one without the other
+            with self._keep_alive_lock:
+                self._policy_handles[handle.policy_id] = (name, handle)
+                self._policy_keep_alive[handle.policy_id] = keep_alive
+                self._policy_active[handle.policy_id] = 0
+                self._policy_expiry[handle.policy_id] = self._expiry_from_now(keep_alive)
+            self._record_use(handle.policy_id)
+
             return {
                 "served": policy_id,
                 "policy_id": handle.policy_id,
                 "port": handle.port,
                 "device": handle.device,
                 "model_load_kwargs": handle.metadata.get("model_load_kwargs"),
+                "keep_alive": keep_alive,
             }
         
         @self.app.post("/policy/act")
@@ -602,22 +1042,85 @@ class VLADaemon:
             if req.policy_id not in self._policy_handles:
                 raise HTTPException(status_code=400, detail=f"Policy '{req.policy_id}' not found. Available: {list(self._policy_handles.keys())}")
 
-            # Get policy backend and handle
-            backend_name, handle = self._policy_handles[req.policy_id]
-            backend = self._policy_backends[backend_name]
+            # Reject undecodable images before they reach the model
+            try:
+                decode_base64_image(req.image)
+            except ImageError as e:
+                raise HTTPException(status_code=400, detail=str(e))
+
+            keep_alive = self._resolve_keep_alive(req.policy_id, req.keep_alive)
 
-            # Run inference
+            # Run inference; held as active while queued so the policy is not unloaded
+            backend_name, handle = self._acquire_policy(req.policy_id)
+            backend = self._policy_backends[backend_name]
             try:
-                action = backend.act(
-                    handle=handle,
-                    image=req.image,  # Already base64
-                    instruction=req.instruction,
-                    model_kwargs=req.model_kwargs,
-                )
+                with self._act_queue.slot():
+                    action = backend.act(
+                        handle=handle,
+                        payload={"image": req.image},  # Already base64
+                        instruction=req.instruction,
+                        model_kwargs=req.model_kwargs,
+                    )
 
                 return {"action": action}
+            except QueueFull as e:
+                raise HTTPException(status_code=503, detail=str(e), headers={"Retry-After": "1"})
+            except Exception as e:
+                log.error(f"Inference failed on {req.policy_id}: {e}", extra={"model": backend_name})
+                raise HTTPException(status_code=500, detail=str(e))
+            finally:
+                self._release_policy(req.policy_id, keep_alive)
+
+        @self.app.post("/policy/act_batch")
+        def policy_act_batch(req: ActBatchRequest) -> Dict[str, Any]:
+            """
+            Get actions from policy for a batch of observations.
+
+            Replaying a trajectory one /policy/act call at a time pays the
+            request overhead on every step. This endpoint takes the images
+            and instructions as parallel lists and returns one action per
+            observation, in order.
+
+            :param req: Batch act request with policy ID, images, and instructions.
+            :return: Dictionary containing the predicted actions.
+            """
+            if req.policy_id not in self._policy_handles:
+                raise HTTPException(status_code=400, detail=f"Policy '{req.policy_id}' not found. Available: {list(self._policy_handles.keys())}")
+
+            if len(req.image) != len(req.instruction):
+                raise HTTPException(status_code=400, detail=f"Got {len(req.image)} images but {len(req.instruction)} instructions")
+            if not req.image:
+                raise HTTPException(status_code=400, detail="Batch is empty")
+            if len(req.image) > self.max_batch:
+                raise HTTPException(status_code=400, detail=f"Batch of {len(req.image)} exceeds the maximum of {self.max_batch}")
+
+            for i, image in enumerate(req.image):
+                try:
+                    decode_base64_image(image)
+                except ImageError as e:
+                    raise HTTPException(status_code=400, detail=f"Image {i}: {e}")
+
+            keep_alive = self._resolve_keep_alive(req.policy_id, req.keep_alive)
+
+            backend_name, handle = self._acquire_policy(req.policy_id)
+            backend = self._policy_backends[backend_name]
+            try:
+                with self._act_queue.slot():
+                    actions = backend.act_batch(
+                        handle=handle,
+                        payloads=[{"image": image} for image in req.image],
+                        instructions=req.instruction,
+                        model_kwargs=req.model_kwargs,
+                    )
+
+                return {"actions": actions}
+            except QueueFull as e:
+                raise HTTPException(status_code=503, detail=str(e), headers={"Retry-After": "1"})
             except Exception as e:
+                log.error(f"Batch inference failed on {req.policy_id}: {e}", extra={"model": backend_name})
                 raise HTTPException(status_code=500, detail=str(e))
+            finally:
+                self._release_policy(req.policy_id, keep_alive)
 
         @self.app.get("/policy/info/{policy_id}")
         def get_policy_info(policy_id: str) -> Dict[str, Any]:
@@ -625,7 +1128,8 @@ class VLADaemon:
             Get information about a policy container.
             
             Returns metadata about the policy including input/output specs
-            and version information.
+            and version information. If the container does not report an
+            action_dim, the backend's own is filled in when it has one.
             
             :param policy_id: Identifier of the policy container.
             :return: Dictionary with policy metadata.
@@ -643,9 +1147,13 @@ class VLADaemon:
             
             # Get info from backend
             try:
-                return backend.get_info(handle)
+                policy_info = backend.get_info(handle)
             except Exception as e:
                 raise HTTPException(status_code=500, detail=str(e))
+            action_dim = backend.info().get("action_dim")
+            if action_dim is not None:
+                policy_info.setdefault("action_dim", action_dim)
+            return policy_info
             
         @self.app.post("/policy/stop/{policy_id}")
         def stop_policy(policy_id: str) -> Dict[str, Any]:
@@ -653,38 +1161,85 @@ class VLADaemon:
             Stop a policy container.
             
             Stops the policy container, unregisters from health monitor,
-            and removes from tracking.
+            and removes from tracking. Accepts either a policy ID or a
+            name:version spec, in which case every matching policy is stopped.
             
-            :param policy_id: Identifier of the policy to stop.
-            :return: Dictionary confirming the stop.
+            :param policy_id: Identifier or spec of the policy to stop.
+            :return: Dictionary with the stopped policy IDs and freed memory.
             """
+            try:
+                policy_ids = self._match_policies(policy_id)
+            except ValueError as e:
+                raise HTTPException(status_code=400, detail=str(e))
+
             # Validate policy exists
-            if policy_id not in self._policy_handles:
+            if not policy_ids:
                 raise HTTPException(
                     status_code=400,
-                    detail=f"Policy '{policy_id}' not found"
+                    detail=f"Policy '{policy_id}' is not loaded. Running: {list(self._policy_handles.keys())}"
                 )
             
-            # Get policy backend and handle
-            backend_name, handle = self._policy_handles[policy_id]
-            backend = self._policy_backends.get(backend_name)
-            
-            # Stop container
-            if backend:
+            freed_memory = 0
+            for pid in policy_ids:
                 try:
-                    backend.stop(handle)
+                    freed_memory += self._stop_policy(pid) or 0
                 except Exception as e:
                     raise HTTPException(status_code=500, detail=str(e))
             
-            # Unregister from health monitor and remove from store
-            if handle.container_id:
-                self._health_monitor.unregister(handle.container_id)
-                store.remove_container(handle.container_id)
+            return {
+                "stopped": policy_ids,
+                "freed_memory": freed_memory or None,
+            }
 
-            # Remove from tracking
-            del self._policy_handles[policy_id]
+        @self.app.get("/ps")
+        def ps() -> Dict[str, Any]:
+            """
+            List loaded policies and running environments.
             
-            return {"stopped": policy_id}
+            :return: Dictionary containing one entry per serving policy with
+                    device, health status and remaining keep-alive time, and
+                    one entry per running environment with its port, health
+                    status, container ID and start time, plus the act queue's
+                    running and queued request counts.
+            """
+            now = time.time()
+            policies = []
+
+            with self._keep_alive_lock:
+                for policy_id, (backend_name, handle) in self._policy_handles.items():
+                    expires_at = self._policy_expiry.get(policy_id)
+                    health = self._health_monitor.get_status(handle.container_id) if handle.container_id else None
+
+                    policies.append({
+                        "policy_id": policy_id,
+                        "backend": backend_name,
+                        "version": handle.version,
+                        "device": handle.device,
+                        "status": health["status"] if health else "unknown",
+                        "active_requests": self._policy_active.get(policy_id, 0),
+                        "keep_alive": self._policy_keep_alive.get(policy_id),
+                        "expires_at": expires_at,
+                        "keep_alive_remaining": max(0.0, expires_at - now) if expires_at is not None else None,
+                        "last_used": self._policy_last_used.get(policy_id),
+                    })
+
+            envs = []
+            for env_id, (backend_name, handle) in list(self._env_handles.items()):
+                health = self._health_monitor.get_status(handle.container_id) if handle.container_id else None
+                container = store.get_container(handle.container_id) if handle.container_id else None
+
+                envs.append({
+                    "env_id": env_id,
+                    "backend": backend_name,
+                    "device": handle.device,
+                    "host": handle.host,
+                    "port": handle.port,
+                    "container_id": handle.container_id,
+                    "status": health["status"] if health else "unknown",
+                    "started_at": container["started_at"] if container else None,
+                })
+
+            return {"policies": policies, "envs": envs, "act_queue": self._act_queue.stats()}
 
         @self.app.post("/env/serve")
         def serve_env(req: ServeEnvRequest) -> Dict[str, Any]:
@@ -912,41 +1467,31 @@ class VLADaemon:
         @self.app.post("/env/stop/{env_id}")
         def stop_single_env(env_id: str) -> Dict[str, Any]:
             """
-            Stop a single environment container.
+            Stop environment containers.
             
-            Stops the specified environment container, unregisters from
-            health monitor, and removes from tracking.
+            Stops the environment container, unregisters from health monitor,
+            and removes from tracking. Accepts either an environment ID or an
+            environment name, in which case every instance of it is stopped.
             
-            :param env_id: Identifier of the environment to stop.
-            :return: Dictionary confirming the stop.
+            :param env_id: Identifier or name of the environment to stop.
+            :return: Dictionary with the stopped environment IDs.
             """
+            env_ids = self._match_envs(env_id)
+
             # Validate environment exists
-            if env_id not in self._env_handles:
+            if not env_ids:
                 raise HTTPException(
                     status_code=400,
-                    detail=f"Env '{env_id}' not found"
+                    detail=f"Env '{env_id}' not found. Running: {list(self._env_handles.keys())}"
                 )
             
-            # Get environment backend and handle
-            backend_name, handle = self._env_handles[env_id]
-            backend = self._env_backends.get(backend_name)
-            
-            # Stop container
-            if backend:
+            for eid in env_ids:
                 try:
-                    backend.stop([handle])
+                    self._stop_env(eid)
                 except Exception as e:
                     raise HTTPException(status_code=500, detail=str(e))
-                        
-            # Unregister from health monitor and remove from store
-            if handle.container_id:
-                self._health_monitor.unregister(handle.container_id)
-                store.remove_container(handle.container_id)
-            
-            # Remove from tracking
-            del self._env_handles[env_id]
             
-            return {"stopped": env_id}
+            return {"stopped": env_ids}
         
         @self.app.post("/env/stop")
         def stop_env() -> Dict[str, Any]:
@@ -960,25 +1505,10 @@ class VLADaemon:
             """
             # Iterate over copy of keys since we're modifying the dict
             for env_id in list(self._env_handles.keys()):
-            
-                # Get environment backend and handle
-                backend_name, handle = self._env_handles[env_id]
-                backend = self._env_backends.get(backend_name)
-                
-                # Stop container
-                if backend:
-                    try:
-                        backend.stop([handle])
-                    except Exception as e:
-                        raise HTTPException(status_code=500, detail=str(e))
-                
-                # Unregister from health monitor and remove from store
-                if handle.container_id:
-                    self._health_monitor.unregister(handle.container_id)
-                    store.remove_container(handle.container_id)
-            
-                # Remove from tracking
-                del self._env_handles[env_id]
+                try:
+                    self._stop_env(env_id)
+                except Exception as e:
+                    raise HTTPException(status_code=500, detail=str(e))
             
             return {"stopped": True}
         
@@ -999,26 +1529,44 @@ class VLADaemon:
         """
         Start the daemon server.
         
-        Acquires the daemon lock, starts the health monitor, launches the
-        FastAPI server in a background thread, and enters the main event loop.
+        Takes the MAPLE home's PID file and the daemon lock, starts the health
+        monitor, launches the FastAPI server in a background thread, and
+        enters the main event loop.
         Handles graceful shutdown on SIGINT/SIGTERM.
         """
-        # Check if another daemon is already running
+        # Only one daemon per MAPLE home; a PID file left by a crash is reclaimed
+        self._pid_file = PidFile()
+        if not self._pid_file.acquire(port=self.port, started_at=time.time(), args=self.serve_args):
+            holder = read_pid_file() or {}
+            print(
+                f"[red]A MAPLE daemon is already running for {maple_home()}[/red] "
+                f"(pid {holder.get('pid', '?')}, port {holder.get('port', '?')})"
+            )
+            print("Stop it with [cyan]maple stop[/cyan], or use --root for a separate MAPLE home")
+            sys.exit(1)
+
+        # Check if another daemon is already running for this MAPLE home
         if is_daemon_running():
-            print("[red]Daemon already running[/red]")
+            print(f"[red]Daemon already running for {maple_home()}[/red]")
+            self._pid_file.release()
             sys.exit(1)
 
         # Acquire daemon lock to prevent multiple instances
         self._lock = DaemonLock()
         if not self._lock.acquire():
             print("[red]Could not acquire daemon lock[/red]")
+            self._pid_file.release()
             sys.exit(1)
 
-        print(
+        info(
             f"[bold cyan]MAPLE daemon started[/bold cyan] "
             f"(port={self.port}, device={self.device})"
         )
 
+        # Persist logs for 'maple logs', including when detached
+        enable_server_logs(**self.log_rotation, log_format=self.log_format)
+        log.info(f"MAPLE daemon {__version__} started (port={self.port}, device={self.device})")
+
         # Register signal handlers for graceful shutdown
         signal.signal(signal.SIGINT, self._signal_shutdown)
         signal.signal(signal.SIGTERM, self._signal_shutdown)
@@ -1066,18 +1614,579 @@ class VLADaemon:
         images = np.concatenate(images, axis=1)
         return images
 
+    def _run_episode(
+        self,
+        req: RunRequest,
+        run_id: str,
+        adapter,
+        keep_alive: Optional[float],
+        on_step: Optional[Callable[[int, List[float], str, Optional[str]], None]] = None,
+    ) -> Dict[str, Any]:
+        """
+        Run one episode of a policy on an environment task.
+        
+        The policy is held loaded for the duration of the episode. Errors
+        are raised as HTTPException for the /run handler to report.
+        
+        :param req: Run request with policy, env, task, and configuration.
+        :param run_id: Identifier of this run.
+        :param adapter: Adapter between the policy and the environment.
+        :param keep_alive: Idle duration to keep the policy loaded afterwards.
+        :param on_step: Optional callback receiving the step, raw policy
+                        action, instruction and saved input image path.
+        :return: Dictionary with episode results including success, steps,
+                reward, and video path.
+        """
+        # Hold the policy loaded for the duration of the episode
+        policy_backend_name, policy_handle = self._acquire_policy(req.policy_id)
+        policy_backend = self._policy_backends[policy_backend_name]
+
+        try:
+            env_backend_name, env_handle = self._env_handles[req.env_id]
+            env_backend = self._env_backends[env_backend_name]
+
+            # Setup environment with task
+            try:
+                setup_result = run_with_timeout(
+                    lambda: env_backend.setup(
+                        handle=env_handle,
+                        task=req.task,
+                        seed=req.seed,
+                        env_kwargs=req.env_kwargs
+                    ),
+                    timeout=req.setup_timeout,
+                    operation="Environment setup"
+                )
+            except TimeoutError as e:
+                raise HTTPException(
+                    status_code=504,
+                    detail=f"Environment setup timed out after {req.setup_timeout}s. The environment may be unresponsive."
+                )
+
+            # Get instruction from request or task default
+            instruction = req.instruction or setup_result.get("instruction", "")
+            if not instruction:
+                raise HTTPException(status_code=400, detail="No instruction provided and task has no default instruction")
+        
+            # Reset environment and get initial observation
+            try:
+                reset_result = run_with_timeout(
+                    lambda: env_backend.reset(handle=env_handle, seed=req.seed),
+                    timeout=req.setup_timeout,
+                    operation="Environment reset"
+                )
+                observation = reset_result.get("observation", {})
+            except TimeoutError as e:
+                raise HTTPException(
+                    status_code=504,
+                    detail=f"Environment reset timed out after {req.setup_timeout}s. The environment may be unresponsive."
+                )
+        
+            total_reward = 0
+            frames = []  # For video recording
+
+            frame_dir = Path(req.record_dir) / run_id if req.record_dir else None
+            if frame_dir is not None:
+                frame_dir.mkdir(parents=True, exist_ok=True)
+
+            # Episode loop - run until max_steps or episode ends
+            for step in tqdm(range(req.max_steps)):                    
+                # Transform observation to policy input format
+                try:
+                    payload = adapter.transform_obs(observation)
+                except Exception as e:
+                    raise HTTPException(
+                        status_code=500,
+                        detail=f"Failed to transform observation: {e}. Keys: {list(observation.keys())}"
+                    )
+            
+                # Capture frame for video if requested
+                if req.save_video:
+                    frames.append(self.get_image(payload))
+
+                # Save the policy input so the step can be replayed
+                frame_path = None
+                if frame_dir is not None:
+                    # Replay sends one image to /policy/act; other inputs could not be reproduced
+                    if set(payload) != {"image"}:
+                        raise HTTPException(
+                            status_code=400,
+                            detail=(
+                                f"Recording needs a policy whose only input is one image, but the "
+                                f"{policy_backend_name}/{env_backend_name} adapter sends {', '.join(sorted(payload))}. "
+                                f"Run without --record"
+                            ),
+                        )
+                    frame_path = frame_dir / f"step_{step:05d}.png"
+                    Image.fromarray(np.asarray(payload["image"])).save(frame_path)
+
+                # Get action from policy, sharing the act slots with /policy/act
+                try:
+                    with self._act_queue.slot():
+                        raw_action = run_with_timeout(
+                            lambda: policy_backend.act(
+                                handle=policy_handle,
+                                payload=payload,
+                                instruction=instruction,
+                                model_kwargs=req.model_kwargs,
+                            ),
+                            timeout=req.step_timeout,
+                            operation="Policy inference"
+                        )
+                except QueueFull as e:
+                    raise HTTPException(status_code=503, detail=f"{e} at step {step}", headers={"Retry-After": "1"})
+                except TimeoutError as e:
+                    log.error(f"Policy inference timed out at step {step}")
+                    raise HTTPException(
+                        status_code=504,
+                        detail=f"Policy inference timed out at step {step} after {req.step_timeout}s. "
+                               f"The policy container may be unresponsive or overloaded."
+                    )
+            
+                if on_step is not None:
+                    on_step(step, np.asarray(raw_action, dtype=float).tolist(), instruction, str(frame_path) if frame_path else None)
+
+                # Transform action to environment format
+                env_action = adapter.transform_action(raw_action)
+            
+                # Step environment with transformed action
+                try:
+                    step_result = run_with_timeout(
+                        lambda: env_backend.step(handle=env_handle, action=env_action),
+                        timeout=req.step_timeout,
+                        operation="Environment step"
+                    )
+                except TimeoutError as e:
+                    log.error(f"Environment step timed out at step {step}")
+                    raise HTTPException(
+                        status_code=504,
+                        detail=f"Environment step timed out at step {step} after {req.step_timeout}s. "
+                               f"The environment container may be unresponsive."
+                    )
+            
+                # Extract step results
+                observation = step_result.get("observation", {})
+                reward = step_result.get("reward", 0.0)
+                terminated = step_result.get("terminated", False)
+                truncated = step_result.get("truncated", False)
+            
+                total_reward += reward
+            
+                # Check if episode is done
+                if terminated or truncated:
+                    break
+        
+            # Save video if requested and frames were captured
+            video_saved_path = None
+            if req.save_video and frames:
+                try:                        
+                    # Determine output path
+                    if req.video_dir:
+                        output_dir = req.video_dir
+                        output_path = Path(req.video_dir) / f"{run_id}.mp4"
+                    else:
+                        output_dir = maple_home() / "videos"
+                        output_path = output_dir / f"{run_id}.mp4"
+
+                    # Create directory if it doesn't exist
+                    os.makedirs(output_dir, exist_ok=True)
+
+                    # Write video at 15 fps
+                    mediapy.write_video(output_path, frames, fps=15)
+                    video_saved_path = str(output_path)
+
+                except Exception as video_err:
+                    log.warning(f"Failed to save video: {video_err}")
+        
+            # Return episode results
+            return {
+                "run_id": run_id,
+                "success": terminated,
+                "policy_id": req.policy_id,
+                "env_id": req.env_id,
+                "task": req.task,
+                "instruction": instruction,
+                "steps": step,
+                "total_reward": total_reward,
+                "terminated": terminated,
+                "truncated": truncated,
+                "video_path": video_saved_path,
+                "adapter": adapter.get_info(),
+            }
+    
+        except HTTPException:
+            # Re-raise HTTP exceptions without wrapping
+            raise
+        except Exception as e:
+            # Log full traceback and return error
+            import traceback
+            traceback.print_exc()
+            raise HTTPException(
+                status_code=500,
+                detail=f"Run failed: {str(e)}"
+            )
+        finally:
+            self._release_policy(req.policy_id, keep_alive)
+
     def _loop(self) -> None:
         """
         Main event loop.
         
-        Waits for shutdown event, then initiates cleanup and exit.
-        Runs in the main thread after start() is called.
+        Unloads policies whose keep-alive has expired and waits for the
+        shutdown event, then initiates cleanup and exit. Runs in the main
+        thread after start() is called.
         """
         while not self.shutdown_event.is_set():
+            self._unload_idle_policies()
             time.sleep(0.2)
 
         self._cleanup_and_exit()
 
+    @staticmethod
+    def _wants_ndjson(request: Request) -> bool:
+        """
+        Check whether a client asked for newline-delimited JSON.
+        
+        :param request: Incoming request.
+        :return: True if the Accept header includes application/x-ndjson.
+        """
+        return "application/x-ndjson" in request.headers.get("accept", "")
+
+    @staticmethod
+    def _stream_records(records: Iterable[Dict[str, Any]]) -> Iterator[str]:
+        """
+        Serialize records as NDJSON lines, one per record.
+        
+        :param records: Records to stream. Generators are consumed lazily.
+        :return: Iterator of JSON lines.
+        """
+        for record in records:
+            yield json.dumps(record) + "\n"
+
+    def _check_ready(self) -> Dict[str, Optional[str]]:
+        """
+        Run the readiness checks behind /readyz.
+
+        :return: Dictionary of check name -> error message, or None if it passed.
+        """
+        checks: Dict[str, Optional[str]] = {}
+
+        try:
+            home = maple_home()
+            home.mkdir(parents=True, exist_ok=True)
+            with tempfile.NamedTemporaryFile(dir=home, prefix=".readyz-"):
+                pass
+            checks["storage"] = None
+        except OSError as e:
+            checks["storage"] = f"{maple_home()} is not writable ({e.strerror or e})"
+
+        try:
+            store.list_envs()
+            checks["database"] = None
+        except Exception as e:
+            checks["database"] = str(e)
+
+        try:
+            client = docker.from_env(timeout=5)
+            try:
+                client.ping()
+            finally:
+                client.close()
+            checks["docker"] = None
+        except Exception as e:
+            checks["docker"] = f"Docker daemon not reachable ({e})"
+
+        return checks
+
+    @staticmethod
+    def _stream_queue(events: "queue.Queue") -> Iterator[str]:
+        """
+        Yield queued events as NDJSON until the None end marker.
+        
+        :param events: Queue filled by a worker thread.
+        :return: Iterator of JSON lines.
+        """
+        while True:
+            event = events.get()
+            if event is None:
+                return
+            yield json.dumps(event) + "\n"
+
+    def _stream_pull(self, job: PullJob) -> Iterator[str]:
+        """
+        Yield a pull job's progress as NDJSON.
+        
+        Disconnecting only detaches this client; the pull keeps running.
+        
+        :param job: Running pull job.
+        :return: Iterator of JSON lines.
+        """
+        events = job.subscribe()
+        try:
+            while True:
+                event = events.get()
+                if event is None:
+                    break
+                yield json.dumps(event) + "\n"
+        finally:
+            job.unsubscribe(events)
+
+    def _parse_keep_alive(self, value: Optional[str]) -> Optional[float]:
+        """
+        Parse a request keep-alive value, falling back to the daemon default.
+        
+        :param value: Keep-alive duration string from the request, or None.
+        :return: Keep-alive in seconds, or None to keep loaded forever.
+        """
+        if value is None:
+            return self.keep_alive
+        try:
+            return parse_duration(value)
+        except ValueError as e:
+            raise HTTPException(status_code=400, detail=str(e))
+
+    def _resolve_keep_alive(self, policy_id: str, value: Optional[str]) -> Optional[float]:
+        """
+        Determine the keep-alive to apply after a request to a policy.
+        
+        A per-request value wins; otherwise the value the policy was served
+        with is reused.
+        
+        :param policy_id: Identifier of the serving policy.
+        :param value: Keep-alive duration string from the request, or None.
+        :return: Keep-alive in seconds, or None to keep loaded forever.
+        """
+        if value is None:
+            with self._keep_alive_lock:
+                return self._policy_keep_alive.get(policy_id, self.keep_alive)
+        return self._parse_keep_alive(value)
+
+    def _expiry_from_now(self, keep_alive: Optional[float]) -> Optional[float]:
+        """
+        Compute an unload deadline from a keep-alive duration.
+        
+        :param keep_alive: Keep-alive in seconds, or None for no deadline.
+        :return: Unix timestamp of the deadline, or None.
+        """
+        return None if keep_alive is None else time.time() + keep_alive
+
+    def _acquire_policy(self, policy_id: str) -> Tuple[str, PolicyHandle]:
+        """
+        Mark a policy as in use so it won't be unloaded mid-request.
+        
+        Checked under the same lock as unloading, so a policy stopped
+        since the request was validated is refused instead of used.
+        
+        :param policy_id: Identifier of the serving policy.
+        :return: Tuple of (backend name, policy handle).
+        :raises HTTPException: If the policy is no longer loaded.
+        """
+        with self._keep_alive_lock:
+            entry = self._policy_handles.get(policy_id)
+            if entry is None:
+                raise HTTPException(status_code=400, detail=f"Policy '{policy_id}' is no longer loaded")
+            self._policy_active[policy_id] = self._policy_active.get(policy_id, 0) + 1
+        self._record_use(policy_id)
+        return entry
+
+    def _record_use(self, policy_id: str) -> None:
+        """
+        Record when a serving policy was last used, for LRU pruning.
+        
+        The store is written at most once per USAGE_RECORD_INTERVAL per
+        policy so busy inference loops don't hit the database every request.
+        
+        :param policy_id: Identifier of the serving policy.
+        """
+        entry = self._policy_handles.get(policy_id)
+        if entry is None:
+            return
+        name, handle = entry
+
+        now = time.time()
+        with self._keep_alive_lock:
+            if policy_id not in self._policy_handles:
+                return  # Stopped in the meantime
+            self._policy_last_used[policy_id] = now
+            if now - self._policy_recorded.get(policy_id, 0.0) < USAGE_RECORD_INTERVAL:
+                return
+            self._policy_recorded[policy_id] = now
+
+        try:
+            store.touch_policy(name, handle.version, now)
+        except Exception as e:
+            log.warning(f"Could not record use of {policy_id}: {e}")
+
+    def _release_policy(self, policy_id: str, keep_alive: Optional[float]) -> None:
+        """
+        Mark a request as finished and restart the keep-alive countdown.
+        
+        A keep-alive of zero makes the policy eligible for unloading on the
+        next loop tick.
+        
+        :param policy_id: Identifier of the serving policy.
+        :param keep_alive: Keep-alive in seconds, or None to keep loaded forever.
+        """
+        with self._keep_alive_lock:
+            if policy_id not in self._policy_active:
+                return  # Stopped while the request was running
+            self._policy_active[policy_id] = max(0, self._policy_active[policy_id] - 1)
+            self._policy_keep_alive[policy_id] = keep_alive
+            self._policy_expiry[policy_id] = self._expiry_from_now(keep_alive)
+
+    def _unload_idle_policies(self) -> None:
+        """
+        Stop policies whose keep-alive deadline has passed.
+        
+        Policies with in-flight requests are skipped; their countdown
+        restarts when the request finishes.
+        """
+        now = time.time()
+        with self._keep_alive_lock:
+            expired = [
+                policy_id for policy_id, expires_at in self._policy_expiry.items()
+                if expires_at is not None 
+                and expires_at <= now 
+                and self._policy_active.get(policy_id, 0) == 0
+            ]
+
+        for policy_id in expired:
+            # A request may have acquired the policy since the scan
+            entry = self._detach_policy(policy_id, idle_only=True)
+            if entry is None:
+                continue
+            log.info(f"Keep-alive expired, unloading policy: {policy_id}")
+            try:
+                self._shutdown_policy(policy_id, *entry)
+            except Exception as e:
+                log.warning(f"Failed to unload idle policy {policy_id}: {e}")
+
+    def _match_policies(self, ref: str) -> List[str]:
+        """
+        Resolve a policy reference to serving policy IDs.
+        
+        :param ref: Exact policy ID (e.g., 'openvla-7b-a1b2c3d4') or a
+                   spec (e.g., 'openvla:7b') matching every served instance.
+        :return: List of matching policy IDs, empty if none are loaded.
+        :raises ValueError: If ref is neither a policy ID nor a valid spec.
+        """
+        if ref in self._policy_handles:
+            return [ref]
+
+        name, version = parse_versioned(ref)
+        return [
+            policy_id for policy_id, (backend_name, handle) in self._policy_handles.items()
+            if backend_name == name and handle.version == version
+        ]
+
+    def _detach_policy(self, policy_id: str, idle_only: bool = False) -> Optional[Tuple[str, PolicyHandle]]:
+        """
+        Remove a policy from all tracking so no new request can use it.
+        
+        :param policy_id: Identifier of the serving policy.
+        :param idle_only: Only detach the policy if it has no in-flight
+                          requests and its keep-alive deadline has passed.
+        :return: Tuple of (backend name, policy handle), or None if the
+                policy is not loaded or, with idle_only, still in use.
+        """
+        with self._keep_alive_lock:
+            if policy_id not in self._policy_handles:
+                return None
+            if idle_only:
+                expires_at = self._policy_expiry.get(policy_id)
+                if self._policy_active.get(policy_id, 0) or expires_at is None or expires_at > time.time():
+                    return None
+            entry = self._policy_handles.pop(policy_id)
+            self._policy_keep_alive.pop(policy_id, None)
+            self._policy_expiry.pop(policy_id, None)
+            self._policy_active.pop(policy_id, None)
+            self._policy_last_used.pop(policy_id, None)
+            self._policy_recorded.pop(policy_id, None)
+        return entry
+
+    def _shutdown_policy(self, policy_id: str, backend_name: str, handle: PolicyHandle) -> Optional[int]:
+        """
+        Stop the container of a detached policy.
+        
+        :param policy_id: Identifier of the serving policy.
+        :param backend_name: Name of the policy's backend.
+        :param handle: Handle returned when the policy was served.
+        :return: Memory in bytes the container was using, if known.
+        """
+        backend = self._policy_backends.get(backend_name)
+        freed_memory = None
+        
+        # Stop container
+        if backend:
+            freed_memory = backend.memory_usage(handle)
+            backend.stop(handle)
+        
+        # Unregister from health monitor and remove from store
+        if handle.container_id:
+            self._health_monitor.unregister(handle.container_id)
+            store.remove_container(handle.container_id)
+
+        log.info(f"Stopped {policy_id}", extra={"model": backend_name})
+        return freed_memory
+
+    def _stop_policy(self, policy_id: str) -> Optional[int]:
+        """
+        Stop a policy container and remove it from all tracking.
+        
+        The policy is detached before its container is stopped, so requests
+        arriving meanwhile are refused rather than sent to a dying container.
+        
+        :param policy_id: Identifier of the serving policy.
+        :return: Memory in bytes the container was using, if known.
+        """
+        entry = self._detach_policy(policy_id)
+        if entry is None:
+            return None
+        return self._shutdown_policy(policy_id, *entry)
+
+    def _match_envs(self, ref: str) -> List[str]:
+        """
+        Resolve an environment reference to running environment IDs.
+        
+        :param ref: Exact environment ID (e.g., 'libero-a1b2c3d4') or an
+                   environment name (e.g., 'libero') matching every instance.
+        :return: List of matching environment IDs, empty if none are running.
+        """
+        if ref in self._env_handles:
+            return [ref]
+
+        return [
+            env_id for env_id, (backend_name, _) in self._env_handles.items()
+            if backend_name == ref
+        ]
+
+    def _stop_env(self, env_id: str) -> None:
+        """
+        Stop an environment container and remove it from all tracking.
+        
+        :param env_id: Identifier of the running environment.
+        """
+        if env_id not in self._env_handles:
+            return
+
+        # Get environment backend and handle
+        backend_name, handle = self._env_handles[env_id]
+        backend = self._env_backends.get(backend_name)
+        
+        # Stop container
+        if backend:
+            backend.stop([handle])
+        
+        # Unregister from health monitor and remove from store
+        if handle.container_id:
+            self._health_monitor.unregister(handle.container_id)
+            store.remove_container(handle.container_id)
+
+        log.info(f"Stopped {env_id}")
+
+        # Remove from tracking
+        del self._env_handles[env_id]
+
     def _signal_shutdown(self, *_):
         """
         Signal handler for SIGINT and SIGTERM.
@@ -1186,11 +2295,16 @@ class VLADaemon:
         """
         log.info("Shutting down MAPLE daemon")
 
+        # Stop downloads so their worker threads exit with the daemon
+        self._pulls.cancel_all()
+
         # Cleanup all containers
         self._cleanup_all_containers()
 
         # Release daemon lock
         if hasattr(self, '_lock'):
             self._lock.release()
+        if hasattr(self, '_pid_file'):
+            self._pid_file.release()
         
         sys.exit(0)
\ No newline at end of file
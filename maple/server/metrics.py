@@ -0,0 +1,126 @@
+"""
+Prometheus metrics for the MAPLE daemon.
+
+This module keeps request counters and latency histograms for the daemon's
+HTTP endpoints and renders them, together with gauges supplied at scrape
+time, in the Prometheus text exposition format (version 0.0.4). It is
+hand-rolled to avoid a dependency on prometheus_client.
+
+Requests are labelled by route template (e.g. '/policy/{policy_id}')
+rather than the raw URL path, so label cardinality stays bounded.
+"""
+
+import threading
+from typing import Dict, Iterable, List, Optional, Tuple
+
+# Latency histogram bucket upper bounds in seconds
+LATENCY_BUCKETS = (0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0)
+
+CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+
+# A scrape-time sample: (name, type, help, value)
+Sample = Tuple[str, str, str, float]
+
+def _escape(value: str) -> str:
+    """
+    Escape a label value for the text format.
+
+    :param value: Raw label value.
+    :return: Value with backslashes, quotes and newlines escaped.
+    """
+    return value.replace("\\", "\\\\").replace('"', '\\"').replace("\n", "\\n")
+
+def _labels(pairs: Iterable[Tuple[str, str]]) -> str:
+    """
+    Format a label set.
+
+    :param pairs: (name, value) pairs.
+    :return: Label string such as '{method="GET",path="/status"}'.
+    """
+    return "{" + ",".join(f'{k}="{_escape(str(v))}"' for k, v in pairs) + "}"
+
+def _number(value: float) -> str:
+    """
+    Format a sample value, writing whole numbers without a decimal point.
+
+    :param value: Sample value.
+    :return: Formatted value.
+    """
+    if value == float("inf"):
+        return "+Inf"
+    if float(value).is_integer():
+        return str(int(value))
+    return repr(float(value))
+
+class Metrics:
+    """
+    Thread-safe request statistics for the daemon.
+    """
+
+    def __init__(self, buckets: Tuple[float, ...] = LATENCY_BUCKETS):
+        """
+        Initialize empty metrics.
+
+        :param buckets: Latency histogram bucket upper bounds in seconds.
+        """
+        self.buckets = buckets
+        self._requests: Dict[Tuple[str, str, str], int] = {}  # (method, path, status) -> count
+        self._latency: Dict[Tuple[str, str], List] = {}       # (method, path) -> [bucket counts, sum, count]
+        self._lock = threading.Lock()
+
+    def observe_request(self, method: str, path: str, status: int, seconds: float) -> None:
+        """
+        Record one handled request.
+
+        :param method: HTTP method.
+        :param path: Route template of the endpoint.
+        :param status: Response status code.
+        :param seconds: Time taken to produce the response.
+        """
+        with self._lock:
+            key = (method, path, str(status))
+            self._requests[key] = self._requests.get(key, 0) + 1
+
+            entry = self._latency.setdefault((method, path), [[0] * len(self.buckets), 0.0, 0])
+            for i, bound in enumerate(self.buckets):
+                if seconds <= bound:
+                    entry[0][i] += 1
+            entry[1] += seconds
+            entry[2] += 1
+
+    def render(self, samples: Optional[List[Sample]] = None) -> str:
+        """
+        Render all metrics in the Prometheus text format.
+
+        :param samples: Extra unlabelled gauges or counters read at scrape time.
+        :return: Exposition text ending in a newline.
+        """
+        lines = []
+        with self._lock:
+            requests = sorted(self._requests.items())
+            latency = sorted((k, [list(v[0]), v[1], v[2]]) for k, v in self._latency.items())
+
+        lines.append("# HELP maple_http_requests_total HTTP requests handled by the daemon.")
+        lines.append("# TYPE maple_http_requests_total counter")
+        for (method, path, status), count in requests:
+            labels = _labels([("method", method), ("path", path), ("status", status)])
+            lines.append(f"maple_http_requests_total{labels} {count}")
+
+        lines.append("# HELP maple_http_request_duration_seconds Time taken to handle HTTP requests.")
+        lines.append("# TYPE maple_http_request_duration_seconds histogram")
+        for (method, path), (counts, total, count) in latency:
+            base = [("method", method), ("path", path)]
+            for bound, n in zip(self.buckets, counts):
+                labels = _labels(base + [("le", _number(bound))])
+                lines.append(f"maple_http_request_duration_seconds_bucket{labels} {n}")
+            labels = _labels(base + [("le", "+Inf")])
+            lines.append(f"maple_http_request_duration_seconds_bucket{labels} {count}")
+            lines.append(f"maple_http_request_duration_seconds_sum{_labels(base)} {_number(total)}")
+            lines.append(f"maple_http_request_duration_seconds_count{_labels(base)} {count}")
+
+        for name, kind, help_text, value in samples or []:
+            lines.append(f"# HELP {name} {help_text}")
+            lines.append(f"# TYPE {name} {kind}")
+            lines.append(f"{name} {_number(value)}")
+
+        return "\n".join(lines) + "\n"
@@ -0,0 +1,208 @@
+"""
+Background pull tracking for the MAPLE daemon.
+
+A pull runs in its own thread as a PullJob, independent of the HTTP request
+that started it. Clients subscribe to the job's progress events; if a
+client disconnects the pull keeps going, and a client that sends the same
+pull again re-attaches to the running job. On re-attach the latest
+progress of every file is replayed first so the client can redraw its
+bars straight away.
+
+PullRegistry keeps one job per policy ref (name:version), so concurrent
+requests for the same ref share a single download.
+"""
+
+import queue
+import threading
+from typing import Any, Callable, Dict, Optional
+
+from maple.utils.logging import get_logger
+
+log = get_logger("pulls")
+
+# Receives (progress callback, cancel event) and returns the result dictionary
+PullFunc = Callable[[Callable[[str, int, int], None], threading.Event], Dict[str, Any]]
+
+class PullJob:
+    """
+    A policy pull running in a background thread.
+
+    Events are the same dictionaries streamed to clients: 'downloading'
+    events while files transfer, then one final 'success' or 'error'
+    event. Each subscriber gets its own queue, terminated with None.
+    """
+
+    def __init__(self, ref: str, revision: Optional[str] = None):
+        """
+        Initialize the job. Does not start it - call start().
+
+        :param ref: Policy ref being pulled (name:version).
+        :param revision: Requested revision pin, if any.
+        """
+        self.ref = ref
+        self.revision = revision
+        self.cancel = threading.Event()
+        self._files: Dict[str, Dict] = {}      # file -> latest 'downloading' event
+        self._result: Optional[Dict] = None    # final 'success' or 'error' event
+        self._subscribers = []
+        self._lock = threading.Lock()
+        self._finished = threading.Event()
+
+    @property
+    def done(self) -> bool:
+        """True once the final event has been published."""
+        return self._finished.is_set()
+
+    def start(self, pull: PullFunc) -> None:
+        """
+        Run the pull in a daemon thread.
+
+        :param pull: Callable performing the pull.
+        """
+        def progress(file: str, completed: int, total: int) -> None:
+            self._publish({"status": "downloading", "file": file, "completed": completed, "total": total})
+
+        def worker() -> None:
+            try:
+                result = pull(progress, self.cancel)
+                self._publish({"status": "success", **result})
+            except Exception as e:
+                log.error(f"Pull of {self.ref} failed: {e}")
+                self._publish({"status": "error", "error": str(e)})
+
+        threading.Thread(target=worker, daemon=True).start()
+
+    def _publish(self, event: Dict) -> None:
+        """
+        Record an event and forward it to every subscriber.
+
+        :param event: Progress or final event.
+        """
+        with self._lock:
+            final = event["status"] != "downloading"
+            if final:
+                self._result = event
+            else:
+                self._files[event["file"]] = event
+
+            for q in self._subscribers:
+                q.put(event)
+                if final:
+                    q.put(None)
+            if final:
+                self._subscribers.clear()
+                self._finished.set()
+
+    def subscribe(self) -> "queue.Queue":
+        """
+        Attach to the job's event stream.
+
+        The queue first receives the latest progress of every file seen so
+        far, then live events, and finally None after the last event.
+
+        :return: Queue of events.
+        """
+        q: queue.Queue = queue.Queue()
+        with self._lock:
+            for event in self._files.values():
+                q.put(event)
+            if self._result is not None:
+                q.put(self._result)
+                q.put(None)
+            else:
+                self._subscribers.append(q)
+        return q
+
+    def unsubscribe(self, q: "queue.Queue") -> None:
+        """
+        Detach a subscriber. The pull keeps running.
+
+        :param q: Queue returned by subscribe().
+        """
+        with self._lock:
+            if q in self._subscribers:
+                self._subscribers.remove(q)
+
+    def snapshot(self) -> Dict[str, Any]:
+        """
+        Summarize the job's progress.
+
+        :return: Dictionary with policy, revision, completed and total bytes.
+        """
+        with self._lock:
+            files = list(self._files.values())
+        return {
+            "policy": self.ref,
+            "revision": self.revision,
+            "completed": sum(e["completed"] for e in files),
+            "total": sum(e["total"] for e in files),
+        }
+
+    def wait(self, timeout: Optional[float] = None) -> Optional[Dict]:
+        """
+        Block until the job finishes.
+
+        :param timeout: Maximum seconds to wait, or None to wait forever.
+        :return: The final event, or None if the timeout expired first.
+        """
+        self._finished.wait(timeout)
+        return self._result
+
+class PullRegistry:
+    """
+    Active pulls keyed by policy ref.
+
+    Finished jobs are replaced by the next pull of the same ref.
+    """
+
+    def __init__(self):
+        """Initialize an empty registry."""
+        self._jobs: Dict[str, PullJob] = {}
+        self._lock = threading.Lock()
+
+    def start_or_attach(self, ref: str, revision: Optional[str], pull: PullFunc) -> PullJob:
+        """
+        Return the running job for a ref, starting a new one if none is running.
+
+        :param ref: Policy ref (name:version).
+        :param revision: Requested revision pin, if any.
+        :param pull: Callable performing the pull, used only when a new job starts.
+        :return: The running job.
+        :raises ValueError: If the ref is already being pulled at a different revision.
+        """
+        with self._lock:
+            job = self._jobs.get(ref)
+            if job is not None and not job.done:
+                if job.revision != revision:
+                    raise ValueError(
+                        f"{ref} is already being pulled at revision {job.revision or 'main'}"
+                    )
+                log.debug(f"Attaching to running pull of {ref}")
+                return job
+
+            job = PullJob(ref, revision=revision)
+            self._jobs[ref] = job
+        job.start(pull)
+        return job
+
+    def active(self) -> Dict[str, PullJob]:
+        """
+        Get the pulls that have not finished yet.
+
+        :return: Dictionary of ref -> job.
+        """
+        with self._lock:
+            return {ref: job for ref, job in self._jobs.items() if not job.done}
+
+    def cancel_all(self) -> None:
+        """
+        Cancel every running pull.
+
+        Downloads stop within one chunk. Completed files are kept so the
+        next pull resumes where this one stopped.
+        """
+        jobs = self.active()
+        if jobs:
+            log.info(f"Cancelling {len(jobs)} pull(s) in progress")
+        for job in jobs.values():
+            job.cancel.set()
@@ -0,0 +1,87 @@
+"""
+Per-client rate limiting for the MAPLE daemon.
+
+On a shared inference server one client sending observations in a tight
+loop can keep the GPU to itself. This module gives every client a token
+bucket: it holds up to 'burst' tokens, refills at 'rate' tokens per second,
+and each request takes one token. A request finding the bucket empty is
+rejected with the time until the next token is available.
+
+Clients are told apart by their remote address. The daemon does not check
+API keys, so a key sent in a header is not used: any client could pick a
+fresh one per request and never be limited.
+"""
+
+import math
+import time
+import threading
+from typing import Callable, Dict, Optional, Tuple
+
+# Buckets kept before idle ones are dropped
+MAX_CLIENTS = 10000
+
+def client_key(remote_addr: Optional[str]) -> str:
+    """
+    Identify the client of a request.
+
+    :param remote_addr: Address the request came from, if known.
+    :return: 'ip:<address>'.
+    """
+    return f"ip:{remote_addr or 'unknown'}"
+
+class RateLimiter:
+    """
+    Thread-safe token buckets, one per client.
+    """
+
+    def __init__(self, rate: float, burst: Optional[int] = None, clock: Callable[[], float] = time.monotonic):
+        """
+        Initialize the limiter.
+
+        :param rate: Requests per second each client may sustain.
+        :param burst: Requests a client may send at once after being idle.
+                      Defaults to the rate rounded up, and at least 1.
+        :param clock: Source of the current time in seconds.
+        """
+        if rate <= 0:
+            raise ValueError(f"Rate must be positive, got {rate}")
+        self.rate = rate
+        self.burst = burst if burst is not None else max(1, math.ceil(rate))
+        if self.burst < 1:
+            raise ValueError(f"Burst must be at least 1, got {self.burst}")
+        self._clock = clock
+        self._buckets: Dict[str, Tuple[float, float]] = {}  # client -> (tokens, time of last update)
+        self._lock = threading.Lock()
+
+    def acquire(self, client: str) -> Optional[float]:
+        """
+        Take a token for one request.
+
+        :param client: Key identifying the client, from client_key.
+        :return: None if the request may proceed, otherwise the seconds
+                 until the client's next token is available.
+        """
+        now = self._clock()
+        with self._lock:
+            tokens, updated = self._buckets.get(client, (self.burst, now))
+            tokens = min(self.burst, tokens + (now - updated) * self.rate)
+            if tokens < 1:
+                self._buckets[client] = (tokens, now)
+                return (1 - tokens) / self.rate
+            self._buckets[client] = (tokens - 1, now)
+            if len(self._buckets) > MAX_CLIENTS:
+                self._drop_idle(now)
+            return None
+
+    def _drop_idle(self, now: float) -> None:
+        """
+        Forget clients whose buckets have refilled; they start full anyway.
+
+        :param now: Current time.
+        """
+        idle = [
+            client for client, (tokens, updated) in self._buckets.items()
+            if tokens + (now - updated) * self.rate >= self.burst
+        ]
+        for client in idle:
+            del self._buckets[client]
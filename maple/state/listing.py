@@ -0,0 +1,120 @@
+"""
+Listings of pulled policies and environments.
+
+The records behind 'maple list' are built from the store and the files on
+disk, so they do not need a running daemon. The daemon's /policy/list and
+/env/list endpoints and the CLI share this module, which keeps local and
+remote listings identical.
+
+Functions:
+- policy_record: Add the weights size to a policy record
+- env_record: Add the Docker image size to an environment record
+- page_policies: Pulled policies, optionally sorted and sliced into a page
+- group_policies: Group policy records by name
+- list_policies: Pulled policies in the shape /policy/list returns
+- list_envs: Pulled environments in the shape /env/list returns
+"""
+
+from pathlib import Path
+from typing import Any, Dict, Iterable, List, Optional, Tuple
+
+from maple.state import store
+from maple.utils.logging import get_logger
+from maple.utils.paths import dir_size, unique_size
+
+log = get_logger("listing")
+
+def policy_record(policy: Dict[str, Any]) -> Dict[str, Any]:
+    """
+    Add the weights size to a pulled policy record.
+
+    The record's own path is measured, so policies stored outside the
+    models directory (daemons started with --models-at) are sized too.
+    Partial downloads are not counted.
+
+    :param policy: Policy record from the store.
+    :return: The same record with a 'size' field in bytes.
+    """
+    policy["size"] = dir_size(Path(policy["path"]), include_partial=False)
+    return policy
+
+def env_record(env: Dict[str, Any]) -> Dict[str, Any]:
+    """
+    Add the Docker image size to a pulled environment record.
+
+    :param env: Environment record from the store.
+    :return: The same record with a 'size' field in bytes, or None if unknown.
+    """
+    # Imported here so listing policies does not load the backends
+    from maple.backend.registry import ENV_BACKENDS
+
+    env["size"] = None
+    if env["name"] in ENV_BACKENDS:
+        try:
+            env["size"] = ENV_BACKENDS[env["name"]]().image_size()
+        except Exception as e:
+            log.debug(f"Could not inspect image for {env['name']}: {e}")
+    return env
+
+def page_policies(limit: Optional[int] = None, offset: int = 0) -> Tuple[List[Dict[str, Any]], int]:
+    """
+    Get pulled policies, optionally one page of them.
+
+    With limit or offset, policies are sorted by name and version before
+    slicing so pages are stable; without them every policy is returned,
+    most recently pulled first.
+
+    :param limit: Optional maximum number of policies to return.
+    :param offset: Number of policies to skip.
+    :return: Store records of the page and the total number of policies.
+    """
+    records = store.list_policies()
+    total = len(records)
+    if limit is not None or offset:
+        records = sorted(records, key=lambda p: (p["name"], p["version"]))
+        records = records[offset:offset + limit if limit is not None else None]
+    return records, total
+
+def group_policies(records: Iterable[Dict[str, Any]]) -> List[Dict[str, Any]]:
+    """
+    Group policy records by name.
+
+    :param records: Records with sizes, as returned by policy_record.
+    :return: One group per name with its versions and the size of their
+             distinct files, so weights shared between versions count once.
+    """
+    groups: Dict[str, List[Dict[str, Any]]] = {}
+    for policy in records:
+        groups.setdefault(policy["name"], []).append(policy)
+    return [
+        {
+            "name": name,
+            "versions": sorted(versions, key=lambda p: p["version"]),
+            "size": unique_size(Path(p["path"]) for p in versions),
+        }
+        for name, versions in sorted(groups.items())
+    ]
+
+def list_policies(limit: Optional[int] = None, offset: int = 0, group: bool = False) -> Dict[str, Any]:
+    """
+    List pulled policies with their sizes.
+
+    :param limit: Optional maximum number of policies to return.
+    :param offset: Number of policies to skip.
+    :param group: Group versions by policy name.
+    :return: Dictionary with 'policies' (or 'groups') and 'total'.
+    """
+    records, total = page_policies(limit, offset)
+    # Sized after slicing so a page only walks its own weights
+    records = [policy_record(policy) for policy in records]
+    if group:
+        return {"groups": group_policies(records), "total": total}
+    return {"policies": records, "total": total}
+
+def list_envs() -> Dict[str, Any]:
+    """
+    List pulled environments with their image sizes.
+
+    :return: Dictionary with 'envs'.
+    """
+    return {"envs": [env_record(env) for env in store.list_envs()]}
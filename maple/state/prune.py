@@ -0,0 +1,135 @@
+"""
+Size-bounded pruning of pulled policies.
+
+This module caps the disk space used by policy weights. When the models
+directory is over budget, whole policies are removed, least recently
+used first, until it fits. Policies never used since they were pulled
+count as used at pull time. Policies that are currently loaded are never
+removed.
+
+Policies built with 'maple create' or tagged on pull hard-link their
+weights, so usage counts each file once, and removing a policy only frees
+the files no remaining policy links to.
+
+Functions:
+- models_usage: Bytes used by the models directory
+- prune_candidates: Pulled policies in eviction order
+- prune_to_size: Remove policies until usage fits a budget
+"""
+
+import shutil
+from pathlib import Path
+from typing import Dict, Iterable, List, Set, Tuple
+
+from maple.state import store
+from maple.utils.lock import lock_ref
+from maple.utils.logging import get_logger
+from maple.utils.paths import models_dir, dir_size, unique_size
+
+log = get_logger("prune")
+
+def models_usage() -> int:
+    """
+    Get the bytes used by policy weights, including partial downloads.
+
+    Hard-linked files are counted once.
+
+    :return: Size of the models directory in bytes.
+    """
+    return unique_size([models_dir()], include_partial=True)
+
+def prune_candidates(in_use: Iterable[Tuple[str, str]] = ()) -> List[Dict]:
+    """
+    List pulled policies in the order they would be evicted.
+
+    :param in_use: (name, version) pairs that must be kept, e.g. loaded policies.
+    :return: Policy records, least recently used first, each with its
+            'size' in bytes. Policies in in_use are left out.
+    """
+    keep: Set[Tuple[str, str]] = set(in_use)
+    policies = [p for p in store.list_policies() if (p["name"], p["version"]) not in keep]
+    policies.sort(key=lambda p: p.get("last_used") or p["pulled_at"])
+    for policy in policies:
+        policy["size"] = dir_size(Path(policy["path"]))
+    return policies
+
+def _files(root: Path) -> Dict[Tuple[int, int], List[int]]:
+    """
+    Map each file under a directory to its size and link counts.
+
+    :param root: Directory to scan.
+    :return: Mapping of (device, inode) to [size, links on disk, links under root].
+    """
+    files: Dict[Tuple[int, int], List[int]] = {}
+    for path in root.rglob("*") if root.is_dir() else ():
+        try:
+            if not path.is_file():
+                continue
+            st = path.stat()
+        except OSError:
+            continue
+        entry = files.setdefault((st.st_dev, st.st_ino), [st.st_size, st.st_nlink, 0])
+        entry[2] += 1
+    return files
+
+def _remove(policy: Dict) -> None:
+    """
+    Remove a policy's database entry and weights.
+
+    :param policy: Policy record from the store.
+    """
+    with lock_ref(policy["name"], policy["version"]):
+        store.remove_policy(policy["name"], policy["version"])
+        path = Path(policy["path"])
+        if path.is_dir():
+            shutil.rmtree(path)
+        elif path.exists():
+            path.unlink()
+
+    # Drop the per-name directory once its last version is gone
+    parent = path.parent
+    if parent != models_dir() and parent.is_dir() and not any(parent.iterdir()):
+        parent.rmdir()
+
+def prune_to_size(
+    max_bytes: int,
+    in_use: Iterable[Tuple[str, str]] = (),
+    dry_run: bool = False,
+) -> Tuple[List[str], int]:
+    """
+    Remove whole policies until the models directory fits a budget.
+
+    Nothing is removed if usage is already within the budget. Otherwise
+    policies are evicted least recently used first. Loaded policies are
+    skipped, so usage can stay above the budget if only they remain.
+    A file only counts as freed once every link to it has been removed, so
+    evicting a base whose weights a derived policy still links frees
+    nothing until the derived policy goes too.
+
+    :param max_bytes: Budget for the models directory in bytes.
+    :param in_use: (name, version) pairs that must not be removed.
+    :param dry_run: If True, only report what would be removed.
+    :return: Tuple of (removed 'name:version' specs, bytes freed).
+    """
+    usage = models_usage()
+    removed: List[str] = []
+    freed = 0
+    # Links left to each file, as if the removals so far had happened
+    links: Dict[Tuple[int, int], int] = {}
+
+    for policy in prune_candidates(in_use):
+        if usage - freed <= max_bytes:
+            break
+        spec = f"{policy['name']}:{policy['version']}"
+        released = 0
+        for inode, (size, nlink, here) in _files(Path(policy["path"])).items():
+            links[inode] = links.get(inode, nlink) - here
+            if links[inode] <= 0:
+                released += size
+        if not dry_run:
+            _remove(policy)
+            log.info(f"Pruned {spec} ({released} bytes freed)")
+        removed.append(spec)
+        freed += released
+
+    return removed, freed
@@ -0,0 +1,147 @@
+"""
+Detection and repair of local storage problems.
+
+A crash or an interrupted command can leave the models directory out of
+step with the store: partial downloads and staging directories nobody will
+finish, weights of policies the store no longer knows, and store entries
+whose weights are gone. This module finds those and fixes them.
+
+Refs being written (a pull, create or removal holding lock_ref) are left
+alone. Fixes that only remove leftovers or recreate directories are safe.
+Fixes that delete weights or store entries are marked destructive so the
+caller can confirm them first.
+
+Functions:
+- find_issues: Scan the MAPLE home for storage problems
+- fix_issue: Repair one problem
+"""
+
+import shutil
+from pathlib import Path
+from dataclasses import dataclass
+from typing import List, Optional, Set, Tuple
+
+from maple.state import store
+from maple.utils.lock import lock_ref
+from maple.utils.logging import get_logger
+from maple.utils.paths import models_dir, dir_size
+
+log = get_logger("repair")
+
+# Suffixes of the directories a create or a pull of a new revision works in
+STAGING_SUFFIXES = {
+    ".creating": "Unfinished create",
+    ".pulling": "Unfinished pull",
+    ".replaced": "Replaced weights",
+}
+
+@dataclass
+class Issue:
+    """
+    A storage problem and how to fix it.
+    """
+    # One of 'missing_dir', 'partial', 'staging', 'orphan', 'missing_weights'
+    kind: str
+    # File or directory the problem is about
+    path: Path
+    # Human-readable description
+    description: str
+    # Policy ref the path belongs to, if any
+    ref: Optional[Tuple[str, str]] = None
+    # Whether fixing it deletes weights or store entries
+    destructive: bool = False
+
+def _busy(name: str, version: str) -> bool:
+    """Check whether another process holds the ref lock."""
+    try:
+        with lock_ref(name, version, blocking=False):
+            return False
+    except BlockingIOError:
+        return True
+
+def find_issues() -> List[Issue]:
+    """
+    Scan the MAPLE home for storage problems.
+
+    :return: Issues found, safe ones first.
+    """
+    issues: List[Issue] = []
+    root = models_dir()
+
+    policies = store.list_policies()
+    known: Set[Tuple[str, str]] = {(p["name"], p["version"]) for p in policies}
+
+    # A fresh install has no models directory yet; only registered policies need one
+    if policies and not root.is_dir():
+        issues.append(Issue("missing_dir", root, f"Models directory {root} is missing"))
+
+    name_dirs = sorted(p for p in root.iterdir() if p.is_dir()) if root.is_dir() else []
+    for name_dir in name_dirs:
+        for path in sorted(p for p in name_dir.iterdir() if p.is_dir()):
+            suffix = next((s for s in STAGING_SUFFIXES if path.name.endswith(s)), None)
+            if path.name.startswith(".") and suffix:
+                version = path.name[1:-len(suffix)]
+                if not _busy(name_dir.name, version):
+                    issues.append(Issue(
+                        "staging", path,
+                        f"{STAGING_SUFFIXES[suffix]} of {name_dir.name}:{version} ({path})",
+                        ref=(name_dir.name, version),
+                    ))
+                continue
+
+            ref = (name_dir.name, path.name)
+            if _busy(*ref):
+                continue
+            if ref not in known:
+                issues.append(Issue(
+                    "orphan", path,
+                    f"Weights of {ref[0]}:{ref[1]} are not in the store ({dir_size(path)} bytes)",
+                    ref=ref, destructive=True,
+                ))
+                continue
+            for part in sorted(path.rglob("*.part")):
+                issues.append(Issue("partial", part, f"Partial download {part}", ref=ref))
+
+    for policy in policies:
+        path = Path(policy["path"])
+        if not path.exists():
+            issues.append(Issue(
+                "missing_weights", path,
+                f"Weights of {policy['name']}:{policy['version']} are missing ({path})",
+                ref=(policy["name"], policy["version"]), destructive=True,
+            ))
+
+    issues.sort(key=lambda issue: issue.destructive)
+    return issues
+
+def fix_issue(issue: Issue) -> str:
+    """
+    Repair one storage problem.
+
+    The ref is locked while its files are changed, so a pull or create
+    started in the meantime waits for the fix.
+
+    :param issue: Issue from find_issues.
+    :return: Description of the action taken.
+    :raises BlockingIOError: If the ref became busy since the scan.
+    """
+    if issue.kind == "missing_dir":
+        issue.path.mkdir(parents=True, exist_ok=True)
+        return f"Created {issue.path}"
+
+    name, version = issue.ref
+    with lock_ref(name, version, blocking=False):
+        if issue.kind == "partial":
+            issue.path.unlink(missing_ok=True)
+            action = f"Removed partial download {issue.path}"
+        elif issue.kind in ("staging", "orphan"):
+            shutil.rmtree(issue.path, ignore_errors=True)
+            action = f"Removed {issue.path}"
+        elif issue.kind == "missing_weights":
+            store.remove_policy(name, version)
+            action = f"Removed {name}:{version} from the store"
+        else:
+            raise ValueError(f"Unknown issue kind '{issue.kind}'")
+
+    log.info(action)
+    return action
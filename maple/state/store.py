@@ -10,6 +10,7 @@ Key features:
 - Policy and environment registry tracking
 - Container lifecycle management (both policies and environments)
 - Evaluation run history and statistics
+- Append-only history of policy pulls and removals
 - Automatic database initialization and schema management
 - Context manager for safe database operations
 
@@ -18,6 +19,7 @@ The database schema includes:
 - envs: Downloaded environment images
 - containers: Currently running containers (policies and envs)
 - runs: Evaluation run history with metrics and outcomes
+- policy_history: Pulls and removals of each policy with the revision at the time
 
 All database operations use proper transaction handling and support
 concurrent access through SQLite's WAL (Write-Ahead Logging) mode.
@@ -31,11 +33,20 @@ from contextlib import contextmanager
 from typing import List, Optional, Dict
 
 from maple.utils.logging import get_logger
+from maple.utils.paths import maple_home
 
 log = get_logger("state")
 
-STATE_DIR = Path.home() / ".maple"
-DB_FILE = STATE_DIR / "state.db"
+# Database path override, or None for state.db in the MAPLE home directory
+DB_FILE: Optional[Path] = None
+
+def db_file() -> Path:
+    """
+    Get the path of the state database.
+    
+    :return: DB_FILE if set, otherwise state.db in the MAPLE home directory.
+    """
+    return DB_FILE or maple_home() / "state.db"
 
 def _ensure_dir() -> None:
     """
@@ -44,7 +55,7 @@ def _ensure_dir() -> None:
     Creates the MAPLE state directory with proper permissions if it
     doesn't already exist. Safe to call multiple times.
     """
-    STATE_DIR.mkdir(parents=True, exist_ok=True)
+    db_file().parent.mkdir(parents=True, exist_ok=True)
 
 @contextmanager
 def _get_conn():
@@ -61,7 +72,7 @@ def _get_conn():
     :return: SQLite connection object configured for MAPLE state management.
     """
     _ensure_dir()
-    conn = sqlite3.connect(DB_FILE, timeout=10)
+    conn = sqlite3.connect(db_file(), timeout=10)
     conn.row_factory = sqlite3.Row  # Access columns by name
     conn.execute("PRAGMA journal_mode=WAL")  # Better concurrent access
     conn.execute("PRAGMA foreign_keys=ON")
@@ -94,7 +105,11 @@ def init_db() -> None:
                 version TEXT NOT NULL,
                 path TEXT NOT NULL,
                 repo TEXT,
+                revision TEXT,  -- resolved commit the weights were pulled at
+                ref TEXT,  -- branch, tag or commit requested with '@' at pull time
+                annotations TEXT,  -- JSON object of user key/value notes
                 pulled_at REAL NOT NULL,
+                last_used REAL,  -- last time the daemon served or ran inference with it
                 UNIQUE(name, version)
             );
             
@@ -137,38 +152,106 @@ def init_db() -> None:
                 metadata TEXT  -- JSON blob
             );
             
+            -- Pulls and removals of policies, newest last
+            CREATE TABLE IF NOT EXISTS policy_history (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                name TEXT NOT NULL,
+                version TEXT NOT NULL,
+                event TEXT NOT NULL,  -- 'pull' or 'remove'
+                repo TEXT,
+                revision TEXT,
+                at REAL NOT NULL
+            );
+            
             -- Indexes for common queries
             CREATE INDEX IF NOT EXISTS idx_containers_type ON containers(type);
             CREATE INDEX IF NOT EXISTS idx_containers_status ON containers(status);
             CREATE INDEX IF NOT EXISTS idx_runs_policy ON runs(policy_id);
             CREATE INDEX IF NOT EXISTS idx_runs_task ON runs(task);
+            CREATE INDEX IF NOT EXISTS idx_policy_history_name ON policy_history(name);
         """)
+        _add_missing_columns(conn)
     log.debug("Database initialized")
 
-def add_policy(name: str, image: str, version: str, path: str, repo: str = None) -> int:
+# Columns added after the initial schema: table -> {column: type}
+_ADDED_COLUMNS = {
+    "policies": {"revision": "TEXT", "annotations": "TEXT", "last_used": "REAL", "ref": "TEXT"},
+}
+
+def _add_missing_columns(conn) -> None:
+    """
+    Add columns introduced after a database was first created.
+    
+    CREATE TABLE IF NOT EXISTS leaves existing tables untouched, so older
+    databases are upgraded here column by column.
+    
+    :param conn: Open SQLite connection.
+    """
+    for table, columns in _ADDED_COLUMNS.items():
+        existing = {row["name"] for row in conn.execute(f"PRAGMA table_info({table})")}
+        for column, column_type in columns.items():
+            if column not in existing:
+                conn.execute(f"ALTER TABLE {table} ADD COLUMN {column} {column_type}")
+                log.debug(f"Added column {table}.{column}")
+
+def add_policy(name: str, image: str, version: str, path: str, repo: str = None, revision: str = None, ref: str = None) -> int:
     """
     Add or update a pulled policy.
     
     Registers a downloaded policy model in the database. If a policy with
-    the same name and version already exists, updates its path, repo, and
-    pulled timestamp.
+    the same name and version already exists, updates its path, repo,
+    revision, ref and pulled timestamp.
     
     :param name: Name of the policy model.
     :param version: Version identifier of the policy.
     :param path: Filesystem path where the policy is stored.
     :param repo: Optional repository URL or identifier.
+    :param revision: Optional commit hash the weights were downloaded at.
+    :param ref: Optional branch, tag or commit the pull asked for, which
+                resolved to revision.
     :return: Database row ID of the inserted or updated policy.
     """
     with _get_conn() as conn:
         conn.execute("""
-            INSERT INTO policies (name, image, version, path, repo, pulled_at)
-            VALUES (?, ?, ?, ?, ?, ?)
+            INSERT INTO policies (name, image, version, path, repo, revision, ref, pulled_at)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?)
             ON CONFLICT(name, version) DO UPDATE SET
                 path = excluded.path,
                 repo = excluded.repo,
+                revision = excluded.revision,
+                ref = excluded.ref,
                 pulled_at = excluded.pulled_at
-        """, (name, image, version, path, repo, time.time()))
-        return conn.execute("SELECT last_insert_rowid()").fetchone()[0]
+        """, (name, image, version, path, repo, revision, ref, time.time()))
+        row_id = conn.execute("SELECT last_insert_rowid()").fetchone()[0]
+        _record_history(conn, name, version, "pull", repo, revision)
+        return row_id
+
+def touch_policy(name: str, version: str, at: Optional[float] = None) -> bool:
+    """
+    Record that a pulled policy was just used.
+    
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy.
+    :param at: Unix timestamp of the use. Defaults to now.
+    :return: True if the policy exists and was updated.
+    """
+    with _get_conn() as conn:
+        cursor = conn.execute(
+            "UPDATE policies SET last_used = ? WHERE name = ? AND version = ?",
+            (at if at is not None else time.time(), name, version)
+        )
+        return cursor.rowcount > 0
+
+def _policy_row(row: sqlite3.Row) -> Dict:
+    """
+    Convert a policies row to a dictionary, decoding its annotations.
+    
+    :param row: Row from the policies table.
+    :return: Dictionary containing policy data.
+    """
+    d = dict(row)
+    d["annotations"] = json.loads(d["annotations"]) if d.get("annotations") else {}
+    return d
 
 def get_policy(name: str, version: str) -> Optional[Dict]:
     """
@@ -185,7 +268,31 @@ def get_policy(name: str, version: str) -> Optional[Dict]:
             "SELECT * FROM policies WHERE name = ? AND version = ?",
             (name, version)
         ).fetchone()
-        return dict(row) if row else None
+        return _policy_row(row) if row else None
+
+def read_policy(db: Path, name: str, version: str) -> Optional[Dict]:
+    """
+    Get a pulled policy from another MAPLE root's database, read-only.
+    
+    Unlike get_policy, the database is neither created nor migrated, so a
+    shared root can be read without write access and is left untouched.
+    
+    :param db: Path to the other root's state.db.
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy.
+    :return: Dictionary containing policy data, or None if not found.
+    :raises sqlite3.Error: If the database cannot be read.
+    """
+    conn = sqlite3.connect(f"{db.resolve().as_uri()}?mode=ro", uri=True, timeout=10)
+    conn.row_factory = sqlite3.Row
+    try:
+        row = conn.execute(
+            "SELECT * FROM policies WHERE name = ? AND version = ?",
+            (name, version)
+        ).fetchone()
+        return _policy_row(row) if row else None
+    finally:
+        conn.close()
 
 def list_policies() -> List[Dict]:
     """
@@ -197,7 +304,26 @@ def list_policies() -> List[Dict]:
     """
     with _get_conn() as conn:
         rows = conn.execute("SELECT * FROM policies ORDER BY pulled_at DESC").fetchall()
-        return [dict(row) for row in rows]
+        return [_policy_row(row) for row in rows]
+
+def set_policy_annotations(name: str, version: str, annotations: Dict[str, str]) -> bool:
+    """
+    Replace the annotations of a pulled policy.
+    
+    Annotations are free-form notes such as training run IDs or dataset
+    versions. They are kept when the policy is pulled again.
+    
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy.
+    :param annotations: Complete key/value mapping to store.
+    :return: True if the policy was updated, False if not found.
+    """
+    with _get_conn() as conn:
+        cursor = conn.execute(
+            "UPDATE policies SET annotations = ? WHERE name = ? AND version = ?",
+            (json.dumps(annotations, sort_keys=True), name, version)
+        )
+        return cursor.rowcount > 0
 
 def remove_policy(name: str, version: str) -> bool:
     """
@@ -211,11 +337,60 @@ def remove_policy(name: str, version: str) -> bool:
     :return: True if a policy was deleted, False if not found.
     """
     with _get_conn() as conn:
-        cursor = conn.execute(
+        row = conn.execute(
+            "SELECT repo, revision FROM policies WHERE name = ? AND version = ?",
+            (name, version)
+        ).fetchone()
+        if row is None:
+            return False
+        conn.execute(
             "DELETE FROM policies WHERE name = ? AND version = ?",
             (name, version)
         )
-        return cursor.rowcount > 0
+        _record_history(conn, name, version, "remove", row["repo"], row["revision"])
+        return True
+
+# Most recent history entries kept per policy name
+HISTORY_LIMIT = 500
+
+def _record_history(conn, name: str, version: str, event: str, repo: Optional[str], revision: Optional[str]) -> None:
+    """
+    Append a policy history entry, dropping the oldest beyond HISTORY_LIMIT.
+    
+    :param conn: Open SQLite connection.
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy.
+    :param event: 'pull' or 'remove'.
+    :param repo: Repository the weights came from.
+    :param revision: Commit the weights were pulled at.
+    """
+    conn.execute(
+        "INSERT INTO policy_history (name, version, event, repo, revision, at) VALUES (?, ?, ?, ?, ?, ?)",
+        (name, version, event, repo, revision, time.time())
+    )
+    conn.execute("""
+        DELETE FROM policy_history WHERE name = ? AND id NOT IN (
+            SELECT id FROM policy_history WHERE name = ? ORDER BY id DESC LIMIT ?
+        )
+    """, (name, name, HISTORY_LIMIT))
+
+def list_policy_history(name: str, version: str = None) -> List[Dict]:
+    """
+    List pulls and removals of a policy, oldest first.
+    
+    :param name: Name of the policy model.
+    :param version: Optional version to filter on. All versions if None.
+    :return: List of dictionaries with version, event, repo, revision and at.
+    """
+    query = "SELECT version, event, repo, revision, at FROM policy_history WHERE name = ?"
+    params = [name]
+    if version:
+        query += " AND version = ?"
+        params.append(version)
+    query += " ORDER BY id"
+    
+    with _get_conn() as conn:
+        return [dict(row) for row in conn.execute(query, params).fetchall()]
     
 def remove_env(name: str) -> bool:
     """
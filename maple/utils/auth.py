@@ -0,0 +1,145 @@
+"""
+Registry credential storage.
+
+This module manages access tokens for remote registries (HuggingFace Hub
+and any other host MAPLE pulls from). Tokens are stored in
+~/.maple/auth.json, namespaced by registry host so several registries can
+be logged in at the same time. The file is created with 0600 permissions.
+
+File layout:
+    {
+        "registries": {
+            "huggingface.co": {"token": "hf_..."}
+        }
+    }
+"""
+
+import os
+import json
+from pathlib import Path
+from typing import Dict, Optional
+
+from maple.utils.paths import maple_home
+from maple.utils.logging import get_logger
+
+log = get_logger("auth")
+
+DEFAULT_REGISTRY = "huggingface.co"
+
+def auth_file() -> Path:
+    """
+    Get the path of the credentials file.
+    
+    :return: Path to auth.json in the MAPLE home directory.
+    """
+    return maple_home() / "auth.json"
+
+def normalize_registry(registry: Optional[str]) -> str:
+    """
+    Reduce a registry reference to its host name.
+    
+    :param registry: Registry host or URL (e.g., 'https://hf.co/'). None for the default.
+    :return: Lower-cased host name, with 'hf.co' mapped to 'huggingface.co'.
+    """
+    if not registry:
+        return DEFAULT_REGISTRY
+
+    host = registry.strip().lower()
+    for scheme in ("https://", "http://"):
+        if host.startswith(scheme):
+            host = host[len(scheme):]
+    host = host.split("/", 1)[0]
+
+    return DEFAULT_REGISTRY if host == "hf.co" else host
+
+def _load(path: Path) -> Dict:
+    """
+    Read the auth file.
+    
+    :param path: Path to the auth file.
+    :return: Parsed contents, or an empty layout if missing or unreadable.
+    """
+    if not path.exists():
+        return {"registries": {}}
+    try:
+        data = json.loads(path.read_text())
+        data.setdefault("registries", {})
+        return data
+    except (OSError, json.JSONDecodeError) as e:
+        log.warning(f"Could not read {path}: {e}")
+        return {"registries": {}}
+
+def _save(data: Dict, path: Path) -> None:
+    """
+    Write the auth file, readable only by the current user.
+    
+    :param data: Auth file contents.
+    :param path: Path to the auth file.
+    """
+    path.parent.mkdir(parents=True, exist_ok=True)
+
+    # Create with restrictive permissions before any secret is written
+    fd = os.open(path, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0o600)
+    with os.fdopen(fd, "w") as f:
+        json.dump(data, f, indent=2)
+    os.chmod(path, 0o600)
+
+def save_token(registry: Optional[str], token: str, path: Optional[Path] = None) -> str:
+    """
+    Store a token for a registry.
+    
+    :param registry: Registry host or URL. None for the default registry.
+    :param token: Access token.
+    :param path: Optional auth file path. Defaults to ~/.maple/auth.json.
+    :return: Normalized registry host the token was stored under.
+    """
+    path = path or auth_file()
+    host = normalize_registry(registry)
+
+    data = _load(path)
+    data["registries"][host] = {"token": token}
+    _save(data, path)
+
+    return host
+
+def remove_token(registry: Optional[str], path: Optional[Path] = None) -> bool:
+    """
+    Remove the stored token for a registry.
+    
+    :param registry: Registry host or URL. None for the default registry.
+    :param path: Optional auth file path. Defaults to ~/.maple/auth.json.
+    :return: True if a token was removed, False if none was stored.
+    """
+    path = path or auth_file()
+    host = normalize_registry(registry)
+
+    data = _load(path)
+    if host not in data["registries"]:
+        return False
+
+    del data["registries"][host]
+    _save(data, path)
+    return True
+
+def get_token(registry: Optional[str], path: Optional[Path] = None) -> Optional[str]:
+    """
+    Look up the stored token for a registry.
+    
+    :param registry: Registry host or URL. None for the default registry.
+    :param path: Optional auth file path. Defaults to ~/.maple/auth.json.
+    :return: Stored token, or None if not logged in.
+    """
+    path = path or auth_file()
+    entry = _load(path)["registries"].get(normalize_registry(registry))
+    return entry.get("token") if entry else None
+
+def auth_headers(registry: Optional[str], path: Optional[Path] = None) -> Dict[str, str]:
+    """
+    Build HTTP authorization headers for a registry.
+    
+    :param registry: Registry host or URL. None for the default registry.
+    :param path: Optional auth file path. Defaults to ~/.maple/auth.json.
+    :return: Dictionary with an Authorization header, or empty if not logged in.
+    """
+    token = get_token(registry, path)
+    return {"Authorization": f"Bearer {token}"} if token else {}
@@ -30,18 +30,51 @@ Configuration sections:
 """
 
 import os
+import re
 import yaml
 from pathlib import Path
-from typing import Optional, Dict, Any
+from typing import Optional, Dict, Any, List
 from dataclasses import dataclass, field, asdict
 
-from maple.utils.logging import get_logger
+from maple.utils.logging import get_logger, LOG_FORMATS
+from maple.utils.misc import parse_duration, parse_size
+from maple.utils.paths import maple_home
 
 log = get_logger("config")
 
-# Configuration directory and file paths
-CONFIG_DIR = Path.home() / ".maple"
-CONFIG_FILE = CONFIG_DIR / "config.yaml"
+def config_file() -> Path:
+    """
+    Get the path of the configuration file.
+    
+    :return: Path to config.yaml in the MAPLE home directory.
+    """
+    return maple_home() / "config.yaml"
+
+# Accepted values used by Config.validate
+LOG_LEVELS = ("DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL")
+# Device types accepted for default_device, optionally with an index (e.g., 'cuda:1')
+DEVICE_TYPES: List[str] = ["cpu", "cuda", "mps"]
+# Options accepted per registry under policy.registries
+REGISTRY_OPTIONS = ("insecure", "ca_cert", "proxy", "mirrors")
+
+class ConfigError(ValueError):
+    """Raised when configuration values are invalid."""
+
+    def __init__(self, errors: List[str]):
+        """
+        :param errors: One message per invalid setting, each starting with its dotted key.
+        """
+        super().__init__("; ".join(errors))
+        self.errors = errors
+
+def register_device_type(name: str) -> None:
+    """
+    Allow an additional device type in default_device settings.
+    
+    :param name: Device type prefix (e.g., 'xpu').
+    """
+    if name not in DEVICE_TYPES:
+        DEVICE_TYPES.append(name)
 
 @dataclass
 class LoggingConfig:
@@ -54,6 +87,14 @@ class LoggingConfig:
     file: Optional[str] = None
     # Enable verbose output with additional debug info
     verbose: bool = False
+    # Rotate daemon log files once they reach this size (0 = never)
+    max_size: str = "10MB"
+    # Compressed rotated log files to keep per log
+    max_backups: int = 3
+    # Delete rotated log files older than this (None = keep)
+    max_age: Optional[str] = None
+    # Daemon log format: text, or json for one object per line
+    format: str = "text"
 
 @dataclass
 class ContainerConfig:
@@ -79,6 +120,12 @@ class PolicyConfig:
     # Default model kwargs
     model_kwargs: Dict[str, Any] = field(default_factory=dict) # Used during act
     model_load_kwargs: Dict[str, Any] = field(default_factory=dict) # Used during serve
+    # How long a served policy stays loaded after its last request ('5m', '1h', '-1' = forever)
+    keep_alive: str = "5m"
+    # Registry used by login/logout when none is given
+    registry: str = "huggingface.co"
+    # Per-registry connection options keyed by host, e.g. {'registry.internal': {'ca_cert': '/etc/ssl/ca.pem'}}
+    registries: Dict[str, Dict[str, Any]] = field(default_factory=dict)
 
 @dataclass
 class EnvConfig:
@@ -170,6 +217,84 @@ class Config:
         """
         self.policy.default_device = value
     
+    def validate(self) -> None:
+        """
+        Check that every setting has a usable value.
+        
+        All problems are collected so they can be fixed in one pass.
+        
+        :raises ConfigError: If any setting is invalid, listing each problem.
+        """
+        errors = []
+
+        def check(ok: bool, message: str) -> None:
+            if not ok:
+                errors.append(message)
+
+        def positive_int(value: Any) -> bool:
+            return isinstance(value, int) and not isinstance(value, bool) and value > 0
+
+        def valid_device(value: Any) -> bool:
+            pattern = rf"({'|'.join(map(re.escape, DEVICE_TYPES))})(:\d+)?"
+            return isinstance(value, str) and re.fullmatch(pattern, value) is not None
+
+        check(str(self.logging.level).upper() in LOG_LEVELS, f"logging.level must be one of {', '.join(LOG_LEVELS)}, got '{self.logging.level}'")
+        try:
+            parse_size(self.logging.max_size)
+        except ValueError:
+            errors.append(f"logging.max_size must be a size such as '10MB', got '{self.logging.max_size}'")
+        check(positive_int(self.logging.max_backups), "logging.max_backups must be a positive integer")
+        if self.logging.max_age is not None:
+            try:
+                parse_duration(self.logging.max_age)
+            except ValueError:
+                errors.append(f"logging.max_age must be a duration such as '7d', got '{self.logging.max_age}'")
+        check(self.logging.format in LOG_FORMATS, f"logging.format must be one of {', '.join(LOG_FORMATS)}, got '{self.logging.format}'")
+
+        for key in ("memory_limit", "shm_size"):
+            value = getattr(self.containers, key)
+            check(isinstance(value, str) and re.fullmatch(r"\d+[bkmg]?", value.lower()) is not None,
+                  f"containers.{key} must be a size such as '16g', got '{value}'")
+        for key in ("startup_timeout", "health_check_interval"):
+            check(positive_int(getattr(self.containers, key)), f"containers.{key} must be a positive integer")
+
+        check(valid_device(self.policy.default_device), f"policy.default_device must be one of {', '.join(DEVICE_TYPES)} (optionally ':N'), got '{self.policy.default_device}'")
+        try:
+            parse_duration(self.policy.keep_alive)
+        except ValueError:
+            errors.append(f"policy.keep_alive must be a duration such as '5m' or '-1', got '{self.policy.keep_alive}'")
+        check(bool(self.policy.registry), "policy.registry must not be empty")
+        if not isinstance(self.policy.registries, dict):
+            errors.append("policy.registries must be a mapping of registry host to options")
+        else:
+            for host, options in self.policy.registries.items():
+                if not isinstance(options, dict):
+                    errors.append(f"policy.registries.{host} must be a mapping of options")
+                    continue
+                unknown = set(options) - set(REGISTRY_OPTIONS)
+                check(not unknown, f"policy.registries.{host} has unknown options: {', '.join(sorted(unknown))}")
+                check(isinstance(options.get("insecure", False), bool), f"policy.registries.{host}.insecure must be true or false")
+                check(isinstance(options.get("ca_cert", ""), str), f"policy.registries.{host}.ca_cert must be a file path")
+                proxy = options.get("proxy", "http://")
+                check(isinstance(proxy, str) and re.match(r"(https?|socks5h?)://", proxy) is not None,
+                      f"policy.registries.{host}.proxy must be a URL such as 'http://proxy:3128', got '{proxy}'")
+                mirrors = options.get("mirrors", [])
+                check(isinstance(mirrors, list) and all(isinstance(m, str) and re.match(r"https?://", m) for m in mirrors),
+                      f"policy.registries.{host}.mirrors must be a list of URLs such as 'https://mirror.internal'")
+
+        check(valid_device(self.env.default_device), f"env.default_device must be one of {', '.join(DEVICE_TYPES)} (optionally ':N'), got '{self.env.default_device}'")
+        check(positive_int(self.env.default_num_envs), "env.default_num_envs must be a positive integer")
+
+        port = self.daemon.port
+        check(isinstance(port, int) and not isinstance(port, bool) and 0 < port < 65536, f"daemon.port must be between 1 and 65535, got {port}")
+
+        for section in ("run", "eval"):
+            for key in ("max_steps", "timeout"):
+                check(positive_int(getattr(getattr(self, section), key)), f"{section}.{key} must be a positive integer")
+
+        if errors:
+            raise ConfigError(errors)
+
     def to_dict(self) -> dict:
         """
         Convert configuration to dictionary.
@@ -190,7 +315,7 @@ class Config:
         :param path: Path to save config file (default: ~/.maple/config.yaml).
         """
         # Use default path if not specified
-        path = path or CONFIG_FILE
+        path = path or config_file()
         
         # Create parent directory if needed
         path.parent.mkdir(parents=True, exist_ok=True)
@@ -247,8 +372,11 @@ def _apply_env_vars(cfg: Config) -> None:
     # This defines which env vars map to which config fields
     env_mappings = {
         "MAPLE_DEVICE": ("policy", "default_device"),
+        "MAPLE_KEEP_ALIVE": ("policy", "keep_alive"),
+        "MAPLE_REGISTRY": ("policy", "registry"),
         "MAPLE_LOG_LEVEL": ("logging", "level"),
         "MAPLE_LOG_FILE": ("logging", "file"),
+        "MAPLE_LOG_FORMAT": ("logging", "format"),
         "MAPLE_MEMORY_LIMIT": ("containers", "memory_limit"),
         "MAPLE_STARTUP_TIMEOUT": ("containers", "startup_timeout"),
         "MAPLE_DAEMON_PORT": ("daemon", "port"),
@@ -342,6 +470,7 @@ def load_config(config_path: Path = None) -> Config:
     1. Reset to default values
     2. Load from YAML file (if exists)
     3. Apply environment variable overrides
+    4. Validate the result
     
     Updates the global config instance and returns it. This function
     should be called early in application startup to initialize
@@ -349,6 +478,8 @@ def load_config(config_path: Path = None) -> Config:
     
     :param config_path: Optional path to config file (default: ~/.maple/config.yaml).
     :return: Updated global configuration instance.
+    :raises ConfigError: If the loaded values are invalid. The global
+                         config is still updated so it can be inspected.
     """
     global config
     
@@ -356,7 +487,7 @@ def load_config(config_path: Path = None) -> Config:
     config = Config()
     
     # Load from YAML file (if it exists)
-    path = config_path or CONFIG_FILE
+    path = config_path or config_file()
     if path.exists():
         try:
             # Load YAML file
@@ -371,8 +502,145 @@ def load_config(config_path: Path = None) -> Config:
     
     # Apply environment variable overrides (highest precedence)
     _apply_env_vars(config)
+
+    config.validate()
     return config
 
+def _coerce_value(current: Any, raw: str) -> Any:
+    """
+    Convert a string to the type of an existing config value.
+    
+    :param current: Current value of the field, used to pick the target type.
+    :param raw: String value from the command line.
+    :return: Converted value.
+    """
+    if raw.lower() in ("null", "none") and not isinstance(current, str):
+        return None
+    if isinstance(current, bool):
+        if raw.lower() in ("true", "1", "yes", "on"):
+            return True
+        if raw.lower() in ("false", "0", "no", "off"):
+            return False
+        raise ValueError(f"Expected a boolean, got '{raw}'")
+    if isinstance(current, int):
+        return int(raw)
+    if isinstance(current, float):
+        return float(raw)
+    if isinstance(current, dict):
+        value = yaml.safe_load(raw)
+        if not isinstance(value, dict):
+            raise ValueError(f"Expected a mapping, got '{raw}'")
+        return value
+    return raw
+
+def _resolve_key(cfg: Config, key: str) -> tuple:
+    """
+    Resolve a dotted key (e.g., 'daemon.port') to its section and field.
+    
+    :param cfg: Configuration instance.
+    :param key: Dotted key in 'section.field' form.
+    :return: Tuple of (section object, field name).
+    """
+    parts = key.split(".")
+    if len(parts) != 2:
+        raise KeyError(f"Invalid key '{key}'. Use 'section.field' (e.g., daemon.port)")
+
+    section, name = parts
+    section_obj = getattr(cfg, section, None)
+    if section_obj is None or not hasattr(section_obj, "__dataclass_fields__"):
+        raise KeyError(f"Unknown config section '{section}'")
+    if name not in section_obj.__dataclass_fields__:
+        raise KeyError(f"Unknown config key '{key}'")
+    
+    return section_obj, name
+
+def get_config_value(key: str, cfg: Config = None) -> Any:
+    """
+    Get a configuration value by dotted key.
+    
+    :param key: Dotted key in 'section.field' form (e.g., 'policy.keep_alive').
+    :param cfg: Configuration instance (default: global config).
+    :return: Current value of the field.
+    """
+    section_obj, name = _resolve_key(cfg or config, key)
+    return getattr(section_obj, name)
+
+def set_config_value(key: str, value: str, config_path: Path = None) -> Any:
+    """
+    Persist a configuration value to the config file.
+    
+    Only the file contents are updated; environment variable overrides
+    are not written back. The global config is reloaded afterwards.
+    
+    :param key: Dotted key in 'section.field' form (e.g., 'daemon.port').
+    :param value: New value as a string, converted to the field's type.
+    :param config_path: Optional path to config file (default: ~/.maple/config.yaml).
+    :return: The converted value that was stored.
+    """
+    path = config_path or config_file()
+
+    # Start from the file contents, not the env-overridden runtime config
+    file_cfg = Config()
+    if path.exists():
+        with open(path) as f:
+            _load_from_dict(file_cfg, yaml.safe_load(f) or {})
+
+    section_obj, name = _resolve_key(file_cfg, key)
+    converted = _coerce_value(getattr(section_obj, name), value)
+    setattr(section_obj, name, converted)
+
+    # Never write a value that would be rejected on the next load. Problems
+    # with other keys are left alone so they can be fixed one at a time.
+    try:
+        file_cfg.validate()
+    except ConfigError as e:
+        problems = [message for message in e.errors if message.startswith(f"{key} ")]
+        if problems:
+            raise ConfigError(problems)
+    file_cfg.save(path)
+    load_config(path)
+    return converted
+
+def set_registry_options(host: str, config_path: Path = None, **options: Any) -> Dict[str, Any]:
+    """
+    Persist connection options for one registry to the config file.
+    
+    Options set to None are removed; the others replace any stored value.
+    
+    :param host: Normalized registry host (e.g., 'registry.internal').
+    :param config_path: Optional path to config file (default: ~/.maple/config.yaml).
+    :param options: Options from REGISTRY_OPTIONS (e.g., insecure=True).
+    :return: Options stored for the registry afterwards.
+    """
+    path = config_path or config_file()
+
+    file_cfg = Config()
+    if path.exists():
+        with open(path) as f:
+            _load_from_dict(file_cfg, yaml.safe_load(f) or {})
+
+    stored = dict(file_cfg.policy.registries.get(host) or {})
+    for name, value in options.items():
+        if value is None:
+            stored.pop(name, None)
+        else:
+            stored[name] = value
+
+    if stored:
+        file_cfg.policy.registries[host] = stored
+    else:
+        file_cfg.policy.registries.pop(host, None)
+
+    try:
+        file_cfg.validate()
+    except ConfigError as e:
+        problems = [message for message in e.errors if message.startswith(f"policy.registries.{host}")]
+        if problems:
+            raise ConfigError(problems)
+    file_cfg.save(path)
+    load_config(path)
+    return stored
+
 def init_config_file():
     """
     Create default configuration file if it doesn't exist.
@@ -382,10 +650,11 @@ def init_config_file():
     it only creates the file if missing.
     """
     # Only create if file doesn't already exist
-    if not CONFIG_FILE.exists():
+    path = config_file()
+    if not path.exists():
         # Save current config (with defaults) to file
-        config.save(CONFIG_FILE)
-        log.info(f"Created default config at {CONFIG_FILE}")
+        config.save(path)
+        log.info(f"Created default config at {path}")
 
 def get_config() -> Config:
     """
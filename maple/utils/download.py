@@ -0,0 +1,620 @@
+"""
+HuggingFace download utilities.
+
+This module downloads model repositories file by file over HTTP so that
+byte-level progress can be reported while a pull is running. Each file is
+written to a '.part' file next to its destination and renamed once complete,
+so interrupted pulls never leave truncated weights in place. Files already
+on disk are skipped on the next pull only if their digest matches the
+listing: the LFS SHA-256 for weights, the git blob ID for small files.
+
+Key features:
+- Per-file progress callbacks (file, completed_bytes, total_bytes)
+- Concurrent file downloads with fail-fast cancellation
+- Caller cancellation through a threading.Event
+- Retries with backoff, resuming partial files via HTTP Range
+- Skip files already present with the expected size and digest
+- Remove files the listed revision no longer has
+- Dry-run planning of which files a pull would fetch
+- Size and digest check of every finished file before the rename from '.part'
+- Process-wide count of bytes transferred, for daemon metrics
+- Bearer token authentication for private repos
+- Registry endpoint, TLS (custom CA, insecure) and proxy options via a Hub
+- Fallback to registry mirrors, in order, for the listing and each file
+"""
+
+import os
+import time
+import hashlib
+import logging
+import threading
+import requests
+from pathlib import Path
+from concurrent.futures import ThreadPoolExecutor, as_completed
+from typing import Callable, Dict, Iterable, List, Optional, Tuple, TypeVar
+
+from maple.utils.hub import Hub
+from maple.utils.auth import auth_headers
+from maple.utils.retry import retry_call
+from maple.utils.logging import get_logger
+
+log = get_logger("download")
+
+# Called with (file name, completed bytes, total bytes)
+ProgressCallback = Callable[[str, int, int], None]
+
+# Minimum seconds between progress callbacks for a single file
+_PROGRESS_INTERVAL = 0.25
+_CHUNK_SIZE = 1 << 20
+
+# Bytes received over the network by every download in this process
+_bytes_transferred = 0
+_bytes_lock = threading.Lock()
+
+class DownloadCancelled(Exception):
+    """Raised when a download is cancelled by the caller or by a failed sibling download."""
+
+class IncompleteDownload(requests.RequestException):
+    """Raised when a response ends before the expected size; retried like a dropped connection."""
+
+class IntegrityError(Exception):
+    """Raised when a finished download does not match the size or digest in the repo listing."""
+
+T = TypeVar("T")
+
+def _auth_headers(token: Optional[str]) -> Dict[str, str]:
+    """Build the Authorization header for a token, if any."""
+    return {"Authorization": f"Bearer {token}"} if token else {}
+
+def _endpoint_headers(hub: Hub, endpoint: str, token: Optional[str]) -> Dict[str, str]:
+    """
+    Build the Authorization header for one of a hub's endpoints.
+    
+    The caller's token only goes to the primary endpoint. Mirrors get the
+    token stored for their own host by 'maple login', if any.
+    """
+    return _auth_headers(token) if endpoint == hub.endpoint else auth_headers(endpoint)
+
+def _from_endpoints(hub: Hub, what: str, attempt: Callable[[str], T]) -> T:
+    """
+    Run a request against each endpoint of a hub until one succeeds.
+    
+    Any request failure, including timeouts and error responses, moves on
+    to the next mirror, as does a file that fails its integrity check. If
+    every endpoint fails, the primary's error is raised.
+    
+    :param hub: Registry connection settings.
+    :param what: Description of the request for log messages.
+    :param attempt: Callable receiving an endpoint base URL.
+    :return: Result of the first successful attempt.
+    """
+    first_error: Optional[Exception] = None
+    for endpoint in hub.endpoints:
+        try:
+            result = attempt(endpoint)
+        except (requests.RequestException, IntegrityError) as e:
+            first_error = first_error or e
+            if len(hub.endpoints) > 1:
+                log.warning(f"{what} failed on {endpoint}, trying next mirror: {e}")
+            continue
+        log.debug(f"{what} served by {endpoint}")
+        return result
+    raise first_error
+
+def _request_kwargs(hub: Hub) -> Dict:
+    """
+    Get the keyword arguments for a registry request.
+    
+    With TLS verification off, urllib3 would warn on every request; the
+    caller has already been warned once, so those warnings are silenced.
+    """
+    if hub.insecure:
+        import urllib3
+        urllib3.disable_warnings(urllib3.exceptions.InsecureRequestWarning)
+    return hub.request_kwargs()
+
+def _model_info(
+    repo_id: str,
+    revision: Optional[str],
+    token: Optional[str],
+    hub: Optional[Hub],
+    blobs: bool = False,
+) -> Dict:
+    """
+    Fetch a model repo's metadata from the registry API.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param token: Optional HuggingFace token for private repos.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :param blobs: Include per-file sizes.
+    :return: Decoded metadata with 'sha' and 'siblings'.
+    """
+    hub = hub or Hub()
+
+    def fetch(endpoint: str) -> Dict:
+        resp = requests.get(
+            hub.api_url(repo_id, revision, endpoint=endpoint),
+            params={"blobs": "true"} if blobs else None,
+            headers=_endpoint_headers(hub, endpoint, token),
+            timeout=30,
+            **_request_kwargs(hub),
+        )
+        resp.raise_for_status()
+        return resp.json()
+
+    return _from_endpoints(hub, f"Metadata of {repo_id}", fetch)
+
+def sibling_digest(sibling: Dict) -> Tuple[int, Optional[str]]:
+    """
+    Get the size and content digest of a file from a 'blobs=true' repo listing.
+    
+    For LFS files the registry's blobId is that of the pointer file, so
+    their contents are identified by the LFS SHA-256 instead.
+    
+    :param sibling: Entry of the listing's 'siblings'.
+    :return: Tuple of (size in bytes, 'sha256:<hex>', 'git:<blob id>' or None).
+    """
+    lfs = sibling.get("lfs") or {}
+    if lfs.get("sha256"):
+        digest = f"sha256:{lfs['sha256']}"
+    elif sibling.get("blobId"):
+        digest = f"git:{sibling['blobId']}"
+    else:
+        digest = None
+    return sibling.get("size") or lfs.get("size") or 0, digest
+
+def list_repo_entries(
+    repo_id: str,
+    token: Optional[str] = None,
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> List[Tuple[str, int, Optional[str]]]:
+    """
+    List the files in a HuggingFace model repo with their sizes and digests.
+    
+    LFS files are identified by the SHA-256 of their contents, others by
+    their git blob ID, in the form accepted by matches_digest.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param token: Optional HuggingFace token for private repos.
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: List of (file name, size in bytes, digest or None) tuples.
+    """
+    info = _model_info(repo_id, revision, token, hub, blobs=True)
+    return [(s["rfilename"], *sibling_digest(s)) for s in info.get("siblings", [])]
+
+def list_repo_files(
+    repo_id: str, 
+    token: Optional[str] = None, 
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> List[Tuple[str, int]]:
+    """
+    List the files in a HuggingFace model repo with their sizes.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param token: Optional HuggingFace token for private repos.
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: List of (file name, size in bytes) tuples.
+    """
+    return [(name, size) for name, size, _ in list_repo_entries(repo_id, token=token, revision=revision, hub=hub)]
+
+def resolve_revision(
+    repo_id: str, 
+    revision: Optional[str] = None, 
+    token: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> str:
+    """
+    Resolve a branch, tag or short commit to the full commit hash.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param token: Optional HuggingFace token for private repos.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: Full commit hash.
+    """
+    return _model_info(repo_id, revision, token, hub)["sha"]
+
+def search_repos(query: str, limit: int = 20, token: Optional[str] = None) -> List[str]:
+    """
+    Search the HuggingFace Hub for model repos, most downloaded first.
+    
+    :param query: Free text matched against repo IDs.
+    :param limit: Maximum number of repos to return.
+    :param token: Optional HuggingFace token so private repos are included.
+    :return: List of repo IDs.
+    """
+    from huggingface_hub import HfApi
+
+    models = HfApi().list_models(search=query, sort="downloads", limit=limit, token=token)
+    return [m.id for m in models]
+
+def file_sha256(path: Path) -> str:
+    """
+    Compute the SHA-256 of a file, as the registry reports it for LFS files.
+    
+    :param path: File to hash.
+    :return: Hex digest.
+    """
+    sha = hashlib.sha256()
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(_CHUNK_SIZE), b""):
+            sha.update(chunk)
+    return sha.hexdigest()
+
+def git_blob_id(path: Path) -> str:
+    """
+    Compute the git blob ID of a file, as the registry reports it.
+
+    :param path: File to hash.
+    :return: Hex SHA-1 of the git blob header and contents.
+    """
+    sha = hashlib.sha1(f"blob {path.stat().st_size}\0".encode())
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(_CHUNK_SIZE), b""):
+            sha.update(chunk)
+    return sha.hexdigest()
+
+def matches_digest(path: Path, digest: Optional[str]) -> bool:
+    """
+    Check a file against a digest from the repo listing.
+    
+    :param path: File to check.
+    :param digest: 'sha256:<hex>' or 'git:<blob id>', or None if unknown.
+    :return: True if the contents match, or if there is no digest to check.
+    """
+    if digest is None:
+        return True
+    kind, _, value = digest.partition(":")
+    if kind == "sha256":
+        return file_sha256(path) == value
+    if kind == "git":
+        return git_blob_id(path) == value
+    raise ValueError(f"Unknown digest '{digest}'")
+
+def is_cached(dest: Path, total: int, digest: Optional[str] = None) -> bool:
+    """
+    Check whether a file is already downloaded with the expected contents.
+    
+    The size is compared first; the file is only hashed if it matches.
+    
+    :param dest: Final destination path.
+    :param total: Expected size in bytes (0 if unknown).
+    :param digest: Expected digest from the listing, if known.
+    :return: True if the next pull would skip this file.
+    """
+    if not (total and dest.exists() and dest.stat().st_size == total):
+        return False
+    return matches_digest(dest, digest)
+
+def remove_stale_files(dst: Path, keep: Iterable[str]) -> List[str]:
+    """
+    Delete files in a download directory that a repo listing does not have.
+    
+    Pulling a new revision over an old one would otherwise leave files the
+    new revision dropped, such as renamed weight shards, next to the new
+    weights. Leftover '.part' files are removed too.
+    
+    :param dst: Download directory.
+    :param keep: Relative file names in the listing.
+    :return: Relative names of the removed files.
+    """
+    if not dst.is_dir():
+        return []
+    keep = set(keep)
+    removed = []
+    for path in sorted(p for p in dst.rglob("*") if p.is_file()):
+        name = path.relative_to(dst).as_posix()
+        if name not in keep:
+            path.unlink()
+            removed.append(name)
+    # Drop directories emptied above, deepest first
+    for path in sorted((p for p in dst.rglob("*") if p.is_dir()), key=lambda p: len(p.parts), reverse=True):
+        if not any(path.iterdir()):
+            path.rmdir()
+    return removed
+
+def plan_download(
+    repo_id: str,
+    dst: Path,
+    token: Optional[str] = None,
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> List[Tuple[str, int, bool]]:
+    """
+    List what a pull of a repo into a directory would download.
+    
+    Only the repo file listing is fetched; nothing is written to disk.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param dst: Destination directory.
+    :param token: Optional HuggingFace token for private repos.
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: List of (file name, size in bytes, already cached) tuples.
+    """
+    entries = {name: (size, digest) for name, size, digest in list_repo_entries(repo_id, token=token, revision=revision, hub=hub)}
+    return [(name, size, is_cached(dst / name, size, digest)) for name, (size, digest) in entries.items()]
+
+def _is_retryable(error: Exception) -> bool:
+    """
+    Decide whether a failed request is worth retrying.
+    
+    Connection errors, timeouts and 5xx responses are transient. Client
+    errors (4xx) such as a missing file or bad token are not, except for
+    request timeouts and rate limiting.
+    
+    :param error: Exception raised by the request.
+    :return: True if the request should be retried.
+    """
+    if isinstance(error, DownloadCancelled):
+        return False
+    if isinstance(error, requests.HTTPError) and error.response is not None:
+        status = error.response.status_code
+        return status >= 500 or status in (408, 429)
+    return True
+
+def bytes_transferred() -> int:
+    """
+    Get the number of bytes downloaded by this process so far.
+
+    Only bytes received over the network count; cached files and resumed
+    '.part' data do not.
+
+    :return: Total bytes transferred.
+    """
+    return _bytes_transferred
+
+def _count_bytes(n: int) -> None:
+    """Add received bytes to the process-wide counter."""
+    global _bytes_transferred
+    with _bytes_lock:
+        _bytes_transferred += n
+
+def _fetch(
+    url: str,
+    part: Path,
+    name: str,
+    total: int,
+    headers: Dict[str, str],
+    progress: Optional[ProgressCallback],
+    cancel: Optional[threading.Event],
+    request_kwargs: Optional[Dict] = None,
+) -> int:
+    """
+    Fetch a URL into a '.part' file, resuming from any bytes already on disk.
+    
+    :return: Total size of the completed file in bytes.
+    """
+    completed = part.stat().st_size if part.exists() else 0
+    headers = dict(headers)
+    if completed:
+        headers["Range"] = f"bytes={completed}-"
+
+    with requests.get(url, headers=headers, stream=True, timeout=60, **(request_kwargs or {})) as resp:
+        if resp.status_code == 416 and total and completed == total:
+            # Partial file already holds every byte
+            return completed
+        resp.raise_for_status()
+
+        if completed and resp.status_code != 206:
+            # Server ignored the range - start over
+            completed = 0
+        length = int(resp.headers.get("Content-Length", 0))
+        total = total or (completed + length)
+        last_report = 0.0
+
+        with open(part, "ab" if completed else "wb") as f:
+            for chunk in resp.iter_content(_CHUNK_SIZE):
+                if cancel is not None and cancel.is_set():
+                    raise DownloadCancelled(name)
+                f.write(chunk)
+                completed += len(chunk)
+                _count_bytes(len(chunk))
+
+                # Throttle callbacks so large files don't flood the stream
+                now = time.monotonic()
+                if progress and now - last_report >= _PROGRESS_INTERVAL:
+                    progress(name, completed, total)
+                    last_report = now
+
+    if completed < total:
+        # Keep the part file; the retry resumes from here
+        raise IncompleteDownload(f"{name}: connection closed after {completed} of {total} bytes")
+    return completed
+
+def download_file(
+    url: str,
+    dest: Path,
+    name: str,
+    total: int = 0,
+    headers: Optional[Dict[str, str]] = None,
+    progress: Optional[ProgressCallback] = None,
+    cancel: Optional[threading.Event] = None,
+    max_retries: int = 3,
+    hub: Optional[Hub] = None,
+    digest: Optional[str] = None,
+) -> None:
+    """
+    Download a single file with progress reporting.
+    
+    Transient failures are retried with exponential backoff and jitter,
+    resuming from the partial file with an HTTP Range request. The finished
+    file must have the expected size and digest before it is renamed into
+    place; otherwise the partial file is discarded and IntegrityError is
+    raised, so the next pull or mirror starts over.
+    
+    :param url: URL to download.
+    :param dest: Final destination path.
+    :param name: Name reported to the progress callback.
+    :param total: Expected size in bytes (0 if unknown).
+    :param headers: Optional HTTP headers (e.g., Authorization).
+    :param progress: Optional callback receiving (name, completed, total).
+    :param cancel: Optional event; when set the download stops and raises DownloadCancelled.
+    :param max_retries: Retries after the first attempt for transient errors.
+    :param hub: Registry connection settings, for TLS options. Defaults to the HuggingFace Hub.
+    :param digest: Expected digest from the repo listing, if known.
+    """
+    # Already downloaded by a previous pull
+    if is_cached(dest, total, digest):
+        if progress:
+            progress(name, total, total)
+        return
+
+    dest.parent.mkdir(parents=True, exist_ok=True)
+    part = dest.with_name(dest.name + ".part")
+    if total and part.exists() and part.stat().st_size > total:
+        # Cannot be resumed into the expected file
+        part.unlink()
+
+    completed = retry_call(
+        _fetch,
+        args=(url, part, name, total, headers or {}, progress, cancel, _request_kwargs(hub or Hub())),
+        max_attempts=max_retries + 1,
+        delay=1.0,
+        backoff=2.0,
+        max_delay=30.0,
+        jitter=0.5,
+        exceptions=(requests.RequestException, OSError),
+        should_retry=_is_retryable,
+        log_level=logging.DEBUG,
+    )
+
+    if (total and completed != total) or not matches_digest(part, digest):
+        part.unlink(missing_ok=True)
+        expected = f"{total} bytes" + (f", {digest}" if digest else "")
+        raise IntegrityError(f"{name}: downloaded {completed} bytes not matching the listing ({expected})")
+    os.replace(part, dest)
+    if progress:
+        progress(name, completed, total or completed)
+
+def _reuse(src: Path, dest: Path, size: int, digest: Optional[str]) -> bool:
+    """
+    Hard-link a file from another revision's directory if it matches the listing.
+    
+    Only files with a known digest are reused, and only into an empty slot;
+    anything already at dest is left to download_file to check.
+    
+    :return: True if the file was linked.
+    """
+    if digest is None or dest.exists() or not is_cached(src, size, digest):
+        return False
+    dest.parent.mkdir(parents=True, exist_ok=True)
+    try:
+        os.link(src, dest)
+    except OSError:
+        return False
+    return True
+
+def download_repo(
+    repo_id: str,
+    dst: Path,
+    token: Optional[str] = None,
+    revision: Optional[str] = None,
+    progress: Optional[ProgressCallback] = None,
+    max_workers: int = 3,
+    max_retries: int = 3,
+    cancel: Optional[threading.Event] = None,
+    hub: Optional[Hub] = None,
+    reuse_from: Optional[Path] = None,
+) -> List[Tuple[str, int]]:
+    """
+    Download every file of a HuggingFace model repo into a directory.
+    
+    All files are announced to the progress callback with zero completed
+    bytes before downloading starts, so totals are known up front. Up to
+    max_workers files are fetched at once. If one download fails the rest
+    are cancelled; files that already finished are kept so the next pull
+    resumes where this one stopped. Setting cancel stops the pull the same
+    way, within one chunk, and raises DownloadCancelled. A file that fails
+    on the hub's endpoint, after retries, is tried on each mirror in turn.
+    Once every file is in place, files the listing does not have are
+    removed from dst.
+    
+    :param repo_id: HuggingFace repo ID (e.g., 'openvla/openvla-7b').
+    :param dst: Destination directory.
+    :param token: Optional HuggingFace token for private repos.
+    :param revision: Optional branch, tag or commit. Defaults to the main branch.
+    :param progress: Optional callback receiving (name, completed, total).
+    :param max_workers: Maximum number of concurrent file downloads.
+    :param max_retries: Retries per file for transient errors.
+    :param cancel: Optional event the caller sets to abort the pull.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :param reuse_from: Optional directory holding another revision of the repo;
+                       files there matching the listing are hard-linked into
+                       dst instead of downloaded.
+    :return: List of (file name, size in bytes) tuples that were downloaded.
+    """
+    hub = hub or Hub()
+
+    # Never schedule the same destination twice
+    entries = {name: (size, digest) for name, size, digest in list_repo_entries(repo_id, token=token, revision=revision, hub=hub)}
+    files = [(name, size) for name, (size, _) in entries.items()]
+
+    if progress:
+        for name, size in files:
+            progress(name, 0, size)
+
+    cancel = cancel if cancel is not None else threading.Event()
+
+    def fetch(name: str, size: int) -> None:
+        if cancel.is_set():
+            raise DownloadCancelled(name)
+        digest = entries[name][1]
+        if reuse_from is not None and _reuse(reuse_from / name, dst / name, size, digest):
+            log.debug(f"Reused {repo_id}/{name} from {reuse_from}")
+            if progress:
+                progress(name, size, size)
+            return
+        log.debug(f"Downloading {repo_id}/{name} ({size} bytes)")
+
+        def attempt(endpoint: str) -> None:
+            # A partial file from a failed mirror is resumed from the next one and
+            # a bad splice fails the digest check; without a digest, start over
+            if digest is None and endpoint != hub.endpoint:
+                (dst / f"{name}.part").unlink(missing_ok=True)
+            download_file(
+                url=hub.file_url(repo_id, name, revision=revision, endpoint=endpoint),
+                dest=dst / name,
+                name=name,
+                total=size,
+                headers=_endpoint_headers(hub, endpoint, token),
+                progress=progress,
+                cancel=cancel,
+                max_retries=max_retries,
+                hub=hub,
+                digest=digest,
+            )
+
+        _from_endpoints(hub, f"{repo_id}/{name}", attempt)
+
+    first_error: Optional[Exception] = None
+    with ThreadPoolExecutor(max_workers=max(1, max_workers)) as pool:
+        futures = {pool.submit(fetch, name, size): name for name, size in files}
+        
+        for future in as_completed(futures):
+            try:
+                future.result()
+            except DownloadCancelled:
+                pass
+            except Exception as e:
+                if first_error is None:
+                    first_error = e
+                    log.error(f"Download of {futures[future]} failed, cancelling remaining files: {e}")
+                    cancel.set()
+                    for pending in futures:
+                        pending.cancel()
+
+    if first_error is not None:
+        raise first_error
+    if cancel.is_set():
+        raise DownloadCancelled(repo_id)
+
+    for name in remove_stale_files(dst, entries):
+        log.info(f"Removed {name}, which {repo_id}@{revision or 'main'} no longer has")
+
+    return files
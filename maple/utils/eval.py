@@ -285,13 +285,16 @@ class BatchEvaluator:
     def __init__(
         self,
         daemon_url: str = "http://127.0.0.1:8000",
+        force: bool = False,
     ):
         """
         Initialize the batch evaluator.
         
         :param daemon_url: URL of the MAPLE daemon (default: localhost:8000).
+        :param force: Run even if the policy has no adapter for the environment.
         """
         self.daemon_url = daemon_url.rstrip("/")
+        self.force = force
         self._session = None
     
     @property
@@ -405,6 +408,7 @@ class BatchEvaluator:
                     "model_kwargs": model_kwargs,
                     "save_video": save_video,
                     "video_path": video_path,
+                    "force": self.force,
                 },
                 timeout=max_steps * timeout,  # Generous timeout for episode
             )
@@ -0,0 +1,164 @@
+"""
+Registry connection settings.
+
+A Hub describes how pulls reach a model registry: the HuggingFace Hub or
+a self-hosted registry serving the same HTTP API. It holds the endpoint,
+any mirrors tried after it, and the TLS and proxy options of the registry HTTP client, built from
+the per-registry options in the config file (policy.registries) and any
+command-line overrides.
+
+Without an explicit proxy, requests picks one up from HTTP_PROXY,
+HTTPS_PROXY and NO_PROXY in the environment of the process making the
+request - for pulls, the daemon.
+
+These settings only apply to outgoing registry requests. The daemon's
+own server is not affected.
+"""
+
+import hashlib
+from pathlib import Path
+from dataclasses import dataclass, field
+from typing import Any, Dict, List, Optional, Union
+from urllib.parse import quote
+
+from maple.utils.paths import maple_home
+from maple.utils.auth import normalize_registry
+from maple.utils.config import get_config
+
+@dataclass
+class Hub:
+    """
+    Connection settings for one registry.
+    """
+    # Base URL of the registry
+    endpoint: str = "https://huggingface.co"
+    # True to verify TLS with the system CAs, a CA bundle path, or False to skip verification
+    verify: Union[bool, str] = True
+    # Proxy URL for every registry request, overriding the environment
+    proxy: Optional[str] = None
+    # Endpoints serving the same repos, tried in order when the endpoint fails
+    mirrors: List[str] = field(default_factory=list)
+
+    @property
+    def endpoints(self) -> List[str]:
+        """
+        Every endpoint to try, primary first.
+
+        :return: List of base URLs.
+        """
+        return [self.endpoint] + [m for m in self.mirrors if m != self.endpoint]
+
+    @property
+    def insecure(self) -> bool:
+        """
+        Whether TLS verification is turned off.
+
+        :return: True if certificates are not verified.
+        """
+        return self.verify is False
+
+    def request_kwargs(self) -> Dict[str, Any]:
+        """
+        Keyword arguments passed to every registry request.
+
+        :return: Dictionary for requests.get and friends.
+        """
+        kwargs: Dict[str, Any] = {"verify": self.verify}
+        if self.proxy:
+            kwargs["proxies"] = {"http": self.proxy, "https": self.proxy}
+        return kwargs
+
+    def api_url(self, repo_id: str, revision: Optional[str] = None, endpoint: Optional[str] = None) -> str:
+        """
+        Build the URL of a repo's metadata.
+
+        :param repo_id: Repo ID (e.g., 'openvla/openvla-7b').
+        :param revision: Optional branch, tag or commit.
+        :param endpoint: Endpoint to use. Defaults to the primary endpoint.
+        :return: Metadata URL.
+        """
+        url = f"{endpoint or self.endpoint}/api/models/{repo_id}"
+        if revision:
+            url += f"/revision/{quote(revision, safe='')}"
+        return url
+
+    def file_url(
+        self, 
+        repo_id: str, 
+        filename: str, 
+        revision: Optional[str] = None, 
+        endpoint: Optional[str] = None,
+    ) -> str:
+        """
+        Build the download URL of a file in a repo.
+
+        :param repo_id: Repo ID (e.g., 'openvla/openvla-7b').
+        :param filename: Path of the file inside the repo.
+        :param revision: Optional branch, tag or commit. Defaults to main.
+        :param endpoint: Endpoint to use. Defaults to the primary endpoint.
+        :return: Download URL.
+        """
+        revision = quote(revision or "main", safe="")
+        return f"{endpoint or self.endpoint}/{repo_id}/resolve/{revision}/{quote(filename)}"
+
+def ca_bundle(ca_cert: str) -> str:
+    """
+    Build a CA bundle trusting the system CAs plus a custom CA.
+
+    requests only accepts a single bundle, so the default certifi bundle
+    and the custom certificate are combined into ~/.maple/certs. The file
+    is named after its contents and reused until either changes.
+
+    :param ca_cert: Path to a PEM file with one or more CA certificates.
+    :return: Path to the combined bundle.
+    :raises FileNotFoundError: If ca_cert does not exist.
+    """
+    import certifi
+
+    custom = Path(ca_cert).expanduser().read_bytes()
+    combined = Path(certifi.where()).read_bytes().rstrip(b"\n") + b"\n" + custom
+
+    path = maple_home() / "certs" / f"{hashlib.sha256(combined).hexdigest()[:16]}.pem"
+    if not path.exists():
+        path.parent.mkdir(parents=True, exist_ok=True)
+        tmp = path.with_suffix(".tmp")
+        tmp.write_bytes(combined)
+        tmp.replace(path)
+    return str(path)
+
+def hub_for(
+    registry: Optional[str] = None,
+    insecure: Optional[bool] = None,
+    ca_cert: Optional[str] = None,
+    proxy: Optional[str] = None,
+) -> Hub:
+    """
+    Build connection settings for a registry.
+
+    Arguments left as None fall back to the registry's entry in
+    policy.registries. Skipping verification wins over a CA bundle.
+    Mirrors only come from the config and share the registry's options.
+
+    :param registry: Registry host or URL. None for policy.registry.
+    :param insecure: Skip TLS certificate verification.
+    :param ca_cert: Path to a PEM file of CAs trusted on top of the system CAs.
+    :param proxy: Proxy URL (e.g., 'http://proxy:3128'). None for the environment.
+    :return: Hub for the registry.
+    """
+    config = get_config()
+    host = normalize_registry(registry or config.policy.registry)
+    options = config.policy.registries.get(host) or {}
+
+    if insecure is None:
+        insecure = bool(options.get("insecure", False))
+    ca_cert = ca_cert or options.get("ca_cert")
+    proxy = proxy or options.get("proxy")
+
+    verify: Union[bool, str] = True
+    if insecure:
+        verify = False
+    elif ca_cert:
+        verify = ca_bundle(ca_cert)
+
+    mirrors = [m.rstrip("/") for m in options.get("mirrors", [])]
+    return Hub(endpoint=f"https://{host}", verify=verify, proxy=proxy, mirrors=mirrors)
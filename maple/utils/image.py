@@ -0,0 +1,132 @@
+"""
+Image preprocessing for policy inference.
+
+This module turns user-supplied images into the base64 PNG payloads that
+policy backends expect, at the input size the policy was trained on.
+Images are center-cropped to the target aspect ratio before resizing so
+objects are not stretched.
+
+Only PNG and JPEG inputs are accepted. Anything else raises ImageError
+instead of being passed to the model as a garbage tensor.
+"""
+
+import io
+import base64
+import binascii
+from pathlib import Path
+from typing import Any, Dict, Optional, Tuple, Union
+from PIL import Image, UnidentifiedImageError
+
+SUPPORTED_FORMATS = ("PNG", "JPEG")
+
+class ImageError(ValueError):
+    """Raised when an image cannot be decoded or is in an unsupported format."""
+
+def decode_image(data: bytes, source: str = "image") -> Image.Image:
+    """
+    Decode PNG or JPEG bytes.
+
+    :param data: Encoded image bytes.
+    :param source: Name used in error messages (e.g., the file path).
+    :return: Decoded RGB image.
+    :raises ImageError: If the bytes are not a PNG or JPEG image.
+    """
+    try:
+        img = Image.open(io.BytesIO(data))
+        img.load()
+    except (OSError, UnidentifiedImageError) as e:
+        raise ImageError(f"Cannot read {source}: {e}")
+
+    if img.format not in SUPPORTED_FORMATS:
+        raise ImageError(
+            f"Unsupported format {img.format} for {source} (expected {' or '.join(SUPPORTED_FORMATS)})"
+        )
+    return img.convert("RGB")
+
+def load_image_file(path: Union[str, Path]) -> Image.Image:
+    """
+    Load a PNG or JPEG file.
+
+    :param path: Image file.
+    :return: Decoded RGB image.
+    :raises ImageError: If the file is missing, unreadable or unsupported.
+    """
+    try:
+        data = Path(path).read_bytes()
+    except OSError as e:
+        raise ImageError(f"Cannot read {path}: {e.strerror or e}")
+    return decode_image(data, source=str(path))
+
+def decode_base64_image(image_b64: str) -> Image.Image:
+    """
+    Decode a base64-encoded PNG or JPEG.
+
+    :param image_b64: Base64 string as sent to /policy/act.
+    :return: Decoded RGB image.
+    :raises ImageError: If the string is not base64 or not a supported image.
+    """
+    try:
+        data = base64.b64decode(image_b64, validate=True)
+    except (binascii.Error, ValueError) as e:
+        raise ImageError(f"Image is not valid base64: {e}")
+    return decode_image(data)
+
+def center_crop_resize(img: Image.Image, size: Tuple[int, int]) -> Image.Image:
+    """
+    Center-crop an image to the target aspect ratio, then resize it.
+
+    :param img: Image to transform.
+    :param size: Target (width, height).
+    :return: Image of exactly the target size.
+    """
+    if img.size == size:
+        return img
+
+    width, height = img.size
+    target_w, target_h = size
+    # Largest box with the target aspect ratio that fits in the image
+    if width * target_h > height * target_w:
+        crop_w, crop_h = height * target_w // target_h, height
+    else:
+        crop_w, crop_h = width, width * target_h // target_w
+    left, top = (width - crop_w) // 2, (height - crop_h) // 2
+
+    img = img.crop((left, top, left + crop_w, top + crop_h))
+    return img.resize(size, Image.Resampling.LANCZOS)
+
+def encode_png(img: Image.Image) -> str:
+    """
+    Encode an image as base64 PNG.
+
+    :param img: Image to encode.
+    :return: Base64 string.
+    """
+    buf = io.BytesIO()
+    img.save(buf, format="PNG")
+    return base64.b64encode(buf.getvalue()).decode()
+
+def policy_image_size(info: Dict[str, Any]) -> Optional[Tuple[int, int]]:
+    """
+    Read the input size from policy metadata.
+
+    :param info: Policy info returned by /policy/info.
+    :return: (width, height), or None if the policy does not report one.
+    """
+    size = info.get("image_size")
+    if isinstance(size, int):
+        return (size, size)
+    if isinstance(size, (list, tuple)) and len(size) >= 2:
+        return (int(size[0]), int(size[1]))
+    return None
+
+def preprocess(img: Image.Image, size: Optional[Tuple[int, int]]) -> str:
+    """
+    Prepare an image for a policy.
+
+    :param img: Decoded image.
+    :param size: Policy input (width, height), or None to keep the image size.
+    :return: Base64 PNG at the requested size.
+    """
+    if size is not None:
+        img = center_crop_resize(img.convert("RGB"), size)
+    return encode_png(img)
@@ -2,7 +2,7 @@
 Daemon locking utilities.
 
 This module provides Unix socket-based locking to ensure only one instance
-of the MAPLE daemon runs at a time. It uses filesystem Unix domain sockets
+of the MAPLE daemon runs per MAPLE home at a time. It uses filesystem Unix domain sockets
 as a locking mechanism, which automatically releases when a process terminates.
 
 Key features:
@@ -15,20 +15,35 @@ Key features:
 The DaemonLock class uses bind() semantics on Unix sockets - only one process
 can bind to a socket path at a time. This provides a reliable, OS-level locking
 mechanism that automatically releases if the daemon crashes.
+
+lock_ref() serializes writers of a single policy (name:version) with an
+flock on a per-ref lock file, so concurrent pulls and removals of the same
+policy cannot interleave their writes to the weights directory and store.
+
+PidFile records the daemon serving a MAPLE home in <home>/maple.pid, held
+with an flock for as long as the daemon runs, so a second 'maple serve'
+against the same root is refused and 'maple stop' knows which process to
+signal.
 """
 
 import os
+import json
+import time
+import fcntl
+import signal
+import hashlib
 import socket
 from pathlib import Path
-from typing import Optional
+from contextlib import contextmanager
+from typing import Any, Dict, Iterator, Optional
 
 from maple.utils.logging import get_logger
+from maple.utils.paths import maple_home
 
 log = get_logger("lock")
 
-# Socket path - in user's runtime dir or /tmp
+# Socket directory - user's runtime dir or /tmp
 _SOCKET_DIR = Path(os.environ.get("XDG_RUNTIME_DIR", "/tmp"))
-_SOCKET_PATH = _SOCKET_DIR / "vla-daemon.sock"
 
 class DaemonLock:
     """
@@ -54,10 +69,10 @@ class DaemonLock:
         acquire the lock - call acquire() or use as context manager.
         
         :param socket_path: Optional custom path for the socket file.
-                           Defaults to XDG_RUNTIME_DIR/vla-daemon.sock
-                           or /tmp/vla-daemon.sock.
+                           Defaults to the current MAPLE home's socket,
+                           see get_socket_path().
         """
-        self.socket_path = socket_path or _SOCKET_PATH
+        self.socket_path = socket_path or get_socket_path()
         self._socket: Optional[socket.socket] = None
 
     def acquire(self) -> bool:
@@ -184,10 +199,10 @@ def is_daemon_running(socket_path: Path = None) -> bool:
     running daemon.
     
     :param socket_path: Optional custom socket path to check.
-                       Defaults to the standard daemon socket path.
+                       Defaults to the current MAPLE home's socket.
     :return: True if daemon is running and responsive, False otherwise.
     """
-    socket_path = socket_path or _SOCKET_PATH
+    socket_path = socket_path or get_socket_path()
 
     if not socket_path.exists():
         return False
@@ -201,14 +216,212 @@ def is_daemon_running(socket_path: Path = None) -> bool:
     except (socket.error, OSError):
         return False
 
+def ref_lock_path(name: str, version: str) -> Path:
+    """
+    Get the lock file path for a policy ref.
+    
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy model.
+    :return: Path to the ref's lock file under ~/.maple/locks.
+    """
+    return maple_home() / "locks" / f"{name}-{version}.lock"
+
+@contextmanager
+def lock_ref(name: str, version: str, blocking: bool = True) -> Iterator[None]:
+    """
+    Hold an exclusive lock on a policy ref for the duration of the block.
+    
+    Blocks until any other writer of the same name:version, in this or
+    another process, has released it. The lock is released when the block
+    exits, including on errors, and by the OS if the process dies.
+    
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy model.
+    :param blocking: If False, fail instead of waiting for another writer.
+    :raises BlockingIOError: If blocking is False and the ref is locked.
+    """
+    path = ref_lock_path(name, version)
+    path.parent.mkdir(parents=True, exist_ok=True)
+
+    with open(path, "w") as f:
+        fcntl.flock(f, fcntl.LOCK_EX if blocking else fcntl.LOCK_EX | fcntl.LOCK_NB)
+        log.debug(f"Ref lock acquired: {name}:{version}")
+        try:
+            yield
+        finally:
+            fcntl.flock(f, fcntl.LOCK_UN)
+            log.debug(f"Ref lock released: {name}:{version}")
+
+def pid_file() -> Path:
+    """
+    Get the daemon's PID file for the current MAPLE home.
+    
+    :return: Path to maple.pid in the MAPLE home directory.
+    """
+    return maple_home() / "maple.pid"
+
+def pid_alive(pid: int) -> bool:
+    """
+    Check whether a process exists.
+    
+    :param pid: Process ID.
+    :return: True if the process exists, even if owned by another user.
+    """
+    try:
+        os.kill(pid, 0)
+    except ProcessLookupError:
+        return False
+    except PermissionError:
+        return True
+    return True
+
+def stop_process(pid: int, timeout: float = 30.0, poll: float = 0.2) -> bool:
+    """
+    Ask a process to exit with SIGTERM, and SIGKILL it if it doesn't.
+    
+    :param pid: Process ID.
+    :param timeout: Seconds to wait after SIGTERM before sending SIGKILL.
+    :param poll: Seconds between checks whether the process has exited.
+    :return: True if it exited on SIGTERM, False if it had to be killed.
+    """
+    try:
+        os.kill(pid, signal.SIGTERM)
+    except ProcessLookupError:
+        return True
+
+    deadline = time.monotonic() + timeout
+    while time.monotonic() < deadline:
+        if not pid_alive(pid):
+            return True
+        time.sleep(poll)
+
+    log.warning(f"Process {pid} did not exit within {timeout:g}s, sending SIGKILL")
+    try:
+        os.kill(pid, signal.SIGKILL)
+    except ProcessLookupError:
+        return True
+    while pid_alive(pid):
+        time.sleep(poll)
+    return False
+
+def read_pid_file(path: Path = None) -> Optional[Dict[str, Any]]:
+    """
+    Read the daemon record from a PID file.
+    
+    :param path: PID file. Defaults to the current MAPLE home's.
+    :return: Record with at least 'pid', or None if the file is missing
+            or unreadable. The process may no longer exist.
+    """
+    try:
+        info = json.loads((path or pid_file()).read_text())
+    except (OSError, ValueError):
+        return None
+    return info if isinstance(info, dict) and isinstance(info.get("pid"), int) else None
+
+def running_daemon(path: Path = None) -> Optional[Dict[str, Any]]:
+    """
+    Find the live daemon serving the current MAPLE home.
+    
+    A record counts only while its daemon still holds the file's lock and
+    the process exists; a file left behind by a crash is ignored.
+    
+    :param path: PID file. Defaults to the current MAPLE home's.
+    :return: The daemon's record (pid, port, ...), or None if none is running.
+    """
+    path = path or pid_file()
+    info = read_pid_file(path)
+    if info is None:
+        return None
+
+    try:
+        with open(path, "r") as f:
+            fcntl.flock(f, fcntl.LOCK_SH | fcntl.LOCK_NB)
+            fcntl.flock(f, fcntl.LOCK_UN)
+        return None  # Nobody holds the lock
+    except BlockingIOError:
+        pass
+    except OSError:
+        return None
+
+    return info if pid_alive(info["pid"]) else None
+
+class PidFile:
+    """
+    Exclusive PID file for the daemon serving a MAPLE home.
+    
+    The file is flocked for the life of the daemon, so the OS releases it
+    if the process dies; a later daemon then reclaims the stale file. The
+    record is JSON so it can carry more than the PID (e.g., the port).
+    """
+
+    def __init__(self, path: Path = None):
+        """
+        Initialize the PidFile. Does not acquire it.
+        
+        :param path: PID file path. Defaults to <home>/maple.pid.
+        """
+        self.path = path or pid_file()
+        self._fd: Optional[int] = None
+
+    def acquire(self, **info: Any) -> bool:
+        """
+        Take the PID file and record this process in it.
+        
+        :param info: Extra fields stored alongside the PID (e.g., port).
+        :return: True if acquired, False if a live daemon holds it.
+        """
+        if self._fd is not None:
+            return True
+
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        fd = os.open(self.path, os.O_RDWR | os.O_CREAT, 0o644)
+        try:
+            fcntl.flock(fd, fcntl.LOCK_EX | fcntl.LOCK_NB)
+        except BlockingIOError:
+            os.close(fd)
+            log.debug(f"PID file held by another daemon: {self.path}")
+            return False
+
+        stale = read_pid_file(self.path)
+        if stale and stale["pid"] != os.getpid():
+            log.info(f"Reclaiming stale PID file of process {stale['pid']}")
+
+        record = {"pid": os.getpid(), **info}
+        os.ftruncate(fd, 0)
+        os.lseek(fd, 0, os.SEEK_SET)
+        os.write(fd, json.dumps(record).encode())
+        self._fd = fd
+        log.debug(f"PID file acquired: {self.path}")
+        return True
+
+    def release(self) -> None:
+        """
+        Remove the PID file and drop its lock.
+        
+        Safe to call multiple times or when not held.
+        """
+        if self._fd is None:
+            return
+        try:
+            self.path.unlink()
+        except OSError:
+            pass
+        fcntl.flock(self._fd, fcntl.LOCK_UN)
+        os.close(self._fd)
+        self._fd = None
+        log.debug("PID file released")
+
 def get_socket_path() -> Path:
     """
-    Get the default daemon socket path.
+    Get the daemon socket path for the current MAPLE home.
     
-    Returns the path where the daemon socket file is located. This
-    respects the XDG_RUNTIME_DIR environment variable if set, otherwise
-    falls back to /tmp.
+    The socket lives in XDG_RUNTIME_DIR if set, otherwise /tmp, and is
+    named after a hash of the MAPLE home, so daemons started with
+    different --root directories do not block each other. The home
+    itself is not used as the directory because socket paths are limited
+    to about 100 characters.
     
     :return: Path object pointing to the daemon socket file location.
     """
-    return _SOCKET_PATH
\ No newline at end of file
+    digest = hashlib.sha256(str(maple_home().resolve()).encode()).hexdigest()[:12]
+    return _SOCKET_DIR / f"maple-daemon-{digest}.sock"
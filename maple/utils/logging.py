@@ -12,19 +12,45 @@ Key features:
 - Verbose mode with source location information
 - Automatic suppression of noisy third-party library logs
 - Namespaced loggers with "maple." prefix for MAPLE components
+- Daemon log files under <home>/logs: server.log for everything and
+  <model>.log for records tagged with a policy name
+- Size-based rotation of the daemon's log files with gzip-compressed
+  backups and an optional maximum backup age
+- Text or JSON-lines output for the daemon, for log aggregators
 
 The module uses a global flag to ensure logging is only configured once,
 even if setup_logging() is called multiple times. All MAPLE loggers use
 the "maple." namespace prefix for easy filtering.
 """
 
+import os
 import sys
+import gzip
+import json
+import time
+import shutil
 import logging
+import logging.handlers
 from pathlib import Path
-from typing import Optional
+from datetime import datetime, timezone
+from typing import Dict, Optional
+
+from maple.utils.paths import maple_home
 
 _CONFIGURED = False
 
+# Shared by console and file output so 'maple logs' can parse either
+LOG_FORMAT = "%(asctime)s | %(levelname)-8s | %(message)s"
+VERBOSE_LOG_FORMAT = "%(asctime)s | %(levelname)-8s | %(name)s:%(lineno)d | %(message)s"
+DATE_FORMAT = "%Y-%m-%d %H:%M:%S"
+
+# Output formats of the daemon's logs
+LOG_FORMATS = ("text", "json")
+
+# Rotation defaults for the daemon's log files
+DEFAULT_MAX_BYTES = 10 * 1024 * 1024
+DEFAULT_BACKUP_COUNT = 3
+
 def setup_logging(level: str = "INFO",
                   log_file: Optional[Path] = None,
                   verbose: bool = False
@@ -63,12 +89,8 @@ def setup_logging(level: str = "INFO",
         return
 
     # Configure message format based on verbosity
-    if verbose:
-        fmt = "%(asctime)s | %(levelname)-8s | %(name)s:%(lineno)d | %(message)s"
-    else:
-        fmt = "%(asctime)s | %(levelname)-8s | %(message)s"
-
-    datefmt = "%Y-%m-%d %H:%M:%S"
+    fmt = VERBOSE_LOG_FORMAT if verbose else LOG_FORMAT
+    datefmt = DATE_FORMAT
 
     # Setup handlers
     handlers = [logging.StreamHandler(sys.stdout)]
@@ -110,4 +132,250 @@ def get_logger(name: str) -> logging.Logger:
                 "health"). The "maple." prefix is automatically added.
     :return: Configured logging.Logger instance with "maple." namespace.
     """
-    return logging.getLogger(f"maple.{name}")
\ No newline at end of file
+    return logging.getLogger(f"maple.{name}")
+
+def logs_dir() -> Path:
+    """
+    Get the directory holding the daemon's log files.
+    
+    :return: Path to 'logs' in the MAPLE home directory.
+    """
+    return maple_home() / "logs"
+
+def server_log_file() -> Path:
+    """
+    Get the daemon's main log file.
+    
+    :return: Path to server.log in the logs directory.
+    """
+    return logs_dir() / "server.log"
+
+def model_log_file(model: str) -> Path:
+    """
+    Get the log file for a single policy.
+    
+    :param model: Policy name (e.g., 'openvla'). A version suffix such as
+                 ':7b' is ignored; all versions share one file.
+    :return: Path to <model>.log in the logs directory.
+    """
+    return logs_dir() / f"{model.split(':', 1)[0]}.log"
+
+def _gzip_rotate(source: str, dest: str) -> None:
+    """
+    Compress a full log file into its backup name.
+    
+    :param source: Log file being rotated out.
+    :param dest: Backup path (ends in .gz).
+    """
+    with open(source, "rb") as src, gzip.open(dest, "wb") as dst:
+        shutil.copyfileobj(src, dst)
+    os.remove(source)
+
+class RotatingLogHandler(logging.handlers.RotatingFileHandler):
+    """
+    Size-rotated log file with compressed, age-limited backups.
+    
+    When the file would grow past max_bytes it is gzipped to <file>.1.gz,
+    older backups shift up to <file>.N.gz and the oldest is dropped.
+    Backups older than max_age seconds are deleted at startup and on
+    every rotation.
+    """
+
+    def __init__(
+        self,
+        path: Path,
+        max_bytes: int = DEFAULT_MAX_BYTES,
+        backup_count: int = DEFAULT_BACKUP_COUNT,
+        max_age: Optional[float] = None,
+    ):
+        """
+        Open the log file for appending.
+        
+        :param path: Log file path. The parent directory is created.
+        :param max_bytes: Rotate once the file reaches this size. 0 never rotates.
+        :param backup_count: Number of compressed backups to keep.
+        :param max_age: Delete backups older than this many seconds. None keeps
+                       them until pushed out by backup_count.
+        """
+        path.parent.mkdir(parents=True, exist_ok=True)
+        super().__init__(path, maxBytes=max_bytes, backupCount=backup_count)
+        self.max_age = max_age
+        self.namer = lambda name: f"{name}.gz"
+        self.rotator = _gzip_rotate
+        self.remove_expired()
+
+    def backups(self) -> list:
+        """
+        List this file's rotated backups.
+        
+        :return: Paths of existing <file>.N.gz backups.
+        """
+        base = Path(self.baseFilename)
+        return sorted(base.parent.glob(f"{base.name}.*.gz"))
+
+    def remove_expired(self, now: Optional[float] = None) -> None:
+        """
+        Delete backups older than max_age.
+        
+        :param now: Reference time. Defaults to the current time.
+        """
+        if self.max_age is None:
+            return
+        cutoff = (now or time.time()) - self.max_age
+        for backup in self.backups():
+            try:
+                if backup.stat().st_mtime < cutoff:
+                    backup.unlink()
+            except OSError:
+                continue
+
+    def doRollover(self) -> None:
+        """
+        Rotate the file, then drop backups past the age limit.
+        """
+        super().doRollover()
+        self.remove_expired()
+
+class ModelLogHandler(logging.Handler):
+    """
+    Route records tagged with a policy name to that policy's log file.
+    
+    Records opt in by passing extra={"model": name} to the logger call;
+    untagged records are ignored. One file handler is opened lazily per
+    policy name and kept for the life of the handler. Each file rotates
+    like server.log.
+    """
+
+    def __init__(
+        self,
+        formatter: logging.Formatter,
+        max_bytes: int = DEFAULT_MAX_BYTES,
+        backup_count: int = DEFAULT_BACKUP_COUNT,
+        max_age: Optional[float] = None,
+    ):
+        """
+        Initialize the handler.
+        
+        :param formatter: Formatter applied to every per-model file.
+        :param max_bytes: Rotate each file once it reaches this size. 0 never rotates.
+        :param backup_count: Number of compressed backups to keep per file.
+        :param max_age: Delete backups older than this many seconds.
+        """
+        super().__init__()
+        self.setFormatter(formatter)
+        self.max_bytes = max_bytes
+        self.backup_count = backup_count
+        self.max_age = max_age
+        self._files: Dict[str, logging.Handler] = {}
+
+    def emit(self, record: logging.LogRecord) -> None:
+        """
+        Write a record to its policy's log file if it carries one.
+        
+        :param record: Log record to write.
+        """
+        model = getattr(record, "model", None)
+        if not model:
+            return
+        handler = self._files.get(model)
+        if handler is None:
+            handler = RotatingLogHandler(model_log_file(model), self.max_bytes, self.backup_count, self.max_age)
+            handler.setFormatter(self.formatter)
+            self._files[model] = handler
+        handler.emit(record)
+
+    def close(self) -> None:
+        """
+        Close every per-model file.
+        """
+        for handler in self._files.values():
+            handler.close()
+        self._files.clear()
+        super().close()
+
+class JsonFormatter(logging.Formatter):
+    """
+    Format each record as one JSON object per line.
+    
+    Every object has 'ts' (ISO 8601, UTC), 'level', 'logger' and 'msg'.
+    Fields passed with extra=..., such as the access log's method, path,
+    status, duration_ms and remote_addr, are added when present.
+    """
+
+    # Record attributes copied into the object when a logger call sets them
+    FIELDS = ("method", "path", "status", "duration_ms", "remote_addr", "model")
+
+    def format(self, record: logging.LogRecord) -> str:
+        """
+        Serialize a record.
+        
+        :param record: Log record to format.
+        :return: JSON object on a single line.
+        """
+        entry = {
+            "ts": datetime.fromtimestamp(record.created, timezone.utc).isoformat(timespec="milliseconds"),
+            "level": record.levelname,
+            "logger": record.name,
+            "msg": record.getMessage(),
+        }
+        for field in self.FIELDS:
+            value = getattr(record, field, None)
+            if value is not None:
+                entry[field] = value
+        if record.exc_info:
+            entry["exc"] = self.formatException(record.exc_info)
+        return json.dumps(entry)
+
+def make_formatter(log_format: str = "text") -> logging.Formatter:
+    """
+    Create the formatter for a daemon log format.
+    
+    :param log_format: 'text' for the ' | '-separated lines 'maple logs'
+                      shows by default, or 'json' for one object per line.
+    :return: Formatter instance.
+    :raises ValueError: If the format is unknown.
+    """
+    if log_format == "json":
+        return JsonFormatter()
+    if log_format != "text":
+        raise ValueError(f"Unknown log format '{log_format}', expected one of: {', '.join(LOG_FORMATS)}")
+    return logging.Formatter(LOG_FORMAT, datefmt=DATE_FORMAT)
+
+def enable_server_logs(
+    max_bytes: int = DEFAULT_MAX_BYTES,
+    backup_count: int = DEFAULT_BACKUP_COUNT,
+    max_age: Optional[float] = None,
+    log_format: str = "text",
+) -> None:
+    """
+    Write MAPLE log records to the daemon's log files.
+    
+    Attaches handlers to the "maple" logger so every record also lands in
+    server.log, and records tagged with a policy name in <model>.log. Called
+    once by the daemon at startup; the CLI keeps logging to the console only.
+    Safe to call more than once.
+    
+    With log_format='json' the console output switches to JSON too, so an
+    aggregator can read either stdout or the files.
+    
+    :param max_bytes: Rotate a log file once it reaches this size. 0 never rotates.
+    :param backup_count: Number of compressed backups to keep per file.
+    :param max_age: Delete backups older than this many seconds. None
+                   disables the age limit.
+    :param log_format: 'text' or 'json'.
+    """
+    logger = logging.getLogger("maple")
+    if any(getattr(h, "_maple_server_log", False) for h in logger.handlers):
+        return
+
+    formatter = make_formatter(log_format)
+    if log_format == "json":
+        for handler in logging.getLogger().handlers:
+            handler.setFormatter(formatter)
+
+    server = RotatingLogHandler(server_log_file(), max_bytes, backup_count, max_age)
+    server.setFormatter(formatter)
+
+    for handler in (server, ModelLogHandler(formatter, max_bytes, backup_count, max_age)):
+        handler._maple_server_log = True
+        logger.addHandler(handler)
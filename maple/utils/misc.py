@@ -10,11 +10,16 @@ Key utilities:
 - parse_policy_env: Parse policy@env shorthand notation
 - parse_error_response: Parse response JSON in case of error
 - load_kwargs: Load string kwargs properly into dict
+- parse_duration: Parse keep-alive style durations (e.g., '5m', '30s')
+- format_size: Format a byte count for display
+- parse_size: Parse a size such as '10MB' into bytes
 """
 
+import re
 import json
+import time
 import typer 
-from typing import Tuple, Dict
+from typing import Tuple, Dict, Optional, Union
 
 def daemon_url(port: int):
     """
@@ -113,4 +118,129 @@ def load_kwargs(kwargs: str) -> Dict:
     else:
         kwargs = {}
 
-    return kwargs
\ No newline at end of file
+    return kwargs
+
+_DURATION_UNITS = {"ms": 0.001, "s": 1, "m": 60, "h": 3600, "d": 86400}
+_DURATION_RE = re.compile(r"(\d+(?:\.\d+)?)(ms|s|m|h|d)")
+
+def parse_duration(value: Union[str, int, float, None]) -> Optional[float]:
+    """
+    Parse a duration into seconds.
+    
+    Accepts plain numbers (seconds) or unit-suffixed strings such as '30s',
+    '5m', '1h', '7d' and combinations like '1h30m'. A negative value means
+    "never expire" and is returned as None.
+    
+    :param value: Duration as a number of seconds or a unit-suffixed string.
+    :return: Duration in seconds, or None for an infinite duration.
+    """
+    if value is None:
+        return None
+
+    if isinstance(value, (int, float)):
+        seconds = float(value)
+    else:
+        text = value.strip().lower()
+        try:
+            seconds = float(text)
+        except ValueError:
+            negative = text.startswith("-")
+            body = text[1:] if negative else text
+            
+            # The whole string must be made of <number><unit> pairs
+            if not body or _DURATION_RE.sub("", body):
+                raise ValueError(f"Invalid duration: '{value}' (expected e.g. 30s, 5m, 1h)")
+            
+            seconds = sum(float(n) * _DURATION_UNITS[u] for n, u in _DURATION_RE.findall(body))
+            if negative:
+                seconds = -seconds
+
+    return None if seconds < 0 else seconds
+
+def format_size(num_bytes: Optional[int]) -> str:
+    """
+    Format a byte count as a human readable size.
+    
+    :param num_bytes: Size in bytes.
+    :return: Size string such as '14.2 GB', or '-' if unknown.
+    """
+    if num_bytes is None:
+        return "-"
+
+    size = float(num_bytes)
+    for unit in ("B", "KB", "MB", "GB"):
+        if size < 1024:
+            return f"{size:.0f} {unit}" if unit == "B" else f"{size:.1f} {unit}"
+        size /= 1024
+    return f"{size:.1f} TB"
+
+# Multipliers for size suffixes, binary like format_size
+_SIZE_UNITS = {"": 1, "B": 1, "K": 1024, "KB": 1024, "M": 1024 ** 2, "MB": 1024 ** 2,
+               "G": 1024 ** 3, "GB": 1024 ** 3, "T": 1024 ** 4, "TB": 1024 ** 4}
+
+def parse_size(value: Union[str, int]) -> int:
+    """
+    Parse a size written as '10MB', '1.5GB' or a plain byte count.
+    
+    Units are binary (1KB = 1024 bytes), matching format_size.
+    
+    :param value: Size string or number of bytes.
+    :return: Size in bytes.
+    :raises ValueError: If the value is not a size.
+    """
+    if isinstance(value, int) and not isinstance(value, bool):
+        return value
+    match = re.fullmatch(r"\s*(\d+(?:\.\d+)?)\s*([KMGT]?B?)\s*", str(value), re.IGNORECASE)
+    if not match:
+        raise ValueError(f"Invalid size: '{value}' (expected e.g. 500MB, 10GB)")
+    return int(float(match.group(1)) * _SIZE_UNITS[match.group(2).upper()])
+
+# Multipliers for parameter count suffixes such as '450M' or '7B'
+_PARAM_UNITS = {"K": 1e3, "M": 1e6, "B": 1e9, "T": 1e12}
+
+def parse_param_size(value: Optional[str]) -> Optional[int]:
+    """
+    Parse a parameter count written as '7B', '450M' or '1.5B'.
+    
+    :param value: Parameter count string.
+    :return: Number of parameters, or None if missing or not understood.
+    """
+    match = re.fullmatch(r"\s*(\d+(?:\.\d+)?)\s*([KMBT])\s*", value or "", re.IGNORECASE)
+    if not match:
+        return None
+    return int(float(match.group(1)) * _PARAM_UNITS[match.group(2).upper()])
+
+def estimate_vram(param_size: Optional[str], bytes_per_param: float = 2.0, overhead: float = 1.2) -> Optional[int]:
+    """
+    Estimate the GPU memory needed to load a model for inference.
+    
+    Weights are assumed to be half precision (2 bytes per parameter) with
+    20% on top for activations, CUDA context and framework buffers.
+    
+    :param param_size: Parameter count string (e.g., '7B').
+    :param bytes_per_param: Bytes per weight (2 for fp16/bf16, 1 for int8).
+    :param overhead: Multiplier for memory beyond the weights.
+    :return: Estimated bytes, or None if the parameter count is unknown.
+    """
+    params = parse_param_size(param_size)
+    if params is None:
+        return None
+    return int(params * bytes_per_param * overhead)
+
+def format_ago(timestamp: Optional[float], now: Optional[float] = None) -> str:
+    """
+    Format a past timestamp relative to now.
+    
+    :param timestamp: Unix timestamp in seconds.
+    :param now: Reference time. Defaults to the current time.
+    :return: Relative time such as '3 days ago', or '-' if unknown.
+    """
+    if timestamp is None:
+        return "-"
+
+    elapsed = max(0.0, (now if now is not None else time.time()) - timestamp)
+    for seconds, unit in ((86400 * 30, "month"), (86400 * 7, "week"), (86400, "day"), (3600, "hour"), (60, "minute")):
+        if elapsed >= seconds:
+            count = int(elapsed // seconds)
+            return f"{count} {unit}{'s' if count != 1 else ''} ago"
+    return "Just now"
@@ -0,0 +1,244 @@
+"""
+Modelfile parsing and local policy creation.
+
+A Modelfile assembles a custom policy from one that is already pulled,
+so fine-tuned checkpoints can be registered in the local store and served
+like any other policy:
+
+    # OpenVLA fine-tuned on kitchen demos
+    FROM openvla:7b
+    WEIGHTS ./checkpoint-5000
+    CONFIG norm_stats_key kitchen_v2
+    LABEL dataset=kitchen-v2
+
+Instructions (case-insensitive, one per line, '#' starts a comment):
+- FROM: Pulled policy to start from (required, once)
+- WEIGHTS: File or directory laid over the base files (repeatable).
+  Relative paths are resolved against the Modelfile's directory
+- CONFIG: Key and value set in the policy's config.json. Values are
+  parsed as JSON when possible, otherwise kept as strings
+- LABEL: key=value annotation stored with the policy
+"""
+
+import os
+import json
+import shlex
+import shutil
+from pathlib import Path
+from dataclasses import dataclass, field
+from typing import Any, Dict, List, Optional, Tuple
+
+from maple.state import store
+from maple.utils.lock import lock_ref
+from maple.utils.logging import get_logger
+from maple.utils.paths import policy_dir
+from maple.utils.spec import parse_versioned
+from maple.utils.download import ProgressCallback
+
+log = get_logger("modelfile")
+
+class PolicyExistsError(ValueError):
+    """Raised when creating a policy that already exists without overwrite."""
+
+@dataclass
+class Modelfile:
+    """
+    Parsed Modelfile.
+    """
+    # Pulled policy spec the new policy starts from (e.g., 'openvla:7b')
+    base: str
+    # Files and directories laid over the base weights, in order
+    weights: List[Path] = field(default_factory=list)
+    # Keys set in config.json
+    config: Dict[str, Any] = field(default_factory=dict)
+    # Annotations stored with the policy
+    labels: Dict[str, str] = field(default_factory=dict)
+
+def _parse_value(raw: str) -> Any:
+    """Parse a CONFIG value as JSON, falling back to the raw string."""
+    try:
+        return json.loads(raw)
+    except json.JSONDecodeError:
+        return raw
+
+def parse_modelfile(text: str, base_dir: Optional[Path] = None) -> Modelfile:
+    """
+    Parse the contents of a Modelfile.
+
+    :param text: Modelfile contents.
+    :param base_dir: Directory relative WEIGHTS paths are resolved against.
+                     Defaults to the current directory.
+    :return: Parsed Modelfile.
+    :raises ValueError: If a line is malformed, naming the line number.
+    """
+    base_dir = base_dir or Path.cwd()
+    base: Optional[str] = None
+    weights: List[Path] = []
+    config: Dict[str, Any] = {}
+    labels: Dict[str, str] = {}
+
+    for lineno, line in enumerate(text.splitlines(), start=1):
+        line = line.strip()
+        if not line or line.startswith("#"):
+            continue
+
+        instruction, _, rest = line.partition(" ")
+        instruction, rest = instruction.upper(), rest.strip()
+        if not rest:
+            raise ValueError(f"Line {lineno}: {instruction} needs an argument")
+
+        if instruction == "FROM":
+            if base is not None:
+                raise ValueError(f"Line {lineno}: FROM may only appear once")
+            base = rest
+        elif instruction == "WEIGHTS":
+            path = Path(shlex.split(rest)[0]).expanduser()
+            weights.append(path if path.is_absolute() else base_dir / path)
+        elif instruction == "CONFIG":
+            key, _, value = rest.partition(" ")
+            if not value.strip():
+                raise ValueError(f"Line {lineno}: CONFIG needs a key and a value")
+            config[key] = _parse_value(value.strip())
+        elif instruction == "LABEL":
+            key, sep, value = rest.partition("=")
+            if not sep or not key.strip():
+                raise ValueError(f"Line {lineno}: LABEL must be key=value")
+            labels[key.strip()] = value.strip()
+        else:
+            raise ValueError(f"Line {lineno}: unknown instruction '{instruction}'")
+
+    if base is None:
+        raise ValueError("Modelfile has no FROM instruction")
+    return Modelfile(base=base, weights=weights, config=config, labels=labels)
+
+def resolve_target(name: str, base: str) -> Tuple[str, str]:
+    """
+    Split the name of a policy to create into backend and version.
+
+    A bare name becomes a version of the base policy's backend.
+
+    :param name: 'backend:version' or a bare version name.
+    :param base: Base policy spec.
+    :return: Tuple of (backend name, version).
+    """
+    if ":" in name:
+        return parse_versioned(name)
+    return parse_versioned(base)[0], name.strip()
+
+def _place(src: Path, dst: Path) -> None:
+    """
+    Put a file in place, hard-linking when possible.
+
+    Base weights can be large, so they are linked rather than copied when
+    they live on the same filesystem. Files are never modified in place,
+    so a link never changes the base policy.
+    """
+    dst.parent.mkdir(parents=True, exist_ok=True)
+    if dst.exists():
+        dst.unlink()
+    try:
+        os.link(src, dst)
+    except OSError:
+        part = dst.with_name(dst.name + ".part")
+        shutil.copyfile(src, part)
+        os.replace(part, dst)
+
+def _files(root: Path) -> List[Tuple[Path, str]]:
+    """List (path, name relative to root) for every file under a file or directory."""
+    if root.is_file():
+        return [(root, root.name)]
+    return [
+        (p, str(p.relative_to(root))) for p in sorted(root.rglob("*"))
+        if p.is_file() and not p.name.endswith(".part")
+    ]
+
+def create_policy(
+    name: str,
+    modelfile: Modelfile,
+    overwrite: bool = False,
+    progress: Optional[ProgressCallback] = None,
+) -> Dict:
+    """
+    Build a policy from a Modelfile and register it in the local store.
+
+    The base policy's files are linked into a staging directory, the
+    WEIGHTS are laid over them in order and CONFIG keys are written to
+    config.json. The staging directory then replaces the policy's weights
+    directory, so a failed create leaves any existing policy untouched.
+
+    :param name: 'backend:version' or a bare version of the base backend.
+    :param modelfile: Parsed Modelfile.
+    :param overwrite: Replace an existing policy of the same name.
+    :param progress: Optional callback receiving (file, completed, total)
+                     as each file is placed.
+    :return: Store record of the created policy.
+    :raises ValueError: If the base is not pulled, the name is invalid or
+                        a WEIGHTS path does not exist.
+    :raises PolicyExistsError: If the policy exists and overwrite is False.
+    """
+    base_name, base_version = parse_versioned(modelfile.base)
+    base = store.get_policy(base_name, base_version)
+    if base is None:
+        raise ValueError(f"Base policy {base_name}:{base_version} is not pulled")
+
+    backend, version = resolve_target(name, modelfile.base)
+    if backend != base_name:
+        raise ValueError(f"{backend}:{version} must use the base policy's backend '{base_name}'")
+    if (backend, version) == (base_name, base_version):
+        raise ValueError(f"{backend}:{version} is the base policy")
+    if store.get_policy(backend, version) and not overwrite:
+        raise PolicyExistsError(f"Policy {backend}:{version} already exists")
+
+    for path in modelfile.weights:
+        if not path.exists():
+            raise ValueError(f"Weights not found: {path}")
+
+    # Later sources override earlier ones file by file
+    files: Dict[str, Path] = {}
+    for root in [Path(base["path"])] + modelfile.weights:
+        for path, rel in _files(root):
+            files[rel] = path
+
+    dst = policy_dir(backend, version)
+    staging = dst.with_name(f".{version}.creating")
+
+    with lock_ref(backend, version):
+        shutil.rmtree(staging, ignore_errors=True)
+        try:
+            for rel, path in files.items():
+                size = path.stat().st_size
+                if progress:
+                    progress(rel, 0, size)
+                _place(path, staging / rel)
+                if progress:
+                    progress(rel, size, size)
+
+            if modelfile.config:
+                config_path = staging / "config.json"
+                config = json.loads(config_path.read_text()) if config_path.exists() else {}
+                config.update(modelfile.config)
+                # Write a new file so a linked base config.json is left alone
+                tmp = config_path.with_name("config.json.part")
+                tmp.write_text(json.dumps(config, indent=2))
+                os.replace(tmp, config_path)
+
+            if dst.exists():
+                shutil.rmtree(dst)
+            os.replace(staging, dst)
+        except BaseException:
+            shutil.rmtree(staging, ignore_errors=True)
+            raise
+
+        store.add_policy(
+            name=backend,
+            image=base["image"],
+            version=version,
+            path=str(dst),
+            repo=base.get("repo"),
+            revision=base.get("revision"),
+            ref=base.get("ref"),
+        )
+        store.set_policy_annotations(backend, version, {"base": f"{base_name}:{base_version}", **modelfile.labels})
+
+    log.info(f"Created {backend}:{version} from {base_name}:{base_version} ({len(files)} files)")
+    return store.get_policy(backend, version)
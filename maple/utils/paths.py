@@ -1,6 +1,74 @@
+"""
+Filesystem layout of the MAPLE home directory.
+
+Everything MAPLE stores locally (config, credentials, database, weights
+and locks) lives under one root. Path helpers resolve it through
+maple_home() at call time rather than at import, so the root can be moved
+with set_maple_home(), for example to point tests at a temporary directory.
+
+Precedence: set_maple_home() (the CLI's --root) > $MAPLE_HOME > ~/.maple.
+
+Policy weights can live apart from the rest, for example on a shared
+read-only volume: $MAPLE_MODELS_DIR (set by 'maple serve --models-at')
+replaces <home>/models.
+"""
+
+import os
 from pathlib import Path
+from contextlib import contextmanager
+from typing import Iterable, Iterator, Optional
+
+# Root set with set_maple_home(), or None for the default
+_home_override: Optional[Path] = None
 
-VLA_HOME = Path.home() / ".maple"
+def maple_home() -> Path:
+    """
+    Get the MAPLE home directory.
+    
+    :return: Path to the root of all local MAPLE state. Created on first
+             write by the code that stores there.
+    """
+    if _home_override is not None:
+        return _home_override
+    env = os.environ.get("MAPLE_HOME")
+    return Path(env).expanduser() if env else Path.home() / ".maple"
+
+def set_maple_home(path: Optional[Path]) -> None:
+    """
+    Relocate the MAPLE home directory for the rest of the process.
+    
+    :param path: New root directory, or None to fall back to $MAPLE_HOME
+                 or ~/.maple.
+    """
+    global _home_override
+    _home_override = Path(path).expanduser() if path is not None else None
+
+@contextmanager
+def maple_home_at(path: Path) -> Iterator[Path]:
+    """
+    Temporarily use another MAPLE home, e.g. to read a second root's store.
+    
+    The previous root is restored when the block exits.
+    
+    :param path: Root directory to use inside the block.
+    :return: The root in use inside the block.
+    """
+    global _home_override
+    previous = _home_override
+    set_maple_home(path)
+    try:
+        yield maple_home()
+    finally:
+        _home_override = previous
+
+def models_dir() -> Path:
+    """
+    Get the directory holding policy weights.
+    
+    :return: $MAPLE_MODELS_DIR if set, otherwise 'models' in the MAPLE home directory.
+    """
+    env = os.environ.get("MAPLE_MODELS_DIR")
+    return Path(env).expanduser() if env else maple_home() / "models"
 
 def policy_dir(name: str, version: str) -> Path:
     """
@@ -13,4 +81,72 @@ def policy_dir(name: str, version: str) -> Path:
     :param version: Version identifier of the policy model.
     :return: Path object pointing to the model's version directory.
     """
-    return VLA_HOME / "models" / name / version
\ No newline at end of file
+    return models_dir() / name / version
+
+def dir_size(root: Path, include_partial: bool = True) -> int:
+    """
+    Sum the size of every file under a directory.
+    
+    Files removed while the tree is being walked are skipped.
+    
+    :param root: Directory to measure.
+    :param include_partial: If False, skip unfinished '.part' downloads.
+    :return: Total size in bytes, or 0 if the directory does not exist.
+    """
+    if not root.is_dir():
+        return 0
+    total = 0
+    for path in root.rglob("*"):
+        if not include_partial and path.name.endswith(".part"):
+            continue
+        try:
+            if path.is_file():
+                total += path.stat().st_size
+        except OSError:
+            continue
+    return total
+
+def unique_size(roots: Iterable[Path], include_partial: bool = False) -> int:
+    """
+    Sum the size of the files under several directories, counting each file once.
+    
+    Policies built with 'maple create' hard-link their base's weights, so
+    the same file can appear under several versions. Files are told apart
+    by device and inode.
+    
+    :param roots: Directories to measure. Missing ones are skipped.
+    :param include_partial: If True, count unfinished '.part' downloads too.
+    :return: Total size in bytes of the distinct files.
+    """
+    seen = set()
+    total = 0
+    for root in roots:
+        if not root.is_dir():
+            continue
+        for path in root.rglob("*"):
+            if not include_partial and path.name.endswith(".part"):
+                continue
+            try:
+                if not path.is_file():
+                    continue
+                st = path.stat()
+            except OSError:
+                continue
+            if (st.st_dev, st.st_ino) in seen:
+                continue
+            seen.add((st.st_dev, st.st_ino))
+            total += st.st_size
+    return total
+
+def policy_size(name: str, version: str) -> int:
+    """
+    Get the size of a pulled policy's weights.
+    
+    This is the one definition of a policy's size: every complete file in
+    its weights directory. Partial downloads are not counted.
+    
+    :param name: Name of the policy model.
+    :param version: Version identifier of the policy model.
+    :return: Size in bytes, or 0 if the weights are not on disk.
+    """
+    return dir_size(policy_dir(name, version), include_partial=False)
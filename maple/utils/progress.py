@@ -0,0 +1,83 @@
+"""
+Progress reporting utilities.
+
+This module provides helpers for rendering long-running transfers such as
+model pulls. Transfer rates are smoothed with an exponential moving average
+so the displayed speed and ETA don't jump around with every chunk.
+"""
+
+import time
+from typing import Optional
+
+class TransferRate:
+    """
+    Exponential moving average of a transfer rate in bytes per second.
+    
+    Each update computes the instantaneous rate since the previous update
+    and blends it into the running average. A higher alpha reacts faster
+    to changes; a lower alpha gives a steadier reading.
+    """
+
+    def __init__(self, alpha: float = 0.3):
+        """
+        Initialize the rate estimator.
+        
+        :param alpha: Smoothing factor in (0, 1]. Weight given to the newest sample.
+        """
+        self.alpha = alpha
+        self.rate = 0.0
+        self._last_time: Optional[float] = None
+        self._last_completed = 0
+
+    def update(self, completed: int, now: Optional[float] = None) -> float:
+        """
+        Record the total bytes completed so far.
+        
+        :param completed: Cumulative bytes transferred.
+        :param now: Optional timestamp (monotonic seconds). Defaults to the current time.
+        :return: Smoothed rate in bytes per second.
+        """
+        now = time.monotonic() if now is None else now
+
+        if self._last_time is not None:
+            elapsed = now - self._last_time
+            if elapsed > 0:
+                instant = max(0, completed - self._last_completed) / elapsed
+                if self.rate == 0:
+                    self.rate = instant
+                else:
+                    self.rate = self.alpha * instant + (1 - self.alpha) * self.rate
+
+        self._last_time = now
+        self._last_completed = completed
+        return self.rate
+
+    def eta(self, remaining: int) -> Optional[float]:
+        """
+        Estimate seconds until the remaining bytes are transferred.
+        
+        :param remaining: Bytes left to transfer.
+        :return: Estimated seconds, or None if the rate is not known yet.
+        """
+        if remaining <= 0:
+            return 0.0
+        if self.rate <= 0:
+            return None
+        return remaining / self.rate
+
+def format_eta(seconds: Optional[float]) -> str:
+    """
+    Format an ETA for display.
+    
+    :param seconds: Seconds remaining, or None if unknown.
+    :return: String such as '1h02m', '3m15s', '42s', or '--' if unknown.
+    """
+    if seconds is None:
+        return "--"
+
+    seconds = int(seconds)
+    if seconds >= 3600:
+        return f"{seconds // 3600}h{(seconds % 3600) // 60:02d}m"
+    if seconds >= 60:
+        return f"{seconds // 60}m{seconds % 60:02d}s"
+    return f"{seconds}s"
@@ -0,0 +1,144 @@
+"""
+Recorded inference trajectories.
+
+'maple run --record run.jsonl' appends one JSON object per line to a
+trajectory file, so a run can be reproduced or replayed against another
+model later:
+
+- header: the policy, its pulled revision and weights digest, the
+  environment, task and seed, and when recording started
+- step: per inference, the step number, a timestamp, the instruction,
+  a reference to the input image and the action the policy returned
+- result: the episode result reported by the daemon
+
+Input images are PNG files in a '<name>.frames' directory next to the
+trajectory, one subdirectory per run. Image references are relative to
+the trajectory's directory so the file and its frames can be moved
+together. Several runs can be appended to the same file; each starts
+with its own header.
+"""
+
+import json
+import time
+from pathlib import Path
+from typing import Any, Dict, Iterator, List, Optional
+
+# Format version written to every header
+RECORD_VERSION = 1
+
+def frames_dir(record: Path) -> Path:
+    """
+    Get the directory holding the input images of a trajectory file.
+
+    :param record: Trajectory file (e.g., run.jsonl).
+    :return: Sibling directory such as run.frames.
+    """
+    return record.with_name(f"{record.stem}.frames")
+
+class Recorder:
+    """
+    Appends a run to a trajectory file.
+    """
+
+    def __init__(self, record: Path):
+        """
+        Open a trajectory file for appending.
+
+        :param record: Trajectory file, created with its parent directories
+                       if missing.
+        """
+        self.path = record
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        self._file = open(self.path, "a")
+
+    def _write(self, entry: Dict[str, Any]) -> None:
+        """
+        Write one line and flush it, so an interrupted run keeps its steps.
+
+        :param entry: Object to write.
+        """
+        self._file.write(json.dumps(entry) + "\n")
+        self._file.flush()
+
+    def header(self, **fields: Any) -> None:
+        """
+        Start a run.
+
+        :param fields: Run metadata (policy, revision, digest, env, task, seed, ...).
+        """
+        self._write({"type": "header", "version": RECORD_VERSION, "recorded_at": time.time(), **fields})
+
+    def step(self, step: int, timestamp: float, instruction: str, image: Optional[str], action: List[float]) -> None:
+        """
+        Record one inference.
+
+        :param step: Step number within the episode.
+        :param timestamp: Time the action was returned.
+        :param instruction: Instruction given to the policy.
+        :param image: Absolute path of the input image, or None if not saved.
+        :param action: Action returned by the policy.
+        """
+        if image is not None:
+            image = self._relative(Path(image))
+        self._write({
+            "type": "step",
+            "step": step,
+            "timestamp": timestamp,
+            "instruction": instruction,
+            "image": image,
+            "action": action,
+        })
+
+    def result(self, result: Dict[str, Any]) -> None:
+        """
+        Finish a run.
+
+        :param result: Episode result from the daemon.
+        """
+        self._write({"type": "result", **result})
+
+    def _relative(self, image: Path) -> str:
+        """
+        Express an image path relative to the trajectory's directory when possible.
+
+        :param image: Absolute image path.
+        :return: Relative POSIX path, or the path unchanged if it lies elsewhere.
+        """
+        try:
+            return image.resolve().relative_to(self.path.parent.resolve()).as_posix()
+        except ValueError:
+            return str(image)
+
+    def close(self) -> None:
+        """Close the trajectory file."""
+        self._file.close()
+
+    def __enter__(self) -> "Recorder":
+        return self
+
+    def __exit__(self, *exc: Any) -> None:
+        self.close()
+
+def read_record(record: Path) -> Iterator[Dict[str, Any]]:
+    """
+    Read the entries of a trajectory file.
+
+    Image references are resolved to absolute paths.
+
+    :param record: Trajectory file.
+    :return: Iterator of header, step and result entries in file order.
+    :raises ValueError: If a line is not a trajectory entry.
+    """
+    with open(record) as f:
+        for number, line in enumerate(f, 1):
+            if not line.strip():
+                continue
+            try:
+                entry = json.loads(line)
+            except json.JSONDecodeError as e:
+                raise ValueError(f"{record}:{number}: invalid JSON ({e})")
+            if not isinstance(entry, dict) or entry.get("type") not in ("header", "step", "result"):
+                raise ValueError(f"{record}:{number}: not a trajectory entry")
+            if entry["type"] == "step" and entry.get("image"):
+                entry["image"] = str((record.parent / entry["image"]).resolve())
+            yield entry
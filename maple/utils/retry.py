@@ -8,6 +8,8 @@ API calls, and other potentially flaky operations.
 Key features:
 - Configurable retry attempts with exponential backoff
 - Exception filtering (retry only specific exception types)
+- Optional predicate to skip retries for non-transient errors
+- Random jitter to avoid synchronized retries
 - Maximum delay cap to prevent excessive waiting
 - Decorator and functional retry patterns
 - Dataclass-based configuration for reusability
@@ -20,6 +22,8 @@ reasonable retry intervals for transient failures.
 """
 
 import time
+import random
+import logging
 from functools import wraps 
 from dataclasses import dataclass
 from typing import Callable, Optional, Tuple, Type, TypeVar
@@ -49,6 +53,10 @@ class RetryConfig:
     """Maximum delay cap in seconds."""
     exceptions: Tuple[Type[Exception], ...] = (Exception,)
     """Tuple of exception types to catch and retry on."""
+    jitter: float = 0.0
+    """Maximum random fraction of the delay added to each wait (0.5 = up to +50%)."""
+    should_retry: Optional[Callable[[Exception], bool]] = None
+    """Optional predicate; caught exceptions for which it returns False are raised immediately."""
 
 def retry(
     max_attempts: int = 3, 
@@ -57,6 +65,9 @@ def retry(
     max_delay: float = 30.0,
     exceptions: Tuple[Type[Exception], ...] = (Exception,),
     config: Optional[RetryConfig] = None, 
+    jitter: float = 0.0,
+    should_retry: Optional[Callable[[Exception], bool]] = None,
+    log_level: int = logging.WARNING,
 ) -> Callable[[Callable[..., T]], Callable[..., T]]:
     """
     Decorator that adds automatic retry logic with exponential backoff.
@@ -77,6 +88,10 @@ def retry(
                       exceptions trigger retries; others propagate immediately.
                       Default is (Exception,) which catches all exceptions.
     :param config: Optional RetryConfig object. If provided, overrides all other parameters.
+    :param jitter: Maximum random fraction of the delay added to each wait.
+    :param should_retry: Optional predicate. Exceptions for which it returns False
+                        are raised immediately without retrying.
+    :param log_level: Logging level for retry attempt messages.
     :return: A decorator function that wraps the target function with retry logic.
     """
     if config:
@@ -85,6 +100,8 @@ def retry(
         backoff = config.backoff
         max_delay = config.max_delay
         exceptions = config.exceptions
+        jitter = config.jitter
+        should_retry = config.should_retry
 
     def decorator(func: Callable[..., T]) -> Callable[..., T]:
         @wraps(func)
@@ -97,13 +114,19 @@ def retry(
                     return func(*args, **kwargs)
                 except exceptions as e:
                     last_exception = e
+
+                    # Non-transient failure - don't waste attempts on it
+                    if should_retry is not None and not should_retry(e):
+                        raise
                     
                     if attempt < max_attempts:
-                        log.warning(
+                        wait = min(current_delay * (1 + random.uniform(0, jitter)), max_delay)
+                        log.log(
+                            log_level,
                             f"{func.__name__} failed (attempt {attempt}/{max_attempts}): {e}. "
-                            f"Retrying in {current_delay:.1f}s..."
+                            f"Retrying in {wait:.1f}s..."
                         )
-                        time.sleep(current_delay)
+                        time.sleep(wait)
                         current_delay = min(current_delay * backoff, max_delay)
                     else:
                         log.error(
@@ -123,6 +146,10 @@ def retry_call(
     delay: float = 1.0,
     backoff: float = 2.0,
     exceptions: Tuple[Type[Exception], ...] = (Exception,),
+    max_delay: float = 30.0,
+    jitter: float = 0.0,
+    should_retry: Optional[Callable[[Exception], bool]] = None,
+    log_level: int = logging.WARNING,
 ) -> T:
     """
     Functional interface for retrying a callable with arguments.
@@ -145,6 +172,11 @@ def retry_call(
     :param backoff: Exponential backoff multiplier applied after each failure.
     :param exceptions: Tuple of exception types to catch and retry on.
                       Default is (Exception,) which catches all exceptions.
+    :param max_delay: Maximum delay cap in seconds.
+    :param jitter: Maximum random fraction of the delay added to each wait.
+    :param should_retry: Optional predicate. Exceptions for which it returns False
+                        are raised immediately without retrying.
+    :param log_level: Logging level for retry attempt messages.
     :return: The return value from successful function execution.
     """
     kwargs = kwargs or {}
@@ -153,7 +185,11 @@ def retry_call(
         max_attempts=max_attempts,
         delay=delay,
         backoff=backoff,
+        max_delay=max_delay,
         exceptions=exceptions,
+        jitter=jitter,
+        should_retry=should_retry,
+        log_level=log_level,
     )
     def _call():
         return func(*args, **kwargs)
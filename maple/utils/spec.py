@@ -19,3 +19,41 @@ def parse_versioned(spec: str) -> tuple[str, str]:
             raise ValueError(f"Invalid spec: {spec}")
         return name, ver
     return spec, "latest"
+
+def parse_pinned(spec: str) -> tuple[str, str | None]:
+    """
+    Split an optional revision pin off a specification.
+    
+    'openvla:7b@a1b2c3d' pins the policy to the commit it was pulled at.
+    Without a pin the spec is returned unchanged.
+    
+    :param spec: Specification string in format 'name[:version][@revision]'.
+    :return: Tuple of (spec without the pin, revision or None).
+    """
+    spec = spec.strip()
+    if "@" not in spec:
+        return spec, None
+    base, revision = spec.rsplit("@", 1)
+    base, revision = base.strip(), revision.strip()
+    if not base or not revision:
+        raise ValueError(f"Invalid spec: {spec}")
+    return base, revision
+
+# Prefixes that mark a spec as a direct HuggingFace repo reference
+HF_PREFIXES = ("https://huggingface.co/", "huggingface.co/", "hf.co/")
+
+def parse_hf_spec(spec: str) -> str | None:
+    """
+    Extract a HuggingFace repo ID from a spec such as 'hf.co/openvla/openvla-7b'.
+    
+    :param spec: Policy specification string.
+    :return: Repo ID in 'org/name' form, or None if the spec is not a HuggingFace reference.
+    """
+    spec = spec.strip()
+    for prefix in HF_PREFIXES:
+        if spec.startswith(prefix):
+            repo_id = spec[len(prefix):].strip("/")
+            if repo_id.count("/") != 1 or not all(repo_id.split("/")):
+                raise ValueError(f"Invalid HuggingFace spec: {spec} (expected hf.co/<org>/<repo>)")
+            return repo_id
+    return None
@@ -0,0 +1,85 @@
+"""
+Console output settings shared by the CLI and the daemon.
+
+Commands print results with rich. Informational output - progress bars,
+spinners, banners and hints - goes through this module instead, so the
+global --quiet flag can turn it off in one place. Errors, results and
+machine-readable output (--json, --output json) are always printed.
+
+The setting is exported as MAPLE_QUIET so a daemon started in the
+background by a quiet command is quiet too.
+
+Color is decided here as well. Rich only emits ANSI codes when stdout is
+a terminal and NO_COLOR (https://no-color.org) is unset; the global
+--no-color flag sets NO_COLOR for this process and its children. Tables
+measure cells by their visible width, so columns stay aligned with or
+without color. Commands print through rich's global console, never a
+Console of their own, so they all follow the same setting.
+"""
+
+import os
+from typing import Any
+
+from rich import print, get_console, reconfigure
+from rich.console import Console
+
+# Environment variable carrying --quiet to child processes
+QUIET_ENV = "MAPLE_QUIET"
+
+# Standard environment variable disabling color in every tool that honors it
+NO_COLOR_ENV = "NO_COLOR"
+
+# Styles shared by every command, so a value looks the same everywhere
+NAME_STYLE = "cyan"
+SIZE_STYLE = "green"
+
+def set_quiet(quiet: bool) -> None:
+    """
+    Turn informational output off or on for this process and its children.
+
+    :param quiet: True to suppress progress, banners and hints.
+    """
+    if quiet:
+        os.environ[QUIET_ENV] = "1"
+    else:
+        os.environ.pop(QUIET_ENV, None)
+
+def is_quiet() -> bool:
+    """
+    Check whether informational output is suppressed.
+
+    :return: True if --quiet was given to this or a parent process.
+    """
+    return os.environ.get(QUIET_ENV, "") not in ("", "0")
+
+def info(*objects: Any, **kwargs: Any) -> None:
+    """
+    Print informational output unless quiet.
+
+    :param objects: Objects to print, with rich markup.
+    :param kwargs: Extra arguments for rich's print.
+    """
+    if not is_quiet():
+        print(*objects, **kwargs)
+
+def progress_console() -> Console:
+    """
+    Get the console progress bars and spinners render to.
+
+    :return: A silent console when quiet, otherwise rich's global console.
+    """
+    return Console(quiet=True) if is_quiet() else get_console()
+
+def set_color(enabled: bool) -> None:
+    """
+    Turn colored output off or on for this process and its children.
+
+    :param enabled: False to print without ANSI color codes.
+    """
+    if enabled:
+        os.environ.pop(NO_COLOR_ENV, None)
+    else:
+        os.environ[NO_COLOR_ENV] = "1"
+    # Replaces the settings of the global console in place, so consoles
+    # fetched with get_console() before this call follow it too
+    reconfigure(no_color=not enabled)
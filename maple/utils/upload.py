@@ -0,0 +1,182 @@
+"""
+HuggingFace upload utilities.
+
+This module publishes a local policy directory to a model repo on the
+registry. Files are compared with what the repo already holds, by LFS
+SHA-256 for large files and git blob ID for the rest, so unchanged weights
+are not uploaded again. Changed files are
+uploaded one at a time, reporting progress per file, and then committed
+together so the repo never shows a partial push.
+
+Key features:
+- Skip files the repo already has with identical contents
+- Per-file progress callbacks (file, completed_bytes, total_bytes)
+- A single commit per push, returning its hash
+- Registry endpoint, TLS and proxy options via a Hub
+"""
+
+import requests
+from pathlib import Path
+from contextlib import contextmanager
+from typing import Dict, Iterator, List, Optional, Tuple
+
+from maple.utils.hub import Hub
+from maple.utils.logging import get_logger
+from maple.utils.download import ProgressCallback, git_blob_id, matches_digest, sibling_digest
+
+log = get_logger("upload")
+
+def remote_blobs(
+    repo_id: str,
+    token: Optional[str] = None,
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> Dict[str, Tuple[int, Optional[str]]]:
+    """
+    Get the size and content digest of every file in a repo.
+
+    Only the primary endpoint is asked, since that is where pushes go.
+
+    :param repo_id: HuggingFace repo ID (e.g., 'myorg/openvla-kitchen').
+    :param token: Access token.
+    :param revision: Optional branch. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: Mapping of file name to (size, digest) as returned by sibling_digest.
+             Empty if the repo does not exist yet.
+    """
+    hub = hub or Hub()
+    resp = requests.get(
+        hub.api_url(repo_id, revision),
+        params={"blobs": "true"},
+        headers={"Authorization": f"Bearer {token}"} if token else {},
+        timeout=30,
+        **hub.request_kwargs(),
+    )
+    if resp.status_code == 404:
+        return {}
+    resp.raise_for_status()
+    return {s["rfilename"]: sibling_digest(s) for s in resp.json().get("siblings", [])}
+
+def plan_upload(
+    src: Path,
+    repo_id: str,
+    token: Optional[str] = None,
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+) -> List[Tuple[str, Path, bool]]:
+    """
+    List the files of a policy directory and whether the repo has them.
+
+    :param src: Policy weights directory.
+    :param repo_id: Target repo ID.
+    :param token: Access token.
+    :param revision: Optional branch. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :return: List of (name in repo, local path, already uploaded) tuples.
+    """
+    remote = remote_blobs(repo_id, token=token, revision=revision, hub=hub)
+    plan = []
+    for path in sorted(src.rglob("*")):
+        if not path.is_file() or path.name.endswith(".part"):
+            continue
+        name = path.relative_to(src).as_posix()
+        size, digest = remote.get(name, (None, None))
+        uploaded = digest is not None and size == path.stat().st_size and matches_digest(path, digest)
+        plan.append((name, path, uploaded))
+    return plan
+
+@contextmanager
+def _hub_session(hub: Hub) -> Iterator[None]:
+    """
+    Apply the hub's TLS and proxy options to huggingface_hub's HTTP session.
+
+    huggingface_hub keeps one session factory for the whole process and
+    has no per-client session, so the options only hold inside the block
+    and the default factory is put back on exit, even on errors. MAPLE
+    installs no other factory, so the default is the previous one.
+
+    :param hub: Registry connection settings.
+    """
+    from huggingface_hub import configure_http_backend
+
+    def session() -> requests.Session:
+        s = requests.Session()
+        s.verify = hub.verify
+        s.proxies.update(hub.request_kwargs().get("proxies", {}))
+        return s
+
+    configure_http_backend(backend_factory=session)
+    try:
+        yield
+    finally:
+        configure_http_backend()
+
+def upload_repo(
+    src: Path,
+    repo_id: str,
+    token: str,
+    revision: Optional[str] = None,
+    hub: Optional[Hub] = None,
+    progress: Optional[ProgressCallback] = None,
+    message: Optional[str] = None,
+) -> Dict:
+    """
+    Upload a policy directory to a model repo.
+
+    The repo is created if it does not exist. Files the repo already has
+    are skipped. The rest are uploaded one by one and then committed
+    together.
+
+    :param src: Policy weights directory.
+    :param repo_id: Target repo ID (e.g., 'myorg/openvla-kitchen').
+    :param token: Access token with write access to the repo.
+    :param revision: Optional branch. Defaults to the main branch.
+    :param hub: Registry connection settings. Defaults to the HuggingFace Hub.
+    :param progress: Optional callback receiving (file, completed, total).
+    :param message: Commit message.
+    :return: Dictionary with repo, commit hash (None if nothing changed),
+            and uploaded and skipped file names.
+    """
+    from huggingface_hub import HfApi, CommitOperationAdd
+
+    hub = hub or Hub()
+    with _hub_session(hub):
+        api = HfApi(endpoint=hub.endpoint, token=token)
+        api.create_repo(repo_id, repo_type="model", exist_ok=True)
+
+        plan = plan_upload(src, repo_id, token=token, revision=revision, hub=hub)
+        operations = []
+        skipped = []
+        for name, path, uploaded in plan:
+            size = path.stat().st_size
+            if uploaded:
+                skipped.append(name)
+                if progress:
+                    progress(name, size, size)
+                continue
+
+            if progress:
+                progress(name, 0, size)
+            operation = CommitOperationAdd(path_in_repo=name, path_or_fileobj=str(path))
+            api.preupload_lfs_files(repo_id, [operation], revision=revision)
+            operations.append(operation)
+            if progress:
+                progress(name, size, size)
+
+        commit = None
+        if operations:
+            info = api.create_commit(
+                repo_id,
+                operations=operations,
+                commit_message=message or f"Upload {len(operations)} file(s) with MAPLE",
+                revision=revision,
+            )
+            commit = info.oid
+            log.info(f"Pushed {len(operations)} file(s) to {repo_id} at {commit}")
+
+    return {
+        "repo": repo_id,
+        "commit": commit,
+        "uploaded": [op.path_in_repo for op in operations],
+        "skipped": skipped,
+    }
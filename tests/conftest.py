@@ -272,6 +272,49 @@ def sample_action():
 # Database Fixtures
 # =============================================================================
 
+@pytest.fixture
+def maple_home(temp_dir):
+    """Point the MAPLE home directory at a temporary directory.
+    
+    Yields:
+        Path: Temporary MAPLE home, restored to the default after the test.
+    """
+    from maple.utils.paths import set_maple_home
+    
+    set_maple_home(temp_dir)
+    yield temp_dir
+    set_maple_home(None)
+
+
+@pytest.fixture
+def pulled_policy(test_db, maple_home):
+    """Factory registering pulled policies with weights in the MAPLE home.
+    
+    Each call creates the policy's weights directory holding a model.bin
+    of size bytes, or hard-linked from the policy named by link_from
+    ('name:version'), and adds the policy to the store. Extra keyword
+    arguments (repo, revision, ...) are passed to store.add_policy.
+    
+    Yields:
+        Callable: pulled_policy(name, version, size=100, link_from=None, **fields),
+        returning the weights directory.
+    """
+    from maple.state import store
+    from maple.utils.paths import policy_dir
+    
+    def make(name, version, size=100, link_from=None, image="img", **fields):
+        weights = policy_dir(name, version)
+        weights.mkdir(parents=True)
+        if link_from is not None:
+            os.link(policy_dir(*link_from.split(":", 1)) / "model.bin", weights / "model.bin")
+        else:
+            (weights / "model.bin").write_bytes(b"x" * size)
+        store.add_policy(name, image, version, str(weights), **fields)
+        return weights
+    
+    yield make
+
+
 @pytest.fixture
 def test_db(temp_dir, monkeypatch):
     """Create a test SQLite database.
@@ -99,7 +99,438 @@ class TestDaemonEndpoints:
                 routes = [route.path for route in daemon.app.routes]
                 
                 assert "/stop" in routes
+    
+    def test_readyz_reports_failing_check(self, mock_docker_client):
+        """Test /healthz always answers and /readyz returns 503 with a reason."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                assert client.get("/healthz").json() == {"status": "ok"}
+                
+                with patch.object(daemon, "_check_ready", return_value={"storage": None, "docker": "not reachable"}):
+                    response = client.get("/readyz")
+                assert response.status_code == 503
+                assert response.json()["reason"] == "docker: not reachable"
+                
+                with patch.object(daemon, "_check_ready", return_value={"storage": None, "docker": None}):
+                    response = client.get("/readyz")
+                assert response.status_code == 200
+                assert response.json() == {"ready": True, "checks": {"storage": "ok", "docker": "ok"}}
+    
+    def test_metrics_endpoint_gated(self, mock_docker_client):
+        """Test /metrics only exists with metrics enabled and counts requests."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                plain = VLADaemon(port=8000, device="cpu")
+                assert "/metrics" not in [route.path for route in plain.app.routes]
+                
+                daemon = VLADaemon(port=8000, device="cpu", metrics=True)
+                client = TestClient(daemon.app)
+                client.get("/status")
+                response = client.get("/metrics")
+                
+                assert response.headers["content-type"].startswith("text/plain")
+                assert 'maple_http_requests_total{method="GET",path="/status",status="200"} 1' in response.text
+                assert "maple_policies_loaded 0" in response.text
+    
+    def test_act_batch_size_and_order(self, mock_docker_client):
+        """Test /policy/act_batch returns actions in order and rejects oversized batches."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu", max_batch=2)
+                backend = MagicMock()
+                backend.act_batch.side_effect = lambda handle, payloads, instructions, model_kwargs: [
+                    [float(i)] for i in range(len(payloads))
+                ]
+                daemon._policy_backends["openvla"] = backend
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock())
+                client = TestClient(daemon.app)
+                
+                with patch("maple.server.daemon.decode_base64_image"):
+                    ok = client.post("/policy/act_batch", json={
+                        "policy_id": "openvla-7b-abc",
+                        "image": ["a", "b"],
+                        "instruction": ["pick", "place"],
+                    })
+                    too_big = client.post("/policy/act_batch", json={
+                        "policy_id": "openvla-7b-abc",
+                        "image": ["a", "b", "c"],
+                        "instruction": ["pick", "place", "push"],
+                    })
+                    mismatched = client.post("/policy/act_batch", json={
+                        "policy_id": "openvla-7b-abc",
+                        "image": ["a", "b"],
+                        "instruction": ["pick"],
+                    })
+                
+                assert ok.status_code == 200
+                assert ok.json() == {"actions": [[0.0], [1.0]]}
+                assert too_big.status_code == 400
+                assert "maximum of 2" in too_big.json()["detail"]
+                assert mismatched.status_code == 400
+                assert backend.act_batch.call_count == 1
+
+    def test_act_rate_limit(self, mock_docker_client):
+        """Test act requests over --act-rate get 429 with Retry-After, per address, and /status stays open."""
+        from fastapi.testclient import TestClient
+
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+
+                daemon = VLADaemon(port=8000, device="cpu", act_rate=0.5, act_burst=1)
+                backend = MagicMock()
+                backend.act.return_value = [0.0]
+                daemon._policy_backends["openvla"] = backend
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock())
+                client = TestClient(daemon.app)
+                body = {"policy_id": "openvla-7b-abc", "image": "a", "instruction": "pick"}
+
+                with patch("maple.server.daemon.decode_base64_image"):
+                    first = client.post("/policy/act", json=body)
+                    limited = client.post("/policy/act", json=body)
+                    # An unchecked API key must not buy a fresh bucket
+                    keyed = client.post("/policy/act", json=body, headers={"X-API-Key": "other"})
+                status = client.get("/status")
+
+                assert first.status_code == 200
+                assert limited.status_code == 429
+                assert limited.headers["retry-after"] == "2"
+                assert keyed.status_code == 429
+                assert status.status_code == 200
+                assert backend.act.call_count == 1
+
+    def test_run_shares_act_slots(self, mock_docker_client):
+        """Test episode inference waits for an act slot and fails with 503 when the queue is full."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu", max_concurrent_act=1, max_act_queue=0)
+                policy, env = MagicMock(), MagicMock()
+                env.setup.return_value = {"instruction": "pick"}
+                env.reset.return_value = {"observation": {}}
+                daemon._policy_backends["openvla"] = policy
+                daemon._env_backends["libero"] = env
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock())
+                daemon._env_handles["libero-xyz"] = ("libero", MagicMock())
+                client = TestClient(daemon.app)
+                body = {"policy_id": "openvla-7b-abc", "env_id": "libero-xyz", "task": "libero_10/0", "max_steps": 1}
+                
+                with patch("maple.server.daemon.has_adapter", return_value=True), \
+                     patch("maple.server.daemon.get_adapter"):
+                    with daemon._act_queue.slot():
+                        response = client.post("/run", json=body)
+                
+                assert response.status_code == 503
+                assert response.headers["retry-after"] == "1"
+                policy.act.assert_not_called()
+                assert daemon._act_queue.stats()["running"] == 0
+    
+    def test_run_record_refuses_multi_input_policies(self, mock_docker_client, temp_dir):
+        """Test recording stops at the first step when the policy input is more than one image."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                policy, env = MagicMock(), MagicMock()
+                env.setup.return_value = {"instruction": "pick"}
+                env.reset.return_value = {"observation": {}}
+                daemon._policy_backends["pi0"] = policy
+                daemon._env_backends["libero"] = env
+                daemon._policy_handles["pi0-base-abc"] = ("pi0", MagicMock())
+                daemon._env_handles["libero-xyz"] = ("libero", MagicMock())
+                client = TestClient(daemon.app)
+                adapter = MagicMock()
+                adapter.transform_obs.return_value = {"observation/image": [[0]], "observation/state": [0.0]}
+                body = {"policy_id": "pi0-base-abc", "env_id": "libero-xyz", "task": "libero_10/0",
+                        "max_steps": 1, "record_dir": str(temp_dir)}
+                
+                with patch("maple.server.daemon.has_adapter", return_value=True), \
+                     patch("maple.server.daemon.get_adapter", return_value=adapter):
+                    response = client.post("/run", json=body)
+                
+                assert response.status_code == 400
+                assert "observation/state" in response.json()["detail"]
+                policy.act.assert_not_called()
+    
+    def test_act_queue_full(self, mock_docker_client):
+        """Test act requests get 503 when every slot is taken and the queue is full, and /ps reports the queue."""
+        from fastapi.testclient import TestClient
+        from maple.server.admission import QueueFull
+
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+
+                daemon = VLADaemon(port=8000, device="cpu", max_concurrent_act=2, max_act_queue=0)
+                backend = MagicMock()
+                daemon._policy_backends["openvla"] = backend
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock())
+                client = TestClient(daemon.app)
+                body = {"policy_id": "openvla-7b-abc", "image": "a", "instruction": "pick"}
 
+                with patch("maple.server.daemon.decode_base64_image"):
+                    with patch.object(daemon._act_queue, "slot", side_effect=QueueFull("Act queue is full")):
+                        full = client.post("/policy/act", json=body)
+                queue = client.get("/ps").json()["act_queue"]
+
+                assert full.status_code == 503
+                assert full.headers["retry-after"] == "1"
+                assert backend.act.call_count == 0
+                assert queue == {"running": 0, "queued": 0, "max_concurrent": 2, "max_queue": 0}
+                assert daemon._policy_active.get("openvla-7b-abc", 0) == 0
+
+    def test_idle_unload_races_with_act(self, mock_docker_client):
+        """Test an idle unload backs off from a policy acquired after its scan, and a stopped policy is refused."""
+        from fastapi import HTTPException
+
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+
+                daemon = VLADaemon(port=8000, device="cpu")
+                backend = MagicMock()
+                daemon._policy_backends["openvla"] = backend
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock(container_id=None))
+                daemon._policy_active["openvla-7b-abc"] = 0
+                daemon._policy_expiry["openvla-7b-abc"] = 0.0
+
+                daemon._acquire_policy("openvla-7b-abc")
+                assert daemon._detach_policy("openvla-7b-abc", idle_only=True) is None
+
+                daemon._release_policy("openvla-7b-abc", 0.0)
+                daemon._unload_idle_policies()
+
+                backend.stop.assert_called_once()
+                with pytest.raises(HTTPException):
+                    daemon._acquire_policy("openvla-7b-abc")
+                assert "openvla-7b-abc" not in daemon._policy_active
+
+    def test_create_refuses_remote_weights(self, mock_docker_client):
+        """Test remote clients cannot name WEIGHTS paths on the daemon host."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                with patch("maple.server.daemon.create_policy") as mock_create:
+                    response = client.post("/policy/create", json={
+                        "name": "openvla:leak", "base": "openvla:7b", "weights": ["~/.maple/auth.json"],
+                    })
+                
+                assert response.status_code == 403
+                mock_create.assert_not_called()
+    
+    def test_push_needs_token_from_remote_clients(self, mock_docker_client, temp_dir):
+        """Test a remote push without an Authorization header is refused instead of using the stored login."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                # TestClient connects from the non-loopback address 'testclient'
+                client = TestClient(daemon.app)
+                body = {"spec": "openvla:kitchen-ft", "repo": "myorg/model"}
+                
+                with patch("maple.server.daemon.store.get_policy", return_value={"path": str(temp_dir)}), \
+                     patch("maple.server.daemon.get_token", return_value="hf_owner") as mock_token, \
+                     patch("maple.server.daemon.upload_repo", return_value={"commit": "abc"}) as mock_upload:
+                    remote = client.post("/policy/push", json=body)
+                    with patch("maple.server.daemon.is_local_request", return_value=True):
+                        local = client.post("/policy/push", json=body)
+                
+                assert remote.status_code == 401
+                assert local.status_code == 200
+                assert mock_token.call_count == 1
+                assert mock_upload.call_args[0][2] == "hf_owner"
+    
+    def test_policy_info_fills_action_dim(self, mock_docker_client):
+        """Test /policy/info adds the backend's action_dim when the container reports none."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                backend = MagicMock()
+                backend.get_info.return_value = {"image_size": 224}
+                backend.info.return_value = {"action_dim": 7}
+                daemon._policy_backends["openvla"] = backend
+                daemon._policy_handles["openvla-7b-abc"] = ("openvla", MagicMock())
+                client = TestClient(daemon.app)
+                
+                assert client.get("/policy/info/openvla-7b-abc").json() == {"image_size": 224, "action_dim": 7}
+                backend.get_info.return_value = {"action_dim": 8}
+                assert client.get("/policy/info/openvla-7b-abc").json() == {"action_dim": 8}
+    
+    def test_policy_stop_invalid_spec(self, mock_docker_client):
+        """Test /policy/stop answers 400, not 500, for a ref that is neither an ID nor a spec."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                response = client.post("/policy/stop/openvla:")
+                
+                assert response.status_code == 400
+    
+    def test_env_stop_by_name(self, mock_docker_client):
+        """Test /env/stop accepts an environment name and stops every instance of it."""
+        from fastapi.testclient import TestClient
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                backend = MagicMock()
+                daemon._env_backends["libero"] = backend
+                for env_id, name in [("libero-a1", "libero"), ("libero-b2", "libero"), ("simpler-c3", "simplerenv")]:
+                    daemon._env_handles[env_id] = (name, MagicMock(container_id=None))
+                client = TestClient(daemon.app)
+                
+                stopped = client.post("/env/stop/libero")
+                missing = client.post("/env/stop/libero")
+                
+                assert stopped.status_code == 200
+                assert stopped.json() == {"stopped": ["libero-a1", "libero-b2"]}
+                assert backend.stop.call_count == 2
+                assert list(daemon._env_handles) == ["simpler-c3"]
+                assert missing.status_code == 400
+    
+    def test_policy_list_ndjson(self, mock_docker_client, maple_home):
+        """Test policy list streams NDJSON when asked and JSON otherwise."""
+        import json
+        from fastapi.testclient import TestClient
+        
+        policies = [{"name": "openvla", "version": "7b"}, {"name": "smolvla", "version": "base"}]
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                with patch("maple.state.store.list_policies", return_value=policies):
+                    batched = client.get("/policy/list")
+                    streamed = client.get("/policy/list", headers={"Accept": "application/x-ndjson"})
+                
+                sized = [dict(p, size=0) for p in policies]
+                assert batched.json() == {"policies": sized, "total": 2}
+                assert streamed.headers["content-type"].startswith("application/x-ndjson")
+                assert [json.loads(line) for line in streamed.text.splitlines()] == sized
+    
+    def test_policy_list_size_matches_weights(self, mock_docker_client, maple_home):
+        """Test listed sizes count the complete files at the policy's path only."""
+        from fastapi.testclient import TestClient
+        
+        weights = maple_home / "elsewhere" / "openvla-7b"
+        weights.mkdir(parents=True)
+        (weights / "model.safetensors").write_bytes(b"x" * 64)
+        (weights / "config.json").write_bytes(b"x" * 16)
+        (weights / "shard.bin.part").write_bytes(b"x" * 8)
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                with patch("maple.state.store.list_policies", return_value=[{"name": "openvla", "version": "7b", "path": str(weights)}]):
+                    listed = client.get("/policy/list").json()["policies"][0]
+                
+                assert listed["size"] == 80
+    
+    def test_policy_list_pagination(self, mock_docker_client, maple_home):
+        """Test policy list pages are sorted by name and report the total."""
+        from fastapi.testclient import TestClient
+        
+        policies = [
+            {"name": "smolvla", "version": "base"},
+            {"name": "openvla", "version": "7b"},
+            {"name": "openpi", "version": "pi05_libero"},
+        ]
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                with patch("maple.state.store.list_policies", return_value=policies):
+                    full = client.get("/policy/list").json()
+                    page = client.get("/policy/list", params={"limit": 2, "offset": 1}).json()
+                
+                assert full == {"policies": [dict(p, size=0) for p in policies], "total": 3}
+                assert [p["name"] for p in page["policies"]] == ["openvla", "smolvla"]
+                assert page["total"] == 3
+
+    
+    def test_policy_list_grouped(self, mock_docker_client, maple_home):
+        """Test grouped listing counts weights linked between versions once."""
+        import os
+        from fastapi.testclient import TestClient
+        from maple.utils.paths import policy_dir
+        
+        base, derived = policy_dir("openvla", "7b"), policy_dir("openvla", "kitchen-ft")
+        base.mkdir(parents=True)
+        derived.mkdir(parents=True)
+        (base / "model.safetensors").write_bytes(b"x" * 64)
+        os.link(base / "model.safetensors", derived / "model.safetensors")
+        (derived / "config.json").write_bytes(b"x" * 16)
+        policies = [
+            {"name": "openvla", "version": "kitchen-ft", "path": str(derived)},
+            {"name": "openvla", "version": "7b", "path": str(base)},
+        ]
+        
+        with patch("maple.state.store.clear_containers"):
+            with patch("maple.utils.cleanup.register_cleanup_handler"):
+                from maple.server.daemon import VLADaemon
+                
+                daemon = VLADaemon(port=8000, device="cpu")
+                client = TestClient(daemon.app)
+                
+                with patch("maple.state.store.list_policies", return_value=policies):
+                    grouped = client.get("/policy/list", params={"group": "true"}).json()
+                
+                assert grouped["total"] == 2
+                [group] = grouped["groups"]
+                assert group["name"] == "openvla"
+                assert [p["version"] for p in group["versions"]] == ["7b", "kitchen-ft"]
+                assert [p["size"] for p in group["versions"]] == [64, 80]
+                assert group["size"] == 80
 
 @pytest.mark.integration
 class TestRequestModels:
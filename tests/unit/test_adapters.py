@@ -50,6 +50,18 @@ class TestAdapterRegistry:
         adapters = list_adapters()
         assert isinstance(adapters, dict)
         assert "openvla:libero" in adapters
+    
+    @pytest.mark.unit
+    def test_supported_envs(self):
+        """Test listing the environments a policy has adapters for."""
+        from maple.adapters import supported_envs, has_adapter
+        
+        assert supported_envs("openvla") == ["libero"]
+        assert set(supported_envs("openpi:pi05_libero")) == {"libero", "alohasim", "bridge", "fractal"}
+        assert supported_envs("unknown_policy") == []
+        
+        assert has_adapter("gr00tn15", "bridge")
+        assert not has_adapter("openvla", "bridge")
 
 
 class TestAdapterBase:
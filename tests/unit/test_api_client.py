@@ -0,0 +1,210 @@
+"""
+Unit tests for maple.api.client module.
+
+Tests cover:
+- Request URLs and parameters
+- Mapping connection failures and error statuses to exceptions
+- Connect timeouts and retrying GETs that could not connect
+- Streaming pull progress to a callback
+- Retryable errors when a pull stream drops, and non-retryable ones for create and push
+- Creating a policy with streamed progress
+- Passing a registry token through on push
+"""
+
+import json
+
+import pytest
+import requests
+from unittest.mock import MagicMock, patch
+
+from maple.api import Client, DaemonError, DaemonNotRunning, StreamInterrupted, PullInterrupted
+from maple.api.client import CONNECT_TIMEOUT_ENV
+
+
+def response(status_code=200, body=None, lines=None):
+    """Build a fake requests.Response."""
+    r = MagicMock(status_code=status_code)
+    r.json.return_value = body if body is not None else {}
+    r.iter_lines.return_value = [json.dumps(line).encode() for line in lines or []]
+    return r
+
+
+class TestClient:
+    """Tests for Client."""
+
+    @pytest.mark.unit
+    def test_list_policies_page(self):
+        """Test paging arguments are sent as limit/offset query parameters."""
+        client = Client("http://localhost:8000/")
+
+        with patch("requests.get", return_value=response(body={"policies": [], "total": 0})) as mock_get:
+            assert client.list_policies(limit=10, offset=20) == {"policies": [], "total": 0}
+
+        assert mock_get.call_args[0][0] == "http://localhost:8000/policy/list"
+        assert mock_get.call_args[1]["params"] == {"limit": 10, "offset": 20}
+
+    @pytest.mark.unit
+    def test_connection_error(self):
+        """Test an unreachable daemon raises DaemonNotRunning after retrying GETs."""
+        client = Client("http://localhost:59999")
+
+        with patch("requests.get", side_effect=requests.exceptions.ConnectionError()) as mock_get, \
+             patch("maple.api.client.time.sleep"):
+            with pytest.raises(DaemonNotRunning) as exc:
+                client.ps()
+
+        assert mock_get.call_count == 3
+        assert "maple serve" in str(exc.value)
+
+    @pytest.mark.unit
+    def test_post_not_retried(self):
+        """Test a POST that could not connect is not sent again."""
+        client = Client("http://localhost:59999")
+
+        with patch("requests.post", side_effect=requests.exceptions.ConnectionError()) as mock_post:
+            with pytest.raises(DaemonNotRunning):
+                client.stop_policy("openvla:7b")
+
+        assert mock_post.call_count == 1
+
+    @pytest.mark.unit
+    def test_connect_timeout(self, monkeypatch):
+        """Test the connect timeout comes from the environment and is reported when hit."""
+        monkeypatch.setenv(CONNECT_TIMEOUT_ENV, "10")
+        client = Client("http://gpu-box:8000")
+
+        with patch("requests.post", side_effect=requests.exceptions.ConnectTimeout()) as mock_post:
+            with pytest.raises(DaemonNotRunning) as exc:
+                client.run({"policy_id": "openvla-7b-a1b2c3d4"}, timeout=600)
+
+        assert mock_post.call_args[1]["timeout"] == (10.0, 600)
+        assert "did not answer within 10s" in str(exc.value)
+        assert "--connect-timeout" in str(exc.value)
+
+    @pytest.mark.unit
+    def test_error_status(self):
+        """Test an error response raises DaemonError with the daemon's detail."""
+        client = Client("http://localhost:8000")
+
+        with patch("requests.post", return_value=response(400, {"detail": "Policy 'openvla:7b' is not loaded"})):
+            with pytest.raises(DaemonError) as exc:
+                client.stop_policy("openvla:7b")
+
+        assert str(exc.value) == "Policy 'openvla:7b' is not loaded"
+        assert exc.value.status_code == 400
+
+    @pytest.mark.unit
+    def test_pull_streams_progress(self):
+        """Test pull passes each progress event to the callback and returns the result."""
+        client = Client("http://localhost:8000")
+        events = [
+            {"status": "downloading", "file": "a.bin", "completed": 5, "total": 10},
+            {"status": "downloading", "file": "a.bin", "completed": 10, "total": 10},
+            {"status": "success", "pulled": "openvla:7b"},
+        ]
+        seen = []
+
+        with patch("requests.post", return_value=response(lines=events)) as mock_post:
+            result = client.pull("openvla:7b", progress=seen.append, concurrency=2)
+
+        assert result == {"status": "success", "pulled": "openvla:7b"}
+        assert seen == events[:2]
+        assert mock_post.call_args[1]["json"] == {"spec": "openvla:7b", "concurrency": 2, "stream": True}
+
+    @pytest.mark.unit
+    def test_pull_error_event(self):
+        """Test a final error event raises DaemonError."""
+        client = Client("http://localhost:8000")
+
+        with patch("requests.post", return_value=response(lines=[{"status": "error", "error": "repo not found"}])):
+            with pytest.raises(DaemonError, match="repo not found"):
+                client.pull("openvla:7b", progress=lambda event: None)
+
+    @pytest.mark.unit
+    def test_pull_stream_drop_is_retryable(self):
+        """Test a connection drop mid-stream raises PullInterrupted after the events so far."""
+        client = Client("http://localhost:8000")
+
+        def lines():
+            yield json.dumps({"status": "downloading", "file": "a.bin", "completed": 5, "total": 10}).encode()
+            raise requests.exceptions.ChunkedEncodingError("connection broken")
+
+        r = response()
+        r.iter_lines.return_value = lines()
+        seen = []
+
+        with patch("requests.post", return_value=r):
+            with pytest.raises(PullInterrupted) as exc:
+                client.pull("openvla:7b", progress=seen.append)
+
+        assert exc.value.retryable
+        assert seen == [{"status": "downloading", "file": "a.bin", "completed": 5, "total": 10}]
+
+    @pytest.mark.unit
+    def test_create_streams_progress(self):
+        """Test create posts to /policy/create and passes each file event to the callback."""
+        client = Client("http://localhost:8000")
+        events = [
+            {"status": "creating", "file": "model.safetensors", "completed": 10, "total": 10},
+            {"status": "success", "created": "openvla:kitchen-ft", "policy": {}},
+        ]
+        seen = []
+
+        with patch("requests.post", return_value=response(lines=events)) as mock_post:
+            result = client.create({"name": "kitchen-ft", "base": "openvla:7b"}, progress=seen.append)
+
+        assert result["created"] == "openvla:kitchen-ft"
+        assert seen == events[:1]
+        assert mock_post.call_args[0][0] == "http://localhost:8000/policy/create"
+        assert mock_post.call_args[1]["json"] == {"name": "kitchen-ft", "base": "openvla:7b", "stream": True}
+
+    @pytest.mark.unit
+    def test_push_stream_drop_not_retryable(self):
+        """Test a push stream ending early raises StreamInterrupted, not PullInterrupted."""
+        client = Client("http://localhost:8000")
+        events = [{"status": "uploading", "file": "model.safetensors", "completed": 5, "total": 10}]
+
+        with patch("requests.post", return_value=response(lines=events)):
+            with pytest.raises(StreamInterrupted) as exc:
+                client.push({"spec": "openvla:kitchen-ft", "repo": "myorg/model"}, progress=lambda event: None)
+
+        assert not isinstance(exc.value, PullInterrupted)
+        assert not exc.value.retryable
+        assert "Push stream" in str(exc.value)
+
+    @pytest.mark.unit
+    def test_run_events_errors(self):
+        """Test run streams report a malformed line as DaemonError and a drop as StreamInterrupted."""
+        client = Client("http://localhost:8000")
+
+        r = response()
+        r.iter_lines.return_value = [b"not json"]
+        with patch("requests.post", return_value=r):
+            with pytest.raises(DaemonError, match="Malformed event from /run"):
+                list(client.run_events({"policy_id": "openvla-7b-a1b2c3d4"}))
+
+        def lines():
+            yield json.dumps({"step": 0, "action": [0.0], "timestamp": 1.0}).encode()
+            raise requests.exceptions.ChunkedEncodingError("connection broken")
+
+        r = response()
+        r.iter_lines.return_value = lines()
+        with patch("requests.post", return_value=r) as mock_post:
+            with pytest.raises(StreamInterrupted):
+                list(client.run_events({"policy_id": "openvla-7b-a1b2c3d4"}, timeout=60))
+
+        assert mock_post.call_args[1]["json"]["stream"] is True
+        assert mock_post.call_args[1]["timeout"][1] == 60
+
+    @pytest.mark.unit
+    def test_push_passes_token(self):
+        """Test push sends the registry token as the Authorization header."""
+        client = Client("http://localhost:8000")
+        body = {"pushed": "openvla:kitchen-ft", "repo": "myorg/model", "commit": "abc1234"}
+
+        with patch("requests.post", return_value=response(body=body)) as mock_post:
+            result = client.push({"spec": "openvla:kitchen-ft", "repo": "myorg/model"}, token="hf_x")
+
+        assert result["commit"] == "abc1234"
+        assert mock_post.call_args[0][0] == "http://localhost:8000/policy/push"
+        assert mock_post.call_args[1]["headers"] == {"Authorization": "Bearer hf_x"}
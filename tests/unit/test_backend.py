@@ -6,6 +6,7 @@ Tests cover:
 - PolicyHandle and EnvHandle dataclasses
 - Policy and environment registries
 - OpenVLA backend specifics
+- Pulling a new revision over an old one
 """
 
 import pytest
@@ -148,6 +149,68 @@ class TestPolicyRegistry:
         
         for key in POLICY_BACKENDS:
             assert key == key.lower(), f"Key '{key}' should be lowercase"
+    
+    @pytest.mark.unit
+    def test_infer_backend_known_repo(self):
+        """Test that a built-in repo resolves to its backend and version."""
+        from maple.backend.registry import infer_policy_backend
+        
+        assert infer_policy_backend("lerobot/smolvla_base") == ("smolvla", "base")
+    
+    @pytest.mark.unit
+    def test_infer_backend_from_repo_name(self):
+        """Test that unknown repos are matched on architecture hints."""
+        from maple.backend.registry import infer_policy_backend
+        
+        assert infer_policy_backend("someone/openvla-7b-finetuned") == ("openvla", "openvla-7b-finetuned")
+    
+    @pytest.mark.unit
+    def test_parse_hf_spec(self):
+        """Test extracting repo IDs from hf.co specs."""
+        from maple.utils.spec import parse_hf_spec
+        
+        assert parse_hf_spec("hf.co/openvla/openvla-7b") == "openvla/openvla-7b"
+        assert parse_hf_spec("https://huggingface.co/lerobot/smolvla_base") == "lerobot/smolvla_base"
+        assert parse_hf_spec("openvla:7b") is None
+        
+        with pytest.raises(ValueError):
+            parse_hf_spec("hf.co/openvla")
+    
+    @pytest.mark.unit
+    def test_parse_pinned(self):
+        """Test splitting a revision pin off a spec."""
+        from maple.utils.spec import parse_pinned
+        
+        assert parse_pinned("openvla:7b@a1b2c3d") == ("openvla:7b", "a1b2c3d")
+        assert parse_pinned("hf.co/openvla/openvla-7b@main") == ("hf.co/openvla/openvla-7b", "main")
+        assert parse_pinned("openvla:7b") == ("openvla:7b", None)
+        
+        with pytest.raises(ValueError):
+            parse_pinned("openvla:7b@")
+    
+    @pytest.mark.unit
+    def test_policy_catalog(self):
+        """Test that the catalog lists every built-in version with metadata."""
+        from maple.backend.registry import policy_catalog
+        
+        catalog = policy_catalog()
+        names = [entry["name"] for entry in catalog]
+        
+        assert "openvla:7b" in names
+        assert "smolvla:base" in names
+        assert all(entry["arch"] and entry["description"] for entry in catalog)
+    
+    @pytest.mark.unit
+    def test_hub_entry(self):
+        """Test describing hub repos as catalog entries."""
+        from maple.backend.registry import hub_entry
+        
+        entry = hub_entry("someone/openvla-7b-finetuned")
+        assert entry["name"] == "hf.co/someone/openvla-7b-finetuned"
+        assert entry["arch"] == "openvla"
+        assert entry["size"] == "7B"
+        
+        assert hub_entry("someone/resnet-50") is None
 
 
 class TestEnvRegistry:
@@ -194,6 +257,7 @@ class TestOpenVLABackend:
         assert "image" in info["inputs"]
         assert "instruction" in info["inputs"]
         assert "action" in info["outputs"]
+        assert info["action_dim"] == 7
     
     @pytest.mark.unit
     def test_hf_repos_versions(self, mock_docker_client):
@@ -282,3 +346,28 @@ class TestPolicyBackendBase:
         encoded = backend._encode_image(img)
         
         assert isinstance(encoded, str)
+    
+    @pytest.mark.unit
+    def test_pull_new_revision_swaps_in(self, mock_docker_client, temp_dir):
+        """Test a pull of another revision downloads beside the old weights and replaces them."""
+        from maple.backend.policy.openvla import OpenVLAPolicy
+        
+        dst = temp_dir / "openvla" / "7b"
+        dst.mkdir(parents=True)
+        (dst / "model.bin").write_bytes(b"old")
+        seen = {}
+        
+        def fake_download(**kwargs):
+            seen.update(kwargs)
+            (kwargs["dst"]).mkdir(parents=True, exist_ok=True)
+            (kwargs["dst"] / "model.bin").write_bytes(b"new")
+        
+        backend = OpenVLAPolicy()
+        with patch("maple.backend.policy.base.resolve_revision", return_value="b" * 40), \
+             patch("maple.backend.policy.base.download_repo", side_effect=fake_download):
+            backend.pull("7b", dst, pulled_revision="a" * 40)
+        
+        assert seen["dst"] == dst.with_name(".7b.pulling")
+        assert seen["reuse_from"] == dst
+        assert (dst / "model.bin").read_bytes() == b"new"
+        assert sorted(p.name for p in dst.parent.iterdir()) == ["7b"]
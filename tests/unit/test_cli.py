@@ -10,6 +10,7 @@ Tests cover:
 - Eval command
 """
 
+import os
 import pytest
 from unittest.mock import MagicMock, patch
 from typer.testing import CliRunner
@@ -65,6 +66,31 @@ class TestConfigCommands:
         assert result.exit_code == 0
         assert ".maple" in result.output or "config" in result.output
     
+    @pytest.mark.unit
+    def test_root_flag(self, temp_dir, monkeypatch):
+        """Test --root relocates the MAPLE home for the invocation."""
+        from maple.cmd.maple_cli import app
+        
+        # Restored after the test, since --root exports MAPLE_HOME
+        monkeypatch.setenv("MAPLE_HOME", str(temp_dir / "env"))
+        result = runner.invoke(app, ["--root", str(temp_dir / "flag"), "config", "path"])
+        
+        assert result.exit_code == 0
+        assert "flag" in result.output
+    
+    @pytest.mark.unit
+    def test_connect_timeout_flag(self, temp_config_dir, monkeypatch):
+        """Test --connect-timeout is passed to the daemon client."""
+        from maple.cmd.maple_cli import app
+        from maple.api.client import CONNECT_TIMEOUT_ENV
+        
+        # Restored after the test, since the flag exports it
+        monkeypatch.setenv(CONNECT_TIMEOUT_ENV, "3")
+        result = runner.invoke(app, ["--connect-timeout", "15", "config", "path"])
+        
+        assert result.exit_code == 0
+        assert os.environ[CONNECT_TIMEOUT_ENV] == "15.0"
+    
     @pytest.mark.unit
     def test_config_help(self):
         """Test config --help shows available subcommands."""
@@ -113,31 +139,76 @@ class TestListCommands:
     """Tests for list subcommands."""
     
     @pytest.mark.unit
-    def test_list_policy_no_daemon(self):
-        """Test list policy fails gracefully when daemon not running."""
+    def test_list_policy_without_daemon(self, pulled_policy):
+        """Test list policy reads local storage when no daemon is running."""
         from maple.cmd.maple_cli import app
         
+        pulled_policy("openvla", "7b", size=2048)
+        
         result = runner.invoke(app, ["list", "policy", "--port", "59999"])
         
-        # Should fail gracefully with helpful message
-        assert (
-            result.exit_code != 0 or 
-            "error" in result.output.lower() or 
-            "not running" in result.output.lower()
-        )
+        assert result.exit_code == 0
+        assert "openvla:7b" in result.output
+        assert "2.0 KB" in result.output
     
     @pytest.mark.unit
-    def test_list_env_no_daemon(self):
-        """Test list env fails gracefully when daemon not running."""
+    def test_list_env_remote_no_daemon(self):
+        """Test list env --remote fails gracefully when the daemon is not running."""
         from maple.cmd.maple_cli import app
         
-        result = runner.invoke(app, ["list", "env", "--port", "59999"])
+        with patch("maple.api.client.time.sleep"):
+            result = runner.invoke(app, ["list", "env", "--remote", "localhost", "--port", "59999"])
         
-        assert (
-            result.exit_code != 0 or 
-            "error" in result.output.lower() or 
-            "not running" in result.output.lower()
-        )
+        assert result.exit_code == 1
+        assert "not running" in result.output.lower()
+    
+    @pytest.mark.unit
+    def test_list_policy_page(self, mock_requests):
+        """Test list policy requests the right slice and reports the range."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {
+            "policies": [
+                {"name": "openvla", "version": "7b", "repo": "openvla/openvla-7b", "pulled_at": 0},
+                {"name": "smolvla", "version": "base", "repo": "lerobot/smolvla_base", "pulled_at": 0},
+            ],
+            "total": 5,
+        }
+        
+        result = runner.invoke(app, ["list", "policy", "--limit", "2", "--page", "2", "--remote", "gpu-box", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["get"].call_args[0][0] == "http://gpu-box:59999/policy/list"
+        assert mock_requests["get"].call_args[1]["params"] == {"limit": 2, "offset": 2}
+        assert "openvla:7b" in result.output
+        assert "Showing 3-4 of 5" in result.output
+    
+    @pytest.mark.unit
+    def test_list_env_table(self, mock_requests):
+        """Test list env shows name, size and pull time."""
+        import time
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {
+            "envs": [{"name": "libero", "image": "maplerobotics/libero:latest", "size": 5 * 1024 ** 3, "pulled_at": time.time() - 7200}]
+        }
+        
+        result = runner.invoke(app, ["list", "env", "--remote", "gpu-box", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "libero" in result.output
+        assert "5.0 GB" in result.output
+        assert "2 hours ago" in result.output
+    
+    @pytest.mark.unit
+    def test_list_env_empty(self, test_db):
+        """Test list env explains how to pull when nothing is installed."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["list", "env"])
+        
+        assert result.exit_code == 0
+        assert "No environments installed" in result.output
     
     @pytest.mark.unit
     def test_list_help(self):
@@ -206,6 +277,128 @@ class TestEvalCommand:
         assert result.exit_code != 0
 
 
+class TestRunCommand:
+    """Tests for run command."""
+    
+    @pytest.mark.unit
+    def test_run_json_output(self, mock_requests):
+        """Test --output json prints one JSON object per step and nothing else."""
+        import json
+        from maple.cmd.maple_cli import app
+        
+        events = [
+            {"step": 0, "action": [0.1, 0.2, 0.3, 0.0, 0.0, 0.0, 1.0], "timestamp": 1.0},
+            {"step": 1, "action": [0.0, 0.1, 0.2, 0.0, 0.0, 0.0, 1.0], "timestamp": 1.1},
+            {"status": "success", "run_id": "run-a1b2c3d4", "success": True, "steps": 1},
+        ]
+        mock_requests["post"].return_value.iter_lines.return_value = [json.dumps(e).encode() for e in events]
+        
+        result = runner.invoke(app, ["run", "openvla-7b-a1b2c3d4", "libero-x1y2z3w4", "--task", "libero_10/0",
+                                     "--output", "json", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert [json.loads(line) for line in result.output.splitlines()] == events
+        assert mock_requests["post"].call_args.kwargs["json"]["stream"] is True
+    
+    @pytest.mark.unit
+    def test_run_json_action_dim_mismatch(self, mock_requests):
+        """Test --output json stops before printing an action that does not match the policy's action_dim."""
+        import json
+        from maple.cmd.maple_cli import app
+        
+        events = [
+            {"step": 0, "action": [0.1, 0.2, 0.3, 0.0, 0.0, 0.0, 1.0], "timestamp": 1.0},
+            {"step": 1, "action": [0.0, 0.1], "timestamp": 1.1},
+        ]
+        mock_requests["get"].return_value.json.return_value = {"action_dim": 7}
+        mock_requests["post"].return_value.iter_lines.return_value = [json.dumps(e).encode() for e in events]
+        
+        result = runner.invoke(app, ["run", "openvla-7b-a1b2c3d4", "libero-x1y2z3w4", "--task", "libero_10/0",
+                                     "--output", "json", "--port", "59999"])
+        
+        assert result.exit_code == 1
+        assert json.loads(result.output.splitlines()[0]) == events[0]
+        assert "action_dim is 7" in result.output
+    
+    @pytest.mark.unit
+    def test_run_record(self, mock_requests, temp_dir):
+        """Test --record appends a header, each step and the result, with frames next to the file."""
+        import json
+        from maple.cmd.maple_cli import app
+        from maple.utils.record import read_record
+        
+        record = temp_dir / "run.jsonl"
+        frame = temp_dir.resolve() / "run.frames" / "run-a1b2c3d4" / "step_00000.png"
+        events = [
+            {"step": 0, "action": [0.1, 1.0], "timestamp": 1.0, "instruction": "pick", "image": str(frame)},
+            {"status": "success", "run_id": "run-a1b2c3d4", "success": True, "steps": 0},
+        ]
+        mock_requests["get"].return_value.json.return_value = {"policies": [
+            {"policy_id": "openvla-7b-a1b2c3d4", "backend": "openvla", "version": "7b"},
+        ]}
+        mock_requests["post"].return_value.iter_lines.return_value = [json.dumps(e).encode() for e in events]
+        
+        with patch("maple.cmd.maple_cli.store.get_policy", return_value={"repo": "openvla/openvla-7b", "revision": "abc123"}), \
+             patch("maple.cmd.maple_cli.build_lockfile", return_value={"digest": "sha256:00ff"}):
+            result = runner.invoke(app, ["run", "openvla-7b-a1b2c3d4", "libero-x1y2z3w4", "--task", "libero_10/0",
+                                         "--record", str(record), "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args.kwargs["json"]["record_dir"] == str(temp_dir.resolve() / "run.frames")
+        header, step, final = list(read_record(record))
+        assert (header["type"], header["policy"], header["revision"], header["digest"]) == ("header", "openvla:7b", "abc123", "sha256:00ff")
+        assert (step["instruction"], step["action"], step["image"]) == ("pick", [0.1, 1.0], str(frame.resolve()))
+        assert "run.frames/" in record.read_text()
+        assert final["run_id"] == "run-a1b2c3d4"
+    
+    @pytest.mark.unit
+    def test_run_image_single_shot(self, mock_requests, temp_dir):
+        """Test --image sends one resized image to /policy/act and prints the action."""
+        import io
+        import base64
+        from PIL import Image
+        from maple.cmd.maple_cli import app
+        
+        photo = temp_dir / "photo.png"
+        Image.new("RGB", (64, 48), color=(255, 0, 0)).save(photo)
+        mock_requests["get"].return_value.json.return_value = {"image_size": [224, 224]}
+        mock_requests["post"].return_value.json.return_value = {"action": [0.1, 0.2, 1.0]}
+        
+        result = runner.invoke(app, ["run", "openvla-7b-a1b2c3d4", "--image", str(photo),
+                                     "--instruction", "pick up the red block", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "[0.1, 0.2, 1.0]" in result.output
+        assert mock_requests["post"].call_args[0][0].endswith("/policy/act")
+        payload = mock_requests["post"].call_args.kwargs["json"]
+        assert payload["instruction"] == "pick up the red block"
+        assert Image.open(io.BytesIO(base64.b64decode(payload["image"]))).size == (224, 224)
+    
+    @pytest.mark.unit
+    def test_run_image_not_an_image(self, mock_requests, temp_dir):
+        """Test --image rejects files that do not decode as images."""
+        from maple.cmd.maple_cli import app
+        
+        bogus = temp_dir / "notes.png"
+        bogus.write_text("not an image")
+        
+        result = runner.invoke(app, ["run", "openvla-7b-a1b2c3d4", "--image", str(bogus), "--instruction", "go"])
+        
+        assert result.exit_code == 1
+        assert "Cannot read image" in result.output
+        mock_requests["post"].assert_not_called()
+    
+    @pytest.mark.unit
+    def test_run_unknown_output(self):
+        """Test an unknown output format is rejected."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["run", "p", "e", "--task", "t", "--output", "yaml"])
+        
+        assert result.exit_code == 1
+        assert "Unknown output format" in result.output
+
+
 class TestStopCommand:
     """Tests for stop command."""
     
@@ -217,3 +410,1032 @@ class TestStopCommand:
         result = runner.invoke(app, ["stop", "--help"])
         
         assert result.exit_code == 0
+    
+    @pytest.mark.unit
+    def test_stop_policy(self, mock_requests):
+        """Test stop with a policy unloads it instead of the daemon."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.json.return_value = {
+            "stopped": ["openvla-7b-a1b2c3d4"],
+            "freed_memory": 15 * 1024 ** 3,
+        }
+        
+        result = runner.invoke(app, ["stop", "openvla:7b", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args[0][0].endswith("/policy/stop/openvla:7b")
+        assert "openvla-7b-a1b2c3d4" in result.output
+        assert "15.0 GB" in result.output
+    
+    @pytest.mark.unit
+    def test_stop_policy_not_loaded(self, mock_requests):
+        """Test stop fails when the policy is not loaded."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.status_code = 400
+        mock_requests["post"].return_value.json.return_value = {"detail": "Policy 'openvla:7b' is not loaded"}
+        
+        result = runner.invoke(app, ["stop", "openvla:7b", "--port", "59999"])
+        
+        assert result.exit_code == 1
+        assert "not loaded" in result.output
+    
+    @pytest.mark.unit
+    def test_stop_daemon_from_pid_file(self):
+        """Test stop without a policy signals the daemon recorded in the PID file."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.maple_cli.running_daemon", return_value={"pid": 4242, "port": 8000}), \
+             patch("maple.cmd.maple_cli.stop_process", return_value=True) as stop_process:
+            result = runner.invoke(app, ["stop", "--timeout", "5s"])
+        
+        assert result.exit_code == 0
+        stop_process.assert_called_once_with(4242, timeout=5.0)
+        assert "stopped cleanly" in result.output
+    
+    @pytest.mark.unit
+    def test_stop_daemon_killed(self):
+        """Test stop reports when the daemon had to be killed."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.maple_cli.running_daemon", return_value={"pid": 4242, "port": 8000}), \
+             patch("maple.cmd.maple_cli.stop_process", return_value=False):
+            result = runner.invoke(app, ["stop"])
+        
+        assert result.exit_code == 0
+        assert "killed" in result.output
+    
+    @pytest.mark.unit
+    def test_stop_no_daemon(self):
+        """Test stop without a running daemon exits cleanly."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.maple_cli.running_daemon", return_value=None):
+            result = runner.invoke(app, ["stop", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "No MAPLE daemon running" in result.output
+
+
+class TestRemoveCommand:
+    """Tests for remove command."""
+    
+    @pytest.mark.unit
+    def test_remove_multiple_policies(self, mock_requests, tmp_path):
+        """Test removing several policies continues past missing ones."""
+        from maple.cmd.maple_cli import app
+        
+        def fake_get_policy(name, version):
+            if name == "missing":
+                return None
+            return {"image": f"maplerobotics/{name}:latest", "path": str(tmp_path / name / version)}
+        
+        with patch("maple.cmd.cli.rmv.get_policy", side_effect=fake_get_policy), \
+             patch("maple.cmd.cli.rmv.remove_policy", return_value=True) as mock_remove, \
+             patch("maple.cmd.cli.rmv.list_policies", return_value=[]), \
+             patch("maple.cmd.cli.rmv.docker") as mock_docker:
+            result = runner.invoke(
+                app, ["remove", "policy", "openvla:7b", "missing:v1", "smolvla:base", "--port", "59999"]
+            )
+        
+        assert result.exit_code == 1
+        assert mock_remove.call_count == 2
+        assert mock_docker.from_env.call_count == 1
+        assert mock_docker.from_env.return_value.images.remove.call_count == 2
+        assert "missing:v1" in result.output
+    
+    @pytest.mark.unit
+    def test_remove_stops_exact_spec(self, mock_requests, tmp_path):
+        """Test removing a policy asks the daemon to stop exactly that name:version."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.json.return_value = {"stopped": ["openvla-7b-a1b2c3d4"]}
+        with patch("maple.cmd.cli.rmv.get_policy", return_value={"image": "img", "path": str(tmp_path / "7b")}), \
+             patch("maple.cmd.cli.rmv.remove_policy", return_value=True), \
+             patch("maple.cmd.cli.rmv.list_policies", return_value=[]), \
+             patch("maple.cmd.cli.rmv.docker"):
+            result = runner.invoke(app, ["remove", "policy", "openvla:7b", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args[0][0].endswith("/policy/stop/openvla:7b")
+        assert "openvla-7b-a1b2c3d4" in result.output
+    
+    @pytest.mark.unit
+    def test_remove_all_versions_of_name(self, mock_requests, tmp_path):
+        """Test a bare name removes every pulled version after --force."""
+        from maple.cmd.maple_cli import app
+        
+        pulled = [
+            {"name": "openvla", "version": "7b", "image": "maplerobotics/openvla:latest", "path": str(tmp_path / "7b")},
+            {"name": "openvla", "version": "latest", "image": "maplerobotics/openvla:latest", "path": str(tmp_path / "latest")},
+            {"name": "smolvla", "version": "base", "image": "maplerobotics/smolvla:latest", "path": str(tmp_path / "base")},
+        ]
+        
+        with patch("maple.cmd.cli.rmv.list_policies", return_value=pulled), \
+             patch("maple.cmd.cli.rmv.get_policy", side_effect=lambda n, v: next(p for p in pulled if (p["name"], p["version"]) == (n, v))), \
+             patch("maple.cmd.cli.rmv.remove_policy", return_value=True) as mock_remove, \
+             patch("maple.cmd.cli.rmv.docker"):
+            result = runner.invoke(app, ["remove", "policy", "openvla", "-f", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert [c.args for c in mock_remove.call_args_list] == [("openvla", "7b"), ("openvla", "latest")]
+    
+    @pytest.mark.unit
+    def test_remove_all_cancelled(self, mock_requests):
+        """Test --all asks for confirmation and removes nothing when declined."""
+        from maple.cmd.maple_cli import app
+        
+        pulled = [{"name": "openvla", "version": "7b", "image": "maplerobotics/openvla:latest", "path": "/tmp/x"}]
+        
+        with patch("maple.cmd.cli.rmv.list_policies", return_value=pulled), \
+             patch("maple.cmd.cli.rmv.remove_policy") as mock_remove:
+            result = runner.invoke(app, ["remove", "policy", "--all"], input="n\n")
+        
+        assert result.exit_code == 0
+        assert "Cancelled" in result.output
+        mock_remove.assert_not_called()
+
+    
+    @pytest.mark.unit
+    def test_remove_env_in_use(self, mock_requests):
+        """Test removing a served environment is refused without --force."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"serving": {"envs": ["libero-a1b2c3d4"]}}
+        
+        with patch("maple.cmd.cli.rmv.get_env", return_value={"name": "libero", "image": "maplerobotics/libero:latest"}), \
+             patch("maple.cmd.cli.rmv.remove_env") as mock_remove:
+            result = runner.invoke(app, ["remove", "env", "libero", "--port", "59999"])
+        
+        assert result.exit_code == 1
+        assert "libero-a1b2c3d4" in result.output
+        mock_remove.assert_not_called()
+    
+    @pytest.mark.unit
+    def test_remove_env_force_reports_freed(self, mock_requests):
+        """Test --force stops served containers and reports freed space."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"serving": {"envs": ["libero-a1b2c3d4"]}}
+        
+        with patch("maple.cmd.cli.rmv.get_env", return_value={"name": "libero", "image": "maplerobotics/libero:latest"}), \
+             patch("maple.cmd.cli.rmv.remove_env", return_value=True), \
+             patch("maple.cmd.cli.rmv.docker") as mock_docker:
+            mock_docker.from_env.return_value.images.get.return_value.attrs = {"Size": 2 * 1024 ** 3}
+            result = runner.invoke(app, ["remove", "env", "libero", "--force", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args[0][0].endswith("/env/stop/libero-a1b2c3d4")
+        assert "2.0 GB" in result.output
+
+
+class TestEnvCommand:
+    """Tests for env run and env stop."""
+    
+    @pytest.mark.unit
+    def test_env_run(self, mock_requests):
+        """Test env run starts a single instance on the requested port."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.json.return_value = {
+            "served": "libero", "num_envs": 1, "env_ids": ["libero-a1b2c3d4"], "ports": [9100],
+        }
+        
+        result = runner.invoke(app, ["env", "run", "libero", "-p", "9100", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args[0][0].endswith("/env/serve")
+        assert mock_requests["post"].call_args[1]["json"]["num_envs"] == 1
+        assert mock_requests["post"].call_args[1]["json"]["host_port"] == 9100
+        assert "libero-a1b2c3d4 on port 9100" in result.output
+    
+    @pytest.mark.unit
+    def test_env_stop_by_name(self, mock_requests):
+        """Test env stop with a name reports every instance stopped."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.json.return_value = {"stopped": ["libero-a1b2c3d4", "libero-e5f6a7b8"]}
+        
+        result = runner.invoke(app, ["env", "stop", "libero", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["post"].call_args[0][0].endswith("/env/stop/libero")
+        assert "libero-a1b2c3d4" in result.output
+        assert "libero-e5f6a7b8" in result.output
+
+
+class TestPsCommand:
+    """Tests for ps command."""
+    
+    @pytest.mark.unit
+    def test_ps_policy_no_daemon(self):
+        """Test ps policy prints a friendly message when daemon is down."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["ps", "policy", "--port", "59999"])
+        
+        assert "not running" in result.output.lower()
+        assert "Traceback" not in result.output
+    
+    @pytest.mark.unit
+    def test_ps_policy_table(self, mock_requests):
+        """Test ps policy renders loaded policies."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {
+            "policies": [{
+                "policy_id": "openvla-7b-a1b2c3d4",
+                "backend": "openvla",
+                "version": "7b",
+                "device": "cuda:0",
+                "status": "healthy",
+                "keep_alive_remaining": 240.0,
+            }]
+        }
+        
+        result = runner.invoke(app, ["ps", "policy", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "openvla:7b" in result.output
+        assert "gpu" in result.output
+        assert "4 minutes from now" in result.output
+    
+    @pytest.mark.unit
+    def test_ps_policy_act_queue(self, mock_requests):
+        """Test ps policy shows how many act requests are running and queued."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {
+            "policies": [{
+                "policy_id": "openvla-7b-a1b2c3d4",
+                "backend": "openvla",
+                "version": "7b",
+                "device": "cuda:0",
+                "keep_alive_remaining": None,
+            }],
+            "act_queue": {"running": 1, "queued": 3, "max_concurrent": 1, "max_queue": 16},
+        }
+        
+        result = runner.invoke(app, ["ps", "policy", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "1/1 running, 3/16 queued" in result.output
+    
+    @pytest.mark.unit
+    def test_ps_policy_watch(self, mock_requests):
+        """Test --watch polls the daemon until interrupted and exits cleanly."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"policies": []}
+        
+        with patch("maple.cmd.cli.ps.time.sleep", side_effect=[None, KeyboardInterrupt]) as sleep:
+            result = runner.invoke(app, ["ps", "policy", "--watch", "--interval", "0.5", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert mock_requests["get"].call_count == 2
+        sleep.assert_called_with(0.5)
+        assert "No policies loaded" in result.output
+    
+    @pytest.mark.unit
+    def test_ps_env_table(self, mock_requests):
+        """Test ps env renders running environments with port, uptime and container."""
+        import time
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {
+            "policies": [],
+            "envs": [{
+                "env_id": "libero-a1b2c3d4",
+                "backend": "libero",
+                "port": 9100,
+                "container_id": "0123456789abcdef",
+                "status": "healthy",
+                "started_at": time.time() - 300,
+            }],
+        }
+        
+        result = runner.invoke(app, ["ps", "env", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "libero-a1b2c3d4" in result.output
+        assert "9100" in result.output
+        assert "Up 5 minutes" in result.output
+        assert "0123456789ab" in result.output
+        assert "0123456789abcdef" not in result.output
+    
+    @pytest.mark.unit
+    def test_ps_env_none_running(self, mock_requests):
+        """Test ps env handles a daemon with no environments, including older daemons."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"policies": []}
+        
+        result = runner.invoke(app, ["ps", "env", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "No environments running" in result.output
+    
+    @pytest.mark.unit
+    def test_ps_env_no_daemon(self):
+        """Test ps env prints a friendly message when daemon is down."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["ps", "env", "--port", "59999"])
+        
+        assert "not running" in result.output.lower()
+        assert "Traceback" not in result.output
+
+
+class TestCompletion:
+    """Tests for shell completion."""
+    
+    @pytest.mark.unit
+    def test_complete_policy_spec(self):
+        """Test pulled policies are suggested by prefix."""
+        from maple.cmd.cli.completion import complete_policy_spec
+        
+        pulled = [
+            {"name": "openvla", "version": "7b"},
+            {"name": "openpi", "version": "pi05_libero"},
+            {"name": "smolvla", "version": "base"},
+        ]
+        with patch("maple.state.store.list_policies", return_value=pulled):
+            assert complete_policy_spec("open") == ["openpi:pi05_libero", "openvla:7b"]
+            assert complete_policy_spec("smolvla:") == ["smolvla:base"]
+    
+    @pytest.mark.unit
+    def test_complete_policy_id_no_daemon(self, mock_requests):
+        """Test policy ID completion is empty when the daemon is down."""
+        import requests
+        from maple.cmd.cli.completion import complete_policy_id
+        
+        mock_requests["get"].side_effect = requests.exceptions.ConnectionError()
+        
+        assert complete_policy_id("") == []
+    
+    @pytest.mark.unit
+    def test_completion_script(self):
+        """Test completion prints a script for supported shells only."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["completion", "bash"])
+        assert result.exit_code == 0
+        assert "_MAPLE_COMPLETE" in result.output
+        
+        result = runner.invoke(app, ["completion", "powershell"])
+        assert result.exit_code == 1
+
+
+class TestDoctorGpuFit:
+    """Tests for the doctor GPU fit check."""
+    
+    @pytest.mark.unit
+    def test_gpu_fit_reports_large_policies(self):
+        """Test policies larger than the biggest GPU are flagged."""
+        from maple.cmd.cli.doctor import check_gpu_fit
+        
+        pulled = [{"name": "openvla", "version": "7b"}, {"name": "smolvla", "version": "base"}]
+        with patch("maple.state.store.list_policies", return_value=pulled):
+            small = check_gpu_fit(gpu_memory=[8 * 1024 ** 3])
+            large = check_gpu_fit(gpu_memory=[8 * 1024 ** 3, 24 * 1024 ** 3])
+        
+        assert not small.passed
+        assert "too large: openvla:7b" in small.details
+        assert "fits: smolvla:base" in small.details
+        assert large.passed
+    
+    @pytest.mark.unit
+    def test_gpu_fit_without_gpu(self):
+        """Test the check fails cleanly when GPU memory is unknown."""
+        from maple.cmd.cli.doctor import check_gpu_fit
+        
+        assert not check_gpu_fit(gpu_memory=[]).passed
+
+
+class TestLockCommand:
+    """Tests for the lock and verify-lock commands."""
+    
+    @pytest.fixture
+    def pulled(self, temp_dir):
+        """A pulled policy with two weight files."""
+        (temp_dir / "config.json").write_text("{}")
+        (temp_dir / "model.safetensors").write_bytes(b"weights")
+        (temp_dir / "partial.bin.part").write_bytes(b"x")
+        return {
+            "name": "openvla", "version": "7b", "path": str(temp_dir),
+            "repo": "openvla/openvla-7b", "revision": "abc123", "image": "maplerobotics/openvla:latest",
+        }
+    
+    @pytest.mark.unit
+    def test_lock_lists_files(self, pulled):
+        """Test the lockfile pins revision and every complete file."""
+        import json
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.lockfile.get_policy", return_value=pulled):
+            result = runner.invoke(app, ["lock", "openvla:7b"])
+        
+        assert result.exit_code == 0
+        lockfile = json.loads(result.output)
+        assert lockfile["policy"] == "openvla:7b"
+        assert lockfile["revision"] == "abc123"
+        assert [f["path"] for f in lockfile["files"]] == ["config.json", "model.safetensors"]
+        assert lockfile["files"][1]["size"] == 7
+    
+    @pytest.mark.unit
+    def test_lock_not_pulled(self):
+        """Test locking a policy that is not pulled fails."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.lockfile.get_policy", return_value=None):
+            result = runner.invoke(app, ["lock", "openvla:7b"])
+        
+        assert result.exit_code == 1
+    
+    @pytest.mark.unit
+    def test_verify_lock_detects_drift(self, pulled, tmp_path):
+        """Test verify-lock passes on a match and fails once a file changes."""
+        from pathlib import Path
+        from maple.cmd.maple_cli import app
+        
+        lock_path = tmp_path / "openvla.lock.json"
+        with patch("maple.cmd.cli.lockfile.get_policy", return_value=pulled):
+            assert runner.invoke(app, ["lock", "openvla:7b", "-o", str(lock_path)]).exit_code == 0
+            
+            result = runner.invoke(app, ["verify-lock", str(lock_path)])
+            assert result.exit_code == 0
+            
+            (Path(pulled["path"]) / "model.safetensors").write_bytes(b"tampered")
+            result = runner.invoke(app, ["verify-lock", str(lock_path)])
+        
+        assert result.exit_code == 1
+        assert "model.safetensors" in result.output
+
+
+class TestPullCommand:
+    """Tests for the pull command."""
+    
+    @pytest.mark.unit
+    def test_pull_dry_run(self, mock_requests):
+        """Test --dry-run reports the net download size without streaming a pull."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["post"].return_value.json.return_value = {
+            "policy": "openvla:7b",
+            "repo": "openvla/openvla-7b",
+            "files": [
+                {"file": "config.json", "size": 1024, "cached": True},
+                {"file": "model.safetensors", "size": 2 * 1024 ** 3, "cached": False},
+            ],
+            "total": 2 * 1024 ** 3 + 1024,
+            "cached": 1024,
+            "download": 2 * 1024 ** 3,
+        }
+        
+        result = runner.invoke(app, ["pull", "policy", "openvla:7b", "--dry-run"])
+        
+        assert result.exit_code == 0
+        assert "model.safetensors" in result.output
+        assert "To download: 2.0 GB" in result.output
+        
+        payload = mock_requests["post"].call_args.kwargs["json"]
+        assert payload["dry_run"] is True
+        assert "stream" not in payload
+    
+    @pytest.mark.unit
+    def test_pull_insecure_is_remembered(self, mock_requests, maple_home):
+        """Test --insecure warns, reaches the daemon and is saved for the registry."""
+        from maple.cmd.maple_cli import app
+        from maple.utils.config import load_config
+        
+        mock_requests["post"].return_value.json.return_value = {
+            "policy": "openvla:7b", "repo": "openvla/openvla-7b", "files": [],
+            "total": 0, "cached": 0, "download": 0,
+        }
+        
+        args = ["pull", "policy", "openvla:7b", "--dry-run", "--registry", "registry.internal"]
+        result = runner.invoke(app, args + ["--insecure"])
+        assert result.exit_code == 0
+        assert "TLS certificate verification is disabled" in result.output
+        
+        # A later pull reuses the saved preference without the flag
+        result = runner.invoke(app, args)
+        payload = mock_requests["post"].call_args.kwargs["json"]
+        assert payload["registry"] == "registry.internal"
+        assert payload["insecure"] is True
+        assert load_config().policy.registries == {"registry.internal": {"insecure": True}}
+        
+        load_config(maple_home / "missing.yaml")
+    
+    @pytest.mark.unit
+    def test_pull_reattaches_after_drop(self):
+        """Test a dropped progress stream is re-attached and the pull finishes."""
+        from maple.api import PullInterrupted
+        from maple.cmd.cli.pull import stream_pull_events
+        
+        attempts = []
+        
+        def pull_events(payload):
+            attempts.append(payload)
+            if len(attempts) == 1:
+                yield {"status": "downloading", "file": "a.bin", "completed": 5, "total": 10}
+                raise PullInterrupted("Lost connection to daemon")
+            yield {"status": "downloading", "file": "a.bin", "completed": 10, "total": 10}
+            yield {"status": "success", "pulled": "openvla:7b"}
+        
+        client = MagicMock()
+        client.pull_events.side_effect = pull_events
+        
+        with patch("maple.cmd.cli.pull.time.sleep"):
+            events = list(stream_pull_events(client, {"spec": "openvla:7b", "stream": True}))
+        
+        assert len(attempts) == 2
+        assert events[-1] == {"status": "success", "pulled": "openvla:7b"}
+    
+    @pytest.mark.unit
+    def test_pull_tag_checked_before_pull(self, mock_requests):
+        """Test a tag naming another backend fails before anything is pulled."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["pull", "policy", "openvla:7b", "--tag", "smolvla:latest"])
+        
+        assert result.exit_code == 1
+        assert "must use the pulled policy's backend 'openvla'" in result.output
+        mock_requests["post"].assert_not_called()
+    
+    @pytest.mark.unit
+    def test_pull_tag_after_success(self):
+        """Test each tag is created from the pulled policy once the pull succeeds."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.pull.store.get_policy", return_value=None), \
+             patch("maple.cmd.cli.pull.stream_pull_events", return_value=iter([{"status": "success", "pulled": "openvla:7b"}])), \
+             patch("maple.cmd.cli.pull.Client.create", return_value={"created": "openvla:latest"}) as mock_create:
+            result = runner.invoke(app, ["pull", "policy", "openvla:7b", "--tag", "latest"])
+        
+        assert result.exit_code == 0
+        assert "TAGGED openvla:7b as openvla:latest" in result.output
+        mock_create.assert_called_once_with({"name": "latest", "base": "openvla:7b", "overwrite": False})
+    
+    @pytest.mark.unit
+    def test_pull_tag_existing_needs_force(self, mock_requests):
+        """Test a tag naming an existing policy fails before the pull unless --force is given."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.pull.store.get_policy", return_value={"name": "openvla", "version": "latest"}) as mock_get:
+            result = runner.invoke(app, ["pull", "policy", "openvla:7b", "--tag", "latest"])
+        
+        assert result.exit_code == 1
+        assert "openvla:latest already exists" in result.output
+        mock_get.assert_called_once_with("openvla", "latest")
+        mock_requests["post"].assert_not_called()
+        
+        with patch("maple.cmd.cli.pull.store.get_policy", return_value={"name": "openvla", "version": "latest"}), \
+             patch("maple.cmd.cli.pull.stream_pull_events", return_value=iter([{"status": "success", "pulled": "openvla:7b"}])), \
+             patch("maple.cmd.cli.pull.Client.create", return_value={"created": "openvla:latest"}) as mock_create:
+            result = runner.invoke(app, ["pull", "policy", "openvla:7b", "--tag", "latest", "--force"])
+        
+        assert result.exit_code == 0
+        mock_create.assert_called_once_with({"name": "latest", "base": "openvla:7b", "overwrite": True})
+    
+    @pytest.mark.unit
+    def test_pull_tag_rejects_pulled_spec(self, mock_requests):
+        """Test a tag equal to the policy being pulled is rejected, even with --force."""
+        from maple.cmd.maple_cli import app
+        
+        for spec, tag in (("openvla:7b", "7b"), ("openvla", "openvla:latest")):
+            result = runner.invoke(app, ["pull", "policy", spec, "--tag", tag, "--force"])
+            
+            assert result.exit_code == 1
+            assert f"Tag {tag} is the policy being pulled" in result.output
+        mock_requests["post"].assert_not_called()
+
+
+class TestAnnotateCommand:
+    """Tests for the annotate command."""
+    
+    @pytest.mark.unit
+    def test_set_and_remove(self):
+        """Test new pairs are merged and --rm keys are dropped."""
+        from maple.cmd.maple_cli import app
+        
+        policy = {"name": "openvla", "version": "7b", "annotations": {"run": "42", "score": "0.7"}}
+        with patch("maple.cmd.cli.annotate.get_policy", return_value=policy), \
+             patch("maple.cmd.cli.annotate.set_policy_annotations") as mock_set:
+            result = runner.invoke(app, ["annotate", "openvla:7b", "dataset=libero=v2", "--rm", "score"])
+        
+        assert result.exit_code == 0
+        mock_set.assert_called_once_with("openvla", "7b", {"run": "42", "dataset": "libero=v2"})
+        assert "dataset=libero=v2" in result.output
+    
+    @pytest.mark.unit
+    def test_invalid_pair(self):
+        """Test arguments without '=' are rejected."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["annotate", "openvla:7b", "dataset"])
+        
+        assert result.exit_code == 1
+    
+    @pytest.mark.unit
+    def test_remove_unknown_key(self):
+        """Test removing a key that is not set fails without writing."""
+        from maple.cmd.maple_cli import app
+        
+        policy = {"name": "openvla", "version": "7b", "annotations": {}}
+        with patch("maple.cmd.cli.annotate.get_policy", return_value=policy), \
+             patch("maple.cmd.cli.annotate.set_policy_annotations") as mock_set:
+            result = runner.invoke(app, ["annotate", "openvla:7b", "--rm", "score"])
+        
+        assert result.exit_code == 1
+        mock_set.assert_not_called()
+
+
+class TestCopyCommand:
+    """Tests for the cp command."""
+    
+    @pytest.mark.unit
+    def test_copy_weights_skips_present(self, temp_dir):
+        """Test files already present with the same contents are not copied again."""
+        from maple.cmd.cli.copy import copy_weights
+        
+        src, dst = temp_dir / "src", temp_dir / "dst"
+        src.mkdir()
+        (src / "config.json").write_text("{}")
+        (src / "model.safetensors").write_bytes(b"weights")
+        dst.mkdir()
+        (dst / "config.json").write_text("{}")
+        
+        assert copy_weights(src, dst) == (1, 1, 7)
+        assert (dst / "model.safetensors").read_bytes() == b"weights"
+        assert not (dst / "model.safetensors.part").exists()
+    
+    @pytest.mark.unit
+    def test_copy_weights_replaces_same_size(self, temp_dir):
+        """Test a local file of the same size but different contents is copied over."""
+        from maple.cmd.cli.copy import copy_weights
+        
+        src, dst = temp_dir / "src", temp_dir / "dst"
+        src.mkdir()
+        (src / "model.safetensors").write_bytes(b"weights")
+        dst.mkdir()
+        (dst / "model.safetensors").write_bytes(b"corrupt")
+        
+        assert copy_weights(src, dst) == (1, 0, 7)
+        assert (dst / "model.safetensors").read_bytes() == b"weights"
+    
+    @pytest.mark.unit
+    def test_cp_from_other_root(self, maple_home, tmp_path):
+        """Test a policy is copied and registered with its source metadata."""
+        from maple.cmd.maple_cli import app
+        from maple.state import store
+        from maple.utils.paths import maple_home_at
+        
+        source = tmp_path / "shared"
+        weights = source / "models" / "openvla" / "7b"
+        weights.mkdir(parents=True)
+        (weights / "model.safetensors").write_bytes(b"weights")
+        with maple_home_at(source):
+            store.init_db()
+            store.add_policy("openvla", "img", "7b", "/elsewhere/openvla/7b", "openvla/openvla-7b", revision="abc")
+        
+        store.init_db()
+        result = runner.invoke(app, ["cp", "openvla:7b", "--from", str(source)])
+        
+        assert result.exit_code == 0
+        policy = store.get_policy("openvla", "7b")
+        assert policy["revision"] == "abc"
+        assert policy["path"] == str(maple_home / "models" / "openvla" / "7b")
+        assert (maple_home / "models" / "openvla" / "7b" / "model.safetensors").read_bytes() == b"weights"
+    
+    @pytest.mark.unit
+    def test_cp_missing_policy(self, maple_home, tmp_path):
+        """Test copying a policy the source root does not have fails."""
+        from maple.cmd.maple_cli import app
+        from maple.state import store
+        from maple.utils.paths import maple_home_at
+        
+        with maple_home_at(tmp_path):
+            store.init_db()
+        
+        result = runner.invoke(app, ["cp", "openvla:7b", "--from", str(tmp_path)])
+        
+        assert result.exit_code == 1
+
+
+class TestBenchCommand:
+    """Tests for the bench command."""
+    
+    @pytest.mark.unit
+    def test_percentile_interpolates(self):
+        """Test percentiles interpolate between the closest ranks."""
+        from maple.cmd.cli.bench import percentile
+        
+        samples = [4.0, 1.0, 3.0, 2.0]
+        
+        assert percentile(samples, 0) == 1.0
+        assert percentile(samples, 50) == 2.5
+        assert percentile(samples, 100) == 4.0
+        assert percentile([], 99) == 0.0
+    
+    @pytest.mark.unit
+    def test_bench_reports_and_stops(self, mock_requests):
+        """Test bench serves the policy, sends timed requests and stops it again."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"image_size": [64, 48]}
+        mock_requests["post"].return_value.json.return_value = {"policy_id": "openvla-7b-abc", "action": [0.0]}
+        
+        result = runner.invoke(app, ["bench", "openvla:7b", "--requests", "5", "--concurrency", "2", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "p95 latency" in result.output
+        urls = [c[0][0] for c in mock_requests["post"].call_args_list]
+        assert urls[0].endswith("/policy/serve")
+        # One warmup plus five timed requests
+        assert sum(url.endswith("/policy/act") for url in urls) == 6
+        assert urls[-1].endswith("/policy/stop/openvla-7b-abc")
+
+
+class TestLogsCommand:
+    """Tests for the logs command."""
+    
+    @pytest.mark.unit
+    def test_parse_since(self):
+        """Test --since accepts durations and timestamps."""
+        from datetime import datetime
+        from maple.cmd.cli.logs import parse_since
+        
+        now = datetime(2026, 1, 31, 14, 0, 0)
+        
+        assert parse_since("10m", now=now) == datetime(2026, 1, 31, 13, 50, 0)
+        assert parse_since("2026-01-31 12:30") == datetime(2026, 1, 31, 12, 30, 0)
+        with pytest.raises(ValueError):
+            parse_since("yesterday")
+    
+    @pytest.mark.unit
+    def test_filter_lines_keeps_continuations(self):
+        """Test tracebacks follow the record they belong to."""
+        from datetime import datetime
+        from maple.cmd.cli.logs import filter_lines
+        
+        lines = [
+            "2026-01-31 13:00:00 | ERROR    | old failure\n",
+            "Traceback (most recent call last):\n",
+            "2026-01-31 14:00:00 | INFO     | serving\n",
+            "2026-01-31 14:05:00 | ERROR    | new failure\n",
+            "Traceback (most recent call last):\n",
+        ]
+        
+        since = list(filter_lines(lines, since=datetime(2026, 1, 31, 13, 30)))
+        errors = list(filter_lines(lines, levels=["ERROR"]))
+        
+        assert since == lines[2:]
+        assert errors == [lines[0], lines[1], lines[3], lines[4]]
+    
+    @pytest.mark.unit
+    def test_filter_lines_reads_json(self):
+        """Test --since and --level work on lines written with --log-format json."""
+        from datetime import datetime, timezone
+        from maple.cmd.cli.logs import filter_lines
+        
+        def line(hour, level):
+            ts = datetime(2026, 1, 31, hour, tzinfo=timezone.utc).isoformat()
+            return f'{{"ts": "{ts}", "level": "{level}", "logger": "maple.daemon", "msg": "x"}}\n'
+        
+        lines = [line(13, "ERROR"), line(14, "INFO")]
+        since = datetime(2026, 1, 31, 13, 30, tzinfo=timezone.utc).astimezone().replace(tzinfo=None)
+        
+        assert list(filter_lines(lines, since=since)) == lines[1:]
+        assert list(filter_lines(lines, levels=["ERROR"])) == lines[:1]
+    
+    @pytest.mark.unit
+    def test_logs_daemon_and_model(self, maple_home):
+        """Test bare logs shows the daemon log and logs MODEL the policy's log."""
+        from maple.cmd.maple_cli import app
+        
+        logs = maple_home / "logs"
+        logs.mkdir()
+        (logs / "server.log").write_text("2026-01-31 14:00:00 | INFO     | MAPLE daemon started\n")
+        (logs / "openvla.log").write_text("2026-01-31 14:01:00 | INFO     | Serving openvla-7b-abc\n")
+        
+        daemon = runner.invoke(app, ["logs"])
+        model = runner.invoke(app, ["logs", "openvla:7b", "--tail", "1"])
+        
+        assert daemon.exit_code == 0
+        assert "daemon started" in daemon.output
+        assert model.exit_code == 0
+        assert "Serving openvla-7b-abc" in model.output
+        assert "daemon started" not in model.output
+    
+    @pytest.mark.unit
+    def test_model_log_handler_routes_tagged_records(self, maple_home):
+        """Test records tagged with a model land in that model's log file."""
+        import logging
+        from maple.utils.logging import ModelLogHandler, model_log_file
+        
+        handler = ModelLogHandler(logging.Formatter("%(message)s"))
+        logger = logging.getLogger("maple.test.logs")
+        logger.addHandler(handler)
+        logger.setLevel(logging.INFO)
+        try:
+            logger.info("untagged")
+            logger.info("loaded", extra={"model": "openvla"})
+        finally:
+            logger.removeHandler(handler)
+            handler.close()
+        
+        assert model_log_file("openvla").read_text() == "loaded\n"
+
+
+class TestRestartCommand:
+    """Tests for the restart command."""
+    
+    @pytest.mark.unit
+    def test_restart_reuses_recorded_flags(self):
+        """Test restart stops the daemon and starts one with its recorded flags."""
+        from maple.cmd.maple_cli import app
+        
+        old = {"pid": 4242, "port": 8000, "args": ["--port", "8000", "--metrics"]}
+        new = {"pid": 4343, "port": 8000, "args": old["args"]}
+        
+        with patch("maple.cmd.cli.restart.running_daemon", side_effect=[old, new]), \
+             patch("maple.cmd.cli.restart.stop_process", return_value=True) as stop_process, \
+             patch("maple.cmd.cli.restart.wait_for_port", return_value=True) as wait_for_port, \
+             patch("maple.cmd.cli.restart.start_detached") as start_detached:
+            result = runner.invoke(app, ["restart"])
+        
+        assert result.exit_code == 0
+        stop_process.assert_called_once_with(4242, timeout=30.0)
+        assert wait_for_port.call_args[0][0] == 8000
+        start_detached.assert_called_once_with(["--port", "8000", "--metrics"])
+        assert "pid 4343" in result.output
+    
+    @pytest.mark.unit
+    def test_restart_replaces_flags(self):
+        """Test flags after -- replace the recorded ones."""
+        from maple.cmd.maple_cli import app
+        
+        old = {"pid": 4242, "port": 8000, "args": ["--port", "8000"]}
+        new = {"pid": 4343, "port": 9000}
+        
+        with patch("maple.cmd.cli.restart.running_daemon", side_effect=[old, new]), \
+             patch("maple.cmd.cli.restart.stop_process", return_value=True), \
+             patch("maple.cmd.cli.restart.wait_for_port", return_value=True), \
+             patch("maple.cmd.cli.restart.start_detached") as start_detached:
+            result = runner.invoke(app, ["restart", "--", "--port", "9000"])
+        
+        assert result.exit_code == 0
+        start_detached.assert_called_once_with(["--port", "9000"])
+    
+    @pytest.mark.unit
+    def test_restart_without_daemon(self):
+        """Test restart fails when no daemon is running."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.restart.running_daemon", return_value=None), \
+             patch("maple.cmd.cli.restart.start_detached") as start_detached:
+            result = runner.invoke(app, ["restart"])
+        
+        assert result.exit_code == 1
+        assert "No MAPLE daemon running" in result.output
+        start_detached.assert_not_called()
+
+
+class TestPruneCommand:
+    """Tests for the prune command."""
+    
+    @pytest.mark.unit
+    def test_prune_reports_evictions(self):
+        """Test that prune passes loaded policies through and reports what it removed."""
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.prune.loaded_policies", return_value={("smolvla", "base")}), \
+             patch("maple.cmd.cli.prune.models_usage", return_value=3 * 1024 ** 3), \
+             patch("maple.cmd.cli.prune.prune_to_size", return_value=(["openvla:7b"], 2 * 1024 ** 3)) as mock_prune:
+            result = runner.invoke(app, ["prune", "--max-size", "1GB"])
+        
+        assert result.exit_code == 0
+        assert "Removed openvla:7b" in result.output
+        assert "freeing 2.0 GB" in result.output
+        mock_prune.assert_called_once_with(1024 ** 3, in_use={("smolvla", "base")}, dry_run=False)
+    
+    @pytest.mark.unit
+    def test_prune_invalid_size(self):
+        """Test that an unparseable budget is rejected."""
+        from maple.cmd.maple_cli import app
+        
+        result = runner.invoke(app, ["prune", "--max-size", "lots"])
+        
+        assert result.exit_code == 1
+
+
+class TestPushCommand:
+    """Tests for the push command."""
+    
+    @pytest.mark.unit
+    def test_push_requires_login(self, temp_dir):
+        """Test that pushing without a stored token fails before uploading."""
+        from maple.cmd.maple_cli import app
+        
+        policy = {"name": "openvla", "version": "kitchen-ft", "path": str(temp_dir)}
+        with patch("maple.cmd.cli.push.store.get_policy", return_value=policy), \
+             patch("maple.cmd.cli.push.get_token", return_value=None), \
+             patch("maple.cmd.cli.push.upload_repo") as mock_upload:
+            result = runner.invoke(app, ["push", "openvla:kitchen-ft", "myorg/openvla-kitchen"])
+        
+        assert result.exit_code == 1
+        assert "maple login huggingface.co" in result.output
+        mock_upload.assert_not_called()
+
+
+class TestCompatCommand:
+    """Tests for the compat command."""
+    
+    @pytest.mark.unit
+    def test_compat_json(self):
+        """Test each cell is supported, not installed or unsupported."""
+        import json
+        from maple.cmd.maple_cli import app
+        
+        with patch("maple.cmd.cli.compat.store.list_policies", return_value=[{"name": "openvla", "version": "7b"}]), \
+             patch("maple.cmd.cli.compat.store.list_envs", return_value=[{"name": "libero"}]), \
+             patch("maple.cmd.cli.compat.ENV_BACKENDS", {"libero": None, "bridge": None, "robocasa": None}), \
+             patch("maple.cmd.cli.compat.has_adapter", side_effect=lambda policy, env: env in ("libero", "bridge")):
+            result = runner.invoke(app, ["compat", "--json"])
+        
+        assert result.exit_code == 0
+        assert json.loads(result.output)["policies"] == {
+            "openvla:7b": {"bridge": "not installed", "libero": "supported", "robocasa": "unsupported"},
+        }
+
+
+class TestReplayCommand:
+    """Tests for the replay command."""
+    
+    @pytest.fixture
+    def trajectory(self, temp_dir):
+        """Record a two-step run of openvla:7b with its frames."""
+        from PIL import Image
+        from maple.utils.record import Recorder, frames_dir
+        
+        record = temp_dir / "run.jsonl"
+        frames = frames_dir(record) / "run-a1b2c3d4"
+        frames.mkdir(parents=True)
+        with Recorder(record) as recorder:
+            recorder.header(policy="openvla:7b", model_kwargs={"unnorm_key": "libero_10"})
+            for step, action in enumerate([[0.1, 1.0], [0.2, 1.0]]):
+                frame = frames / f"step_{step:05d}.png"
+                Image.new("RGB", (8, 8)).save(frame)
+                recorder.step(step, 1.0 + step, "pick", str(frame), action)
+        return record
+    
+    @pytest.mark.unit
+    def test_action_deviation(self):
+        """Test the deviation is the largest per-dimension difference."""
+        import math
+        from maple.cmd.cli.replay import action_deviation
+        
+        assert action_deviation([0.1, 1.0], [0.15, 0.9]) == pytest.approx(0.1)
+        assert action_deviation([0.1], [0.1]) == 0.0
+        assert math.isinf(action_deviation([0.1, 1.0], [0.1]))
+    
+    @pytest.mark.unit
+    def test_replay_on_loaded_policy(self, mock_requests, trajectory):
+        """Test each step is re-sent to the loaded policy and a deviation over tolerance exits 1."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"policies": [
+            {"policy_id": "openvla-7b-abc", "backend": "openvla", "version": "7b"},
+        ]}
+        mock_requests["post"].return_value.json.side_effect = [{"action": [0.1, 1.0]}, {"action": [0.25, 1.0]}]
+        
+        result = runner.invoke(app, ["replay", str(trajectory), "--tolerance", "0.01", "--port", "59999"])
+        
+        assert result.exit_code == 1
+        assert "Max deviation: 0.05" in result.output
+        assert "1 of 2 step(s) exceed" in result.output
+        urls = [c[0][0] for c in mock_requests["post"].call_args_list]
+        assert all(url.endswith("/policy/act") for url in urls)
+        payload = mock_requests["post"].call_args.kwargs["json"]
+        assert (payload["policy_id"], payload["instruction"]) == ("openvla-7b-abc", "pick")
+        assert payload["model_kwargs"] == {"unnorm_key": "libero_10"}
+    
+    @pytest.mark.unit
+    def test_replay_loads_and_stops_policy(self, mock_requests, trajectory):
+        """Test a policy that is not loaded is served for the replay and stopped afterwards."""
+        from maple.cmd.maple_cli import app
+        
+        mock_requests["get"].return_value.json.return_value = {"policies": []}
+        mock_requests["post"].return_value.json.side_effect = [
+            {"policy_id": "openvla-7b-new"}, {"action": [0.1, 1.0]}, {"action": [0.2, 1.0]}, {"stopped": ["openvla-7b-new"]},
+        ]
+        
+        result = runner.invoke(app, ["replay", str(trajectory), "--model", "openvla:7b", "--port", "59999"])
+        
+        assert result.exit_code == 0
+        assert "All steps within" in result.output
+        urls = [c[0][0] for c in mock_requests["post"].call_args_list]
+        assert urls[0].endswith("/policy/serve")
+        assert urls[-1].endswith("/policy/stop/openvla-7b-new")
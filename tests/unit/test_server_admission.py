@@ -0,0 +1,76 @@
+"""
+Unit tests for maple.server.admission module.
+
+Tests cover:
+- Running up to the concurrency limit
+- Queuing in arrival order and rejecting when the queue is full
+- Reported running and queued counts
+"""
+
+import time
+import threading
+
+import pytest
+
+from maple.server.admission import ActQueue, QueueFull
+
+
+def _hold(queue, started, release):
+    """Take a slot and keep it until released."""
+    with queue.slot():
+        started.set()
+        release.wait(5)
+
+
+class TestActQueue:
+    """Tests for ActQueue."""
+
+    @pytest.mark.unit
+    def test_rejects_when_queue_full(self):
+        """Test requests past the running and queued limits get QueueFull."""
+        queue = ActQueue(max_concurrent=1, max_queue=0)
+        started, release = threading.Event(), threading.Event()
+        holder = threading.Thread(target=_hold, args=(queue, started, release))
+        holder.start()
+        started.wait(5)
+
+        with pytest.raises(QueueFull):
+            with queue.slot():
+                pass
+
+        release.set()
+        holder.join(5)
+        assert queue.stats()["running"] == 0
+
+    @pytest.mark.unit
+    def test_queued_requests_run_in_order(self):
+        """Test waiting requests are counted and run oldest first once a slot frees up."""
+        queue = ActQueue(max_concurrent=1, max_queue=2)
+        started, release = threading.Event(), threading.Event()
+        holder = threading.Thread(target=_hold, args=(queue, started, release))
+        holder.start()
+        started.wait(5)
+
+        order = []
+
+        def wait(name):
+            with queue.slot():
+                order.append(name)
+
+        waiters = []
+        for name in ("first", "second"):
+            waiter = threading.Thread(target=wait, args=(name,))
+            waiter.start()
+            waiters.append(waiter)
+            while queue.stats()["queued"] < len(waiters):
+                time.sleep(0.01)
+
+        assert queue.stats() == {"running": 1, "queued": 2, "max_concurrent": 1, "max_queue": 2}
+
+        release.set()
+        for thread in [holder, *waiters]:
+            thread.join(5)
+
+        assert order == ["first", "second"]
+        assert queue.stats()["running"] == 0
+        assert queue.stats()["queued"] == 0
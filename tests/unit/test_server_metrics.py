@@ -0,0 +1,58 @@
+"""
+Unit tests for maple.server.metrics module.
+
+Tests cover:
+- Request counters labelled by method, route and status
+- Cumulative latency histogram buckets
+- Scrape-time samples and label escaping
+"""
+
+import pytest
+
+from maple.server.metrics import Metrics
+
+
+class TestMetrics:
+    """Tests for Metrics."""
+
+    @pytest.mark.unit
+    def test_request_counts(self):
+        """Test requests are counted per method, route and status."""
+        metrics = Metrics()
+        metrics.observe_request("GET", "/status", 200, 0.01)
+        metrics.observe_request("GET", "/status", 200, 0.02)
+        metrics.observe_request("POST", "/policy/pull", 400, 0.5)
+
+        text = metrics.render()
+
+        assert 'maple_http_requests_total{method="GET",path="/status",status="200"} 2' in text
+        assert 'maple_http_requests_total{method="POST",path="/policy/pull",status="400"} 1' in text
+        assert "# TYPE maple_http_requests_total counter" in text
+
+    @pytest.mark.unit
+    def test_latency_histogram_is_cumulative(self):
+        """Test each bucket counts every request at or below its bound."""
+        metrics = Metrics(buckets=(0.1, 1.0))
+        metrics.observe_request("POST", "/run", 200, 0.05)
+        metrics.observe_request("POST", "/run", 200, 0.5)
+        metrics.observe_request("POST", "/run", 200, 3.0)
+
+        lines = metrics.render().splitlines()
+
+        assert 'maple_http_request_duration_seconds_bucket{method="POST",path="/run",le="0.1"} 1' in lines
+        assert 'maple_http_request_duration_seconds_bucket{method="POST",path="/run",le="1"} 2' in lines
+        assert 'maple_http_request_duration_seconds_bucket{method="POST",path="/run",le="+Inf"} 3' in lines
+        assert 'maple_http_request_duration_seconds_sum{method="POST",path="/run"} 3.55' in lines
+        assert 'maple_http_request_duration_seconds_count{method="POST",path="/run"} 3' in lines
+
+    @pytest.mark.unit
+    def test_samples_and_escaping(self):
+        """Test scrape-time samples are rendered and label values escaped."""
+        metrics = Metrics()
+        metrics.observe_request("GET", 'a"b', 200, 0.01)
+
+        text = metrics.render([("maple_policies_loaded", "gauge", "Policies currently served.", 2)])
+
+        assert 'path="a\\"b"' in text
+        assert "# TYPE maple_policies_loaded gauge\nmaple_policies_loaded 2\n" in text
+        assert text.endswith("\n")
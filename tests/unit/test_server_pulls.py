@@ -0,0 +1,107 @@
+"""
+Unit tests for maple.server.pulls module.
+
+Tests cover:
+- Replaying progress to subscribers that attach mid-pull
+- Pulls continuing after a subscriber detaches
+- One job per ref, and conflicting revision pins
+"""
+
+import threading
+
+import pytest
+
+from maple.server.pulls import PullJob, PullRegistry
+
+
+def drain(q):
+    """Collect events from a subscriber queue up to the end marker."""
+    events = []
+    while True:
+        event = q.get(timeout=5)
+        if event is None:
+            return events
+        events.append(event)
+
+
+class TestPullJob:
+    """Tests for PullJob."""
+
+    @pytest.mark.unit
+    def test_reattach_replays_progress(self):
+        """Test a late subscriber first sees the latest progress of each file."""
+        started, release = threading.Event(), threading.Event()
+
+        def pull(progress, cancel):
+            progress("a.bin", 5, 10)
+            progress("a.bin", 8, 10)
+            started.set()
+            release.wait(5)
+            progress("a.bin", 10, 10)
+            return {"pulled": "openvla:7b"}
+
+        job = PullJob("openvla:7b")
+        job.start(pull)
+        started.wait(5)
+
+        first = job.subscribe()
+        job.unsubscribe(first)      # Client disconnects, pull keeps going
+        late = job.subscribe()
+        release.set()
+
+        events = drain(late)
+        assert events[0] == {"status": "downloading", "file": "a.bin", "completed": 8, "total": 10}
+        assert events[-1] == {"status": "success", "pulled": "openvla:7b"}
+        assert job.wait(5)["status"] == "success"
+
+    @pytest.mark.unit
+    def test_error_is_final_event(self):
+        """Test a failed pull ends the stream with an error event."""
+        def pull(progress, cancel):
+            raise RuntimeError("connection reset")
+
+        job = PullJob("openvla:7b")
+        job.start(pull)
+
+        assert job.wait(5) == {"status": "error", "error": "connection reset"}
+        assert drain(job.subscribe()) == [{"status": "error", "error": "connection reset"}]
+
+
+class TestPullRegistry:
+    """Tests for PullRegistry."""
+
+    @pytest.mark.unit
+    def test_same_ref_shares_job(self):
+        """Test a second pull of a running ref attaches instead of starting again."""
+        release = threading.Event()
+        calls = []
+
+        def pull(progress, cancel):
+            calls.append(1)
+            release.wait(5)
+            return {"pulled": "openvla:7b"}
+
+        registry = PullRegistry()
+        job = registry.start_or_attach("openvla:7b", None, pull)
+
+        assert registry.start_or_attach("openvla:7b", None, pull) is job
+        with pytest.raises(ValueError):
+            registry.start_or_attach("openvla:7b", "abc123", pull)
+
+        release.set()
+        job.wait(5)
+        assert calls == [1]
+        assert registry.active() == {}
+
+    @pytest.mark.unit
+    def test_cancel_all(self):
+        """Test shutdown cancellation reaches running pulls."""
+        def pull(progress, cancel):
+            cancel.wait(5)
+            raise RuntimeError("cancelled")
+
+        registry = PullRegistry()
+        job = registry.start_or_attach("openvla:7b", None, pull)
+        registry.cancel_all()
+
+        assert job.wait(5)["status"] == "error"
@@ -0,0 +1,65 @@
+"""
+Unit tests for maple.server.ratelimit module.
+
+Tests cover:
+- Burst, refill and the wait reported when over the limit
+- Separate buckets per client
+- Client keys from remote addresses
+"""
+
+import pytest
+
+from maple.server.ratelimit import RateLimiter, client_key
+
+
+class Clock:
+    """Manually advanced time source."""
+
+    def __init__(self):
+        self.now = 0.0
+
+    def __call__(self):
+        return self.now
+
+
+class TestRateLimiter:
+    """Tests for RateLimiter."""
+
+    @pytest.mark.unit
+    def test_burst_then_refill(self):
+        """Test a client gets its burst, waits for the next token, then proceeds."""
+        clock = Clock()
+        limiter = RateLimiter(rate=2, burst=3, clock=clock)
+
+        assert [limiter.acquire("a") for _ in range(3)] == [None, None, None]
+        assert limiter.acquire("a") == pytest.approx(0.5)
+
+        clock.now = 0.5
+        assert limiter.acquire("a") is None
+        assert limiter.acquire("a") is not None
+
+    @pytest.mark.unit
+    def test_clients_are_independent(self):
+        """Test one client running out does not limit another."""
+        limiter = RateLimiter(rate=1, clock=Clock())
+
+        assert limiter.acquire("a") is None
+        assert limiter.acquire("a") is not None
+        assert limiter.acquire("b") is None
+
+    @pytest.mark.unit
+    def test_rejects_invalid_rate(self):
+        """Test a rate of zero is refused."""
+        with pytest.raises(ValueError):
+            RateLimiter(rate=0)
+
+
+class TestClientKey:
+    """Tests for client_key."""
+
+    @pytest.mark.unit
+    def test_keyed_by_address(self):
+        """Test clients are grouped by remote address."""
+        assert client_key("10.0.0.1") == "ip:10.0.0.1"
+        assert client_key("10.0.0.1") != client_key("10.0.0.2")
+        assert client_key(None) == "ip:unknown"
@@ -0,0 +1,71 @@
+"""
+Unit tests for maple.state.listing module.
+
+Tests cover:
+- Sizing pulled policies from local storage, wherever they are stored
+- Stable pages and totals
+- Grouping versions with shared weights counted once
+"""
+
+import pytest
+
+
+@pytest.fixture
+def pulled(pulled_policy, maple_home):
+    """Register three policies, one of them hard-linking another's weights."""
+    pulled_policy("smolvla", "base", size=10)
+    pulled_policy("openvla", "7b", size=100)
+    derived = pulled_policy("openvla", "kitchen-ft", link_from="openvla:7b")
+    (derived / "adapter.bin").write_bytes(b"x" * 5)
+    return maple_home
+
+
+class TestListPolicies:
+    """Tests for list_policies."""
+
+    @pytest.mark.unit
+    def test_sizes_and_total(self, pulled):
+        """Test every policy is listed with the size of its weights."""
+        from maple.state.listing import list_policies
+
+        data = list_policies()
+
+        assert data["total"] == 3
+        sizes = {(p["name"], p["version"]): p["size"] for p in data["policies"]}
+        assert sizes == {("smolvla", "base"): 10, ("openvla", "7b"): 100, ("openvla", "kitchen-ft"): 105}
+
+    @pytest.mark.unit
+    def test_sizes_policies_outside_models_dir(self, test_db, temp_dir):
+        """Test a policy stored elsewhere, as with --models-at, is sized from its path."""
+        from maple.state import store
+        from maple.state.listing import list_policies
+
+        weights = temp_dir / "mnt" / "openvla" / "7b"
+        weights.mkdir(parents=True)
+        (weights / "model.bin").write_bytes(b"x" * 42)
+        (weights / "shard.bin.part").write_bytes(b"x" * 8)
+        store.add_policy("openvla", "img", "7b", str(weights))
+
+        assert list_policies()["policies"][0]["size"] == 42
+
+    @pytest.mark.unit
+    def test_page_sorted_by_name(self, pulled):
+        """Test pages are sliced from policies sorted by name and version."""
+        from maple.state.listing import list_policies
+
+        data = list_policies(limit=2, offset=1)
+
+        assert [(p["name"], p["version"]) for p in data["policies"]] == [("openvla", "kitchen-ft"), ("smolvla", "base")]
+        assert data["total"] == 3
+
+    @pytest.mark.unit
+    def test_group_counts_shared_weights_once(self, pulled):
+        """Test a name's size counts hard-linked files once."""
+        from maple.state.listing import list_policies
+
+        groups = list_policies(group=True)["groups"]
+
+        assert [(g["name"], [v["version"] for v in g["versions"]], g["size"]) for g in groups] == [
+            ("openvla", ["7b", "kitchen-ft"], 105),
+            ("smolvla", ["base"], 10),
+        ]
@@ -0,0 +1,97 @@
+"""
+Unit tests for maple.state.prune module.
+
+Tests cover:
+- Eviction order by pull time
+- Eviction order by last use, when recorded
+- Stopping once usage fits the budget
+- Keeping loaded policies
+- Dry runs
+- Counting hard-linked weights once
+"""
+
+import pytest
+from unittest.mock import patch
+
+
+@pytest.fixture
+def pulled(pulled_policy, maple_home):
+    """Register three policies of 100 bytes each, pulled oldest to newest."""
+    for i, version in enumerate(["old", "mid", "new"]):
+        with patch("maple.state.store.time.time", return_value=1000.0 + i):
+            pulled_policy("openvla", version, size=100)
+    return maple_home
+
+
+class TestPruneToSize:
+    """Tests for prune_to_size."""
+
+    @pytest.mark.unit
+    def test_within_budget(self, pulled):
+        """Test that nothing is removed when usage fits the budget."""
+        from maple.state.prune import prune_to_size
+
+        assert prune_to_size(300) == ([], 0)
+
+    @pytest.mark.unit
+    def test_evicts_least_recently_pulled(self, pulled):
+        """Test that the oldest policies go first and pruning stops under budget."""
+        from maple.state import store
+        from maple.state.prune import prune_to_size
+        from maple.utils.paths import policy_dir
+
+        removed, freed = prune_to_size(150)
+
+        assert removed == ["openvla:old", "openvla:mid"]
+        assert freed == 200
+        assert not policy_dir("openvla", "old").exists()
+        assert [p["version"] for p in store.list_policies()] == ["new"]
+
+    @pytest.mark.unit
+    def test_last_use_wins_over_pull_time(self, pulled):
+        """Test that a recently used policy outlives newer but unused ones."""
+        from maple.state import store
+        from maple.state.prune import prune_to_size
+
+        store.touch_policy("openvla", "old", at=2000.0)
+
+        removed, _ = prune_to_size(150)
+
+        assert removed == ["openvla:mid", "openvla:new"]
+
+    @pytest.mark.unit
+    def test_keeps_loaded_policies(self, pulled):
+        """Test that a loaded policy is skipped even if it is the oldest."""
+        from maple.state.prune import prune_to_size
+
+        removed, _ = prune_to_size(150, in_use={("openvla", "old")})
+
+        assert removed == ["openvla:mid", "openvla:new"]
+
+    @pytest.mark.unit
+    def test_dry_run(self, pulled):
+        """Test that a dry run reports evictions without removing anything."""
+        from maple.state import store
+        from maple.state.prune import prune_to_size
+
+        removed, freed = prune_to_size(0, dry_run=True)
+
+        assert removed == ["openvla:old", "openvla:mid", "openvla:new"]
+        assert freed == 300
+        assert len(store.list_policies()) == 3
+
+    @pytest.mark.unit
+    def test_linked_weights_freed_with_last_link(self, pulled, pulled_policy):
+        """Test that a linked file is counted once and evicting its base frees nothing while it is linked."""
+        from maple.state.prune import models_usage, prune_to_size
+
+        with patch("maple.state.store.time.time", return_value=1003.0):
+            pulled_policy("openvla", "ft", link_from="openvla:old")
+
+        assert models_usage() == 300
+
+        removed, freed = prune_to_size(150)
+
+        assert removed == ["openvla:old", "openvla:mid", "openvla:new"]
+        assert freed == 200
+        assert models_usage() == 100
@@ -0,0 +1,74 @@
+"""
+Unit tests for maple.state.repair module.
+
+Tests cover:
+- Finding partial downloads, staging directories, orphaned weights and
+  store entries without weights
+- Leaving refs that are being written alone
+- Fixing issues
+"""
+
+import pytest
+
+
+@pytest.fixture
+def storage(pulled_policy, maple_home):
+    """Register openvla:7b with a partial download and leave leftovers around it."""
+    from maple.state import store
+    from maple.utils.paths import policy_dir
+
+    weights = pulled_policy("openvla", "7b", size=10)
+    (weights / "shard.bin.part").write_bytes(b"x" * 5)
+
+    store.add_policy("openvla", "img", "gone", str(policy_dir("openvla", "gone")))
+    policy_dir("openvla", "stray").mkdir(parents=True)
+    (weights.parent / ".ft.creating").mkdir()
+    return maple_home
+
+
+class TestFindIssues:
+    """Tests for find_issues."""
+
+    @pytest.mark.unit
+    def test_finds_each_kind(self, storage):
+        """Test that every kind of leftover is found, safe ones first."""
+        from maple.state.repair import find_issues
+
+        issues = find_issues()
+
+        assert [(i.kind, i.ref, i.destructive) for i in issues] == [
+            ("staging", ("openvla", "ft"), False),
+            ("partial", ("openvla", "7b"), False),
+            ("orphan", ("openvla", "stray"), True),
+            ("missing_weights", ("openvla", "gone"), True),
+        ]
+
+    @pytest.mark.unit
+    def test_skips_locked_refs(self, storage):
+        """Test that a ref being pulled is not reported."""
+        from maple.utils.lock import lock_ref
+        from maple.state.repair import find_issues
+
+        with lock_ref("openvla", "7b"):
+            kinds = [i.kind for i in find_issues()]
+
+        assert "partial" not in kinds
+
+
+class TestFixIssue:
+    """Tests for fix_issue."""
+
+    @pytest.mark.unit
+    def test_fixes_all(self, storage):
+        """Test that fixing every issue leaves a clean scan and the registered weights."""
+        from maple.state import store
+        from maple.utils.paths import policy_dir
+        from maple.state.repair import find_issues, fix_issue
+
+        for issue in find_issues():
+            fix_issue(issue)
+
+        assert find_issues() == []
+        assert (policy_dir("openvla", "7b") / "model.bin").exists()
+        assert not policy_dir("openvla", "stray").exists()
+        assert store.get_policy("openvla", "gone") is None
@@ -10,6 +10,7 @@ Tests cover:
 
 import pytest
 from datetime import datetime
+from unittest.mock import patch
 
 
 class TestPolicyStore:
@@ -49,6 +50,43 @@ class TestPolicyStore:
         assert policy["path"] == "/test/path"
         assert policy["repo"] == "org/repo"
     
+    @pytest.mark.unit
+    def test_read_policy_from_other_root(self, test_db, temp_dir):
+        """Test reading another root's policy leaves an older database unmigrated."""
+        import sqlite3
+        from maple.state import store
+        
+        store.add_policy("openvla", "img", "7b", "/p", "org/repo")
+        store.set_policy_annotations("openvla", "7b", {"score": "0.8"})
+        assert store.read_policy(test_db, "openvla", "7b")["annotations"] == {"score": "0.8"}
+        
+        old = temp_dir / "old.db"
+        conn = sqlite3.connect(old)
+        conn.execute("CREATE TABLE policies (name TEXT, version TEXT, image TEXT, path TEXT)")
+        conn.execute("INSERT INTO policies VALUES ('openvla', '7b', 'img', '/p')")
+        conn.commit()
+        conn.close()
+        
+        policy = store.read_policy(old, "openvla", "7b")
+        assert policy["path"] == "/p"
+        assert policy["annotations"] == {}
+        assert store.read_policy(old, "openvla", "latest") is None
+        conn = sqlite3.connect(old)
+        assert [c[1] for c in conn.execute("PRAGMA table_info(policies)")] == ["name", "version", "image", "path"]
+        conn.close()
+    
+    @pytest.mark.unit
+    def test_touch_policy(self, test_db):
+        """Test that touching a policy records its last use."""
+        from maple.state import store
+        
+        store.add_policy("test_policy", "test:image", "v1", "/test/path")
+        assert store.get_policy("test_policy", "v1")["last_used"] is None
+        
+        assert store.touch_policy("test_policy", "v1", at=1234.0) is True
+        assert store.get_policy("test_policy", "v1")["last_used"] == 1234.0
+        assert store.touch_policy("missing", "v1") is False
+    
     @pytest.mark.unit
     def test_get_nonexistent_policy(self, test_db):
         """Test that getting a nonexistent policy returns None."""
@@ -88,6 +126,82 @@ class TestPolicyStore:
         assert "policy1:v1" in names
         assert "policy1:v2" in names
         assert "policy2:v2" in names
+    
+    @pytest.mark.unit
+    def test_policy_revision(self, test_db):
+        """Test the pulled revision is stored and updated on re-pull."""
+        from maple.state import store
+        
+        store.add_policy("openvla", "img", "7b", "/p", "openvla/openvla-7b", revision="a" * 40)
+        assert store.get_policy("openvla", "7b")["revision"] == "a" * 40
+        
+        store.add_policy("openvla", "img", "7b", "/p", "openvla/openvla-7b", revision="b" * 40, ref="main")
+        assert store.get_policy("openvla", "7b")["revision"] == "b" * 40
+        assert store.get_policy("openvla", "7b")["ref"] == "main"
+    
+    @pytest.mark.unit
+    def test_init_db_adds_missing_columns(self, test_db):
+        """Test databases created before the revision column are upgraded."""
+        import sqlite3
+        from maple.state import store
+        
+        conn = sqlite3.connect(test_db)
+        conn.execute("DROP TABLE policies")
+        conn.execute("""
+            CREATE TABLE policies (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                name TEXT NOT NULL, image TEXT NOT NULL, version TEXT NOT NULL,
+                path TEXT NOT NULL, repo TEXT, pulled_at REAL NOT NULL,
+                UNIQUE(name, version)
+            )
+        """)
+        conn.commit()
+        conn.close()
+        
+        store.init_db()
+        store.add_policy("openvla", "img", "7b", "/p", revision="abc")
+        
+        assert store.get_policy("openvla", "7b")["revision"] == "abc"
+        assert store.get_policy("openvla", "7b")["annotations"] == {}
+    
+    @pytest.mark.unit
+    def test_policy_annotations(self, test_db):
+        """Test annotations are stored, listed and kept on re-pull."""
+        from maple.state import store
+        
+        store.add_policy("openvla", "img", "7b", "/p")
+        assert store.set_policy_annotations("openvla", "7b", {"dataset": "libero-v2"})
+        assert not store.set_policy_annotations("openvla", "13b", {"dataset": "libero-v2"})
+        
+        store.add_policy("openvla", "img", "7b", "/p")
+        assert store.get_policy("openvla", "7b")["annotations"] == {"dataset": "libero-v2"}
+        assert store.list_policies()[0]["annotations"] == {"dataset": "libero-v2"}
+
+    
+    @pytest.mark.unit
+    def test_policy_history(self, test_db):
+        """Test pulls and removals are recorded oldest first."""
+        from maple.state import store
+        
+        store.add_policy("openvla", "img", "7b", "/p", "openvla/openvla-7b", revision="a" * 40)
+        store.add_policy("openvla", "img", "7b", "/p", "openvla/openvla-7b", revision="b" * 40)
+        store.add_policy("openvla", "img", "latest", "/p", "openvla/openvla-7b", revision="b" * 40)
+        store.remove_policy("openvla", "7b")
+        
+        history = store.list_policy_history("openvla", "7b")
+        assert [(h["event"], h["revision"][0]) for h in history] == [("pull", "a"), ("pull", "b"), ("remove", "b")]
+        assert len(store.list_policy_history("openvla")) == 4
+    
+    @pytest.mark.unit
+    def test_policy_history_capped(self, test_db):
+        """Test only the newest entries per policy are kept."""
+        from maple.state import store
+        
+        with patch.object(store, "HISTORY_LIMIT", 3):
+            for i in range(5):
+                store.add_policy("openvla", "img", "7b", "/p", revision=str(i))
+        
+        assert [h["revision"] for h in store.list_policy_history("openvla")] == ["2", "3", "4"]
 
 
 class TestEnvStore:
@@ -0,0 +1,71 @@
+"""
+Unit tests for maple.utils.auth module.
+
+Tests cover:
+- Registry host normalization
+- Token storage, lookup and removal
+- Auth file permissions
+"""
+
+import os
+import stat
+import pytest
+
+from maple.utils.auth import (
+    normalize_registry,
+    save_token,
+    get_token,
+    remove_token,
+    auth_headers,
+)
+
+
+class TestNormalizeRegistry:
+    """Tests for normalize_registry."""
+
+    @pytest.mark.unit
+    def test_default_registry(self):
+        """Test that no registry maps to HuggingFace."""
+        assert normalize_registry(None) == "huggingface.co"
+        assert normalize_registry("hf.co") == "huggingface.co"
+
+    @pytest.mark.unit
+    def test_strips_scheme_and_path(self):
+        """Test that URLs reduce to their host."""
+        assert normalize_registry("https://Registry.Example.com/v2/") == "registry.example.com"
+
+
+class TestTokenStore:
+    """Tests for token storage."""
+
+    @pytest.mark.unit
+    def test_save_and_get(self, temp_dir):
+        """Test that saved tokens can be read back per registry."""
+        path = temp_dir / "auth.json"
+
+        save_token(None, "hf_abc", path=path)
+        save_token("registry.example.com", "secret", path=path)
+
+        assert get_token("huggingface.co", path=path) == "hf_abc"
+        assert get_token("registry.example.com", path=path) == "secret"
+        assert auth_headers("registry.example.com", path=path) == {"Authorization": "Bearer secret"}
+
+    @pytest.mark.unit
+    def test_file_permissions(self, temp_dir):
+        """Test that the auth file is only readable by the owner."""
+        path = temp_dir / "auth.json"
+        save_token(None, "hf_abc", path=path)
+
+        assert stat.S_IMODE(os.stat(path).st_mode) == 0o600
+
+    @pytest.mark.unit
+    def test_remove(self, temp_dir):
+        """Test that logout removes only the given registry."""
+        path = temp_dir / "auth.json"
+        save_token(None, "hf_abc", path=path)
+        save_token("registry.example.com", "secret", path=path)
+
+        assert remove_token(None, path=path) is True
+        assert remove_token(None, path=path) is False
+        assert get_token(None, path=path) is None
+        assert get_token("registry.example.com", path=path) == "secret"
@@ -106,6 +106,75 @@ class TestLoadConfig:
         assert config.eval.max_steps == 500
 
 
+class TestConfigGetSet:
+    """Tests for dotted-key config access."""
+    
+    @pytest.mark.unit
+    def test_get_value(self, default_config):
+        """Test reading a value by dotted key."""
+        from maple.utils.config import get_config_value
+        
+        assert get_config_value("daemon.port", default_config) == 8000
+        assert get_config_value("policy.keep_alive", default_config) == "5m"
+    
+    @pytest.mark.unit
+    def test_get_unknown_key(self, default_config):
+        """Test that unknown keys raise KeyError."""
+        from maple.utils.config import get_config_value
+        
+        with pytest.raises(KeyError):
+            get_config_value("daemon.nope", default_config)
+        with pytest.raises(KeyError):
+            get_config_value("port", default_config)
+    
+    @pytest.mark.unit
+    def test_set_value_persists(self, temp_dir):
+        """Test that set converts types and writes the file."""
+        from maple.utils.config import set_config_value, load_config
+        
+        path = temp_dir / "config.yaml"
+        
+        assert set_config_value("daemon.port", "9001", path) == 9001
+        assert set_config_value("eval.save_video", "yes", path) is True
+        
+        config = load_config(path)
+        assert config.daemon.port == 9001
+        assert config.eval.save_video is True
+    
+    @pytest.mark.unit
+    def test_set_invalid_value(self, temp_dir):
+        """Test that values of the wrong type are rejected."""
+        from maple.utils.config import set_config_value
+        
+        with pytest.raises(ValueError):
+            set_config_value("daemon.port", "not-a-port", temp_dir / "config.yaml")
+
+    @pytest.mark.unit
+    def test_set_registry_options(self, temp_dir):
+        """Test that registry options are stored per host and None removes them."""
+        from maple.utils.config import set_registry_options, load_config
+        
+        path = temp_dir / "config.yaml"
+        
+        set_registry_options("registry.internal", path, insecure=True, ca_cert="/etc/ssl/ca.pem")
+        assert set_registry_options("registry.internal", path, insecure=None) == {"ca_cert": "/etc/ssl/ca.pem"}
+        
+        config = load_config(path)
+        assert config.policy.registries == {"registry.internal": {"ca_cert": "/etc/ssl/ca.pem"}}
+        load_config(temp_dir / "missing.yaml")
+    
+    @pytest.mark.unit
+    def test_set_registry_options_rejects_unknown(self, temp_dir):
+        """Test that unknown registry options are not written."""
+        from maple.utils.config import set_registry_options, ConfigError
+        
+        path = temp_dir / "config.yaml"
+        
+        with pytest.raises(ConfigError):
+            set_registry_options("registry.internal", path, verify="maybe")
+        assert not path.exists()
+
+
 class TestConfigSections:
     """Tests for individual config sections."""
     
@@ -174,3 +243,65 @@ class TestGetConfig:
         config2 = get_config()
         
         assert config1 is config2
+    
+    @pytest.mark.unit
+    def test_set_rejects_invalid_setting(self, temp_dir):
+        """Test that set refuses values validation would reject."""
+        from maple.utils.config import set_config_value, ConfigError
+        
+        path = temp_dir / "config.yaml"
+        
+        with pytest.raises(ConfigError):
+            set_config_value("daemon.port", "0", path)
+        assert not path.exists()
+
+
+class TestConfigValidate:
+    """Tests for Config.validate."""
+    
+    @pytest.mark.unit
+    def test_defaults_are_valid(self, default_config):
+        """Test the default configuration passes validation."""
+        default_config.validate()
+    
+    @pytest.mark.unit
+    def test_collects_all_errors(self, default_config):
+        """Test every invalid setting is reported at once."""
+        from maple.utils.config import ConfigError
+        
+        default_config.daemon.port = 70000
+        default_config.eval.max_steps = 0
+        default_config.policy.default_device = "gpu"
+        default_config.policy.keep_alive = "soon"
+        
+        with pytest.raises(ConfigError) as exc:
+            default_config.validate()
+        
+        keys = [message.split()[0] for message in exc.value.errors]
+        assert keys == ["policy.default_device", "policy.keep_alive", "daemon.port", "eval.max_steps"]
+    
+    @pytest.mark.unit
+    def test_register_device_type(self, default_config, monkeypatch):
+        """Test additional device types can be registered."""
+        from maple.utils import config as config_module
+        from maple.utils.config import ConfigError, register_device_type
+        
+        monkeypatch.setattr(config_module, "DEVICE_TYPES", list(config_module.DEVICE_TYPES))
+        default_config.policy.default_device = "xpu:0"
+        
+        with pytest.raises(ConfigError):
+            default_config.validate()
+        
+        register_device_type("xpu")
+        default_config.validate()
+    
+    @pytest.mark.unit
+    def test_load_rejects_invalid_file(self, temp_dir):
+        """Test load_config raises on invalid file values."""
+        from maple.utils.config import load_config, ConfigError
+        
+        path = temp_dir / "config.yaml"
+        path.write_text("run:\n  max_steps: -5\n")
+        
+        with pytest.raises(ConfigError):
+            load_config(path)
@@ -0,0 +1,372 @@
+"""
+Unit tests for maple.utils.download module.
+
+Tests cover:
+- Skipping files that are already complete, checked by digest
+- Removing files a new revision no longer has
+- Retrying transient errors but not client errors
+- Concurrent repo downloads and failure propagation
+- Caller cancellation
+- Dry-run download planning
+- Registry endpoint, TLS and proxy options
+- Falling back to registry mirrors
+- Discarding downloads that fail their size or digest check
+"""
+
+import pytest
+import hashlib
+import requests
+import threading
+from unittest.mock import MagicMock, patch
+
+from maple.utils.hub import Hub, hub_for
+from maple.utils.download import (
+    download_file, download_repo, list_repo_entries, list_repo_files, plan_download, git_blob_id, DownloadCancelled,
+    IntegrityError,
+)
+
+
+class TestDownloadFile:
+    """Tests for download_file."""
+
+    @pytest.mark.unit
+    def test_skips_complete_file(self, temp_dir):
+        """Test that a file with the expected size is not fetched again."""
+        dest = temp_dir / "model.safetensors"
+        dest.write_bytes(b"x" * 10)
+        events = []
+
+        with patch("maple.utils.download.requests.get") as mock_get:
+            download_file("http://example/model", dest, "model.safetensors", total=10,
+                          progress=lambda *e: events.append(e))
+
+        mock_get.assert_not_called()
+        assert events == [("model.safetensors", 10, 10)]
+
+    @pytest.mark.unit
+    def test_refetches_same_size_with_other_digest(self, temp_dir):
+        """Test that a file of the right size but different contents is downloaded again."""
+        dest = temp_dir / "model.safetensors"
+        dest.write_bytes(b"old!")
+        response = MagicMock(status_code=200, headers={"Content-Length": "4"})
+        response.iter_content.return_value = [b"new!"]
+        response.__enter__.return_value = response
+        digest = "sha256:" + hashlib.sha256(b"new!").hexdigest()
+
+        with patch("maple.utils.download.requests.get", return_value=response) as mock_get:
+            download_file("http://example/model", dest, "model.safetensors", total=4, digest=digest)
+
+        mock_get.assert_called_once()
+        assert dest.read_bytes() == b"new!"
+
+    @pytest.mark.unit
+    def test_client_error_not_retried(self, temp_dir):
+        """Test that a 404 fails immediately instead of retrying."""
+        response = MagicMock(status_code=404)
+        error = requests.HTTPError("404 Not Found", response=response)
+
+        with patch("maple.utils.download._fetch", side_effect=error) as mock_fetch:
+            with pytest.raises(requests.HTTPError):
+                download_file("http://example/missing", temp_dir / "missing", "missing", max_retries=3)
+
+        assert mock_fetch.call_count == 1
+
+    @pytest.mark.unit
+    def test_transient_error_retried(self, temp_dir):
+        """Test that connection errors are retried until the download succeeds."""
+        dest = temp_dir / "model.bin"
+
+        def flaky_fetch(url, part, *args):
+            if flaky_fetch.calls == 0:
+                flaky_fetch.calls += 1
+                raise requests.ConnectionError("connection reset")
+            part.write_bytes(b"data")
+            return 4
+        flaky_fetch.calls = 0
+
+        with patch("maple.utils.download._fetch", side_effect=flaky_fetch), \
+             patch("maple.utils.retry.time.sleep"):
+            download_file("http://example/model", dest, "model.bin", total=4, max_retries=2)
+
+        assert dest.read_bytes() == b"data"
+
+    @pytest.mark.unit
+    def test_digest_mismatch_discarded(self, temp_dir):
+        """Test that a finished file with the wrong digest is not put in place."""
+        dest = temp_dir / "model.bin"
+        response = MagicMock(status_code=200, headers={"Content-Length": "4"})
+        response.iter_content.return_value = [b"evil"]
+        response.__enter__.return_value = response
+        digest = "sha256:" + hashlib.sha256(b"good").hexdigest()
+
+        with patch("maple.utils.download.requests.get", return_value=response):
+            with pytest.raises(IntegrityError):
+                download_file("http://example/model", dest, "model.bin", total=4, digest=digest)
+
+        assert list(temp_dir.iterdir()) == []
+
+    @pytest.mark.unit
+    def test_short_read_resumed(self, temp_dir):
+        """Test that a response ending early is retried from where it stopped."""
+        dest = temp_dir / "model.bin"
+        first = MagicMock(status_code=200, headers={"Content-Length": "4"})
+        first.iter_content.return_value = [b"da"]
+        second = MagicMock(status_code=206, headers={"Content-Length": "2"})
+        second.iter_content.return_value = [b"ta"]
+        for response in (first, second):
+            response.__enter__.return_value = response
+
+        with patch("maple.utils.download.requests.get", side_effect=[first, second]) as mock_get, \
+             patch("maple.utils.retry.time.sleep"):
+            download_file("http://example/model", dest, "model.bin", total=4)
+
+        assert mock_get.call_args.kwargs["headers"]["Range"] == "bytes=2-"
+        assert dest.read_bytes() == b"data"
+
+
+class TestPlanDownload:
+    """Tests for plan_download."""
+
+    @pytest.mark.unit
+    def test_marks_cached_files(self, temp_dir):
+        """Test that only files on disk with the expected size count as cached."""
+        (temp_dir / "config.json").write_bytes(b"x" * 10)
+        (temp_dir / "model.safetensors").write_bytes(b"x" * 50)
+        files = [("config.json", 10, None), ("model.safetensors", 100, None), ("tokenizer.json", 5, None)]
+
+        with patch("maple.utils.download.list_repo_entries", return_value=files):
+            plan = plan_download("org/model", temp_dir)
+
+        assert plan == [
+            ("config.json", 10, True),
+            ("model.safetensors", 100, False),
+            ("tokenizer.json", 5, False),
+        ]
+
+
+class TestDownloadRepo:
+    """Tests for download_repo."""
+
+    @pytest.mark.unit
+    def test_downloads_all_files(self, temp_dir):
+        """Test that each listed file is downloaded once."""
+        files = [("config.json", 10, None), ("model.safetensors", 100, None), ("config.json", 10, None)]
+
+        with patch("maple.utils.download.list_repo_entries", return_value=files), \
+             patch("maple.utils.download.download_file") as mock_download:
+            result = download_repo("org/model", temp_dir, max_workers=2)
+
+        assert sorted(result) == [("config.json", 10), ("model.safetensors", 100)]
+        assert sorted(c.kwargs["name"] for c in mock_download.call_args_list) == ["config.json", "model.safetensors"]
+
+    @pytest.mark.unit
+    def test_failure_is_raised(self, temp_dir):
+        """Test that a failed file aborts the pull with its error."""
+        files = [("a.bin", 1, None), ("b.bin", 1, None)]
+
+        def fake_download(**kwargs):
+            if kwargs["name"] == "b.bin":
+                raise RuntimeError("connection reset")
+
+        with patch("maple.utils.download.list_repo_entries", return_value=files), \
+             patch("maple.utils.download.download_file", side_effect=fake_download):
+            with pytest.raises(RuntimeError, match="connection reset"):
+                download_repo("org/model", temp_dir, max_workers=2)
+
+    @pytest.mark.unit
+    def test_cancelled_by_caller(self, temp_dir):
+        """Test that a set cancel event stops the pull before any file is fetched."""
+        files = [("a.bin", 1, None), ("b.bin", 1, None)]
+        cancel = threading.Event()
+        cancel.set()
+
+        with patch("maple.utils.download.list_repo_entries", return_value=files), \
+             patch("maple.utils.download.download_file") as mock_download:
+            with pytest.raises(DownloadCancelled):
+                download_repo("org/model", temp_dir, cancel=cancel)
+
+        mock_download.assert_not_called()
+
+    @pytest.mark.unit
+    def test_removes_files_not_in_listing(self, temp_dir):
+        """Test that files dropped by the pulled revision are deleted afterwards."""
+        (temp_dir / "model.bin").write_bytes(b"x")
+        (temp_dir / "old").mkdir()
+        (temp_dir / "old" / "shard-1.bin").write_bytes(b"x")
+
+        with patch("maple.utils.download.list_repo_entries", return_value=[("model.bin", 1, None)]), \
+             patch("maple.utils.download.download_file"):
+            download_repo("org/model", temp_dir)
+
+        assert sorted(p.name for p in temp_dir.rglob("*")) == ["model.bin"]
+
+    @pytest.mark.unit
+    def test_reuses_matching_files(self, temp_dir):
+        """Test that unchanged files of another revision are linked instead of downloaded."""
+        old, new = temp_dir / "old", temp_dir / "new"
+        old.mkdir()
+        (old / "config.json").write_bytes(b"{}")
+        files = [("config.json", 2, f"git:{git_blob_id(old / 'config.json')}"), ("model.bin", 1, None)]
+
+        with patch("maple.utils.download.list_repo_entries", return_value=files), \
+             patch("maple.utils.download.download_file") as mock_download:
+            download_repo("org/model", new, reuse_from=old)
+
+        assert [c.kwargs["name"] for c in mock_download.call_args_list] == ["model.bin"]
+        assert (new / "config.json").stat().st_ino == (old / "config.json").stat().st_ino
+
+
+class TestRegistryOptions:
+    """Tests for registry endpoint, TLS and proxy options."""
+
+    @pytest.mark.unit
+    def test_listing_uses_hub(self):
+        """Test that the file listing goes to the hub endpoint with its TLS options."""
+        hub = Hub(endpoint="https://registry.internal", verify=False)
+        response = MagicMock()
+        response.json.return_value = {"siblings": [{"rfilename": "config.json", "size": 10}]}
+
+        with patch("maple.utils.download.requests.get", return_value=response) as mock_get:
+            files = list_repo_files("org/model", revision="v1", hub=hub)
+
+        assert files == [("config.json", 10)]
+        assert mock_get.call_args.args[0] == "https://registry.internal/api/models/org/model/revision/v1"
+        assert mock_get.call_args.kwargs["params"] == {"blobs": "true"}
+        assert mock_get.call_args.kwargs["verify"] is False
+
+    @pytest.mark.unit
+    def test_listing_digests(self):
+        """Test that LFS files are listed with their SHA-256 and others with their blob ID."""
+        response = MagicMock()
+        response.json.return_value = {"siblings": [
+            {"rfilename": "config.json", "size": 10, "blobId": "b1"},
+            {"rfilename": "model.safetensors", "size": 100, "blobId": "b2", "lfs": {"sha256": "abc", "size": 100}},
+            {"rfilename": "README.md"},
+        ]}
+
+        with patch("maple.utils.download.requests.get", return_value=response):
+            entries = list_repo_entries("org/model")
+
+        assert entries == [
+            ("config.json", 10, "git:b1"),
+            ("model.safetensors", 100, "sha256:abc"),
+            ("README.md", 0, None),
+        ]
+
+    @pytest.mark.unit
+    def test_file_download_uses_ca_bundle(self, temp_dir):
+        """Test that file downloads verify against the hub's CA bundle."""
+        hub = Hub(verify="/etc/ssl/internal.pem")
+        response = MagicMock(status_code=200, headers={"Content-Length": "4"})
+        response.iter_content.return_value = [b"data"]
+        response.__enter__.return_value = response
+
+        with patch("maple.utils.download.requests.get", return_value=response) as mock_get:
+            download_file("https://registry.internal/f", temp_dir / "f", "f", hub=hub)
+
+        assert mock_get.call_args.kwargs["verify"] == "/etc/ssl/internal.pem"
+        assert (temp_dir / "f").read_bytes() == b"data"
+
+    @pytest.mark.unit
+    def test_resume_uses_proxy(self, temp_dir):
+        """Test that Range-resumed downloads go through the hub's proxy."""
+        hub = Hub(proxy="http://proxy.internal:3128")
+        (temp_dir / "f.part").write_bytes(b"da")
+        response = MagicMock(status_code=206, headers={"Content-Length": "2"})
+        response.iter_content.return_value = [b"ta"]
+        response.__enter__.return_value = response
+
+        with patch("maple.utils.download.requests.get", return_value=response) as mock_get:
+            download_file("https://huggingface.co/f", temp_dir / "f", "f", total=4, hub=hub)
+
+        kwargs = mock_get.call_args.kwargs
+        assert kwargs["headers"]["Range"] == "bytes=2-"
+        assert kwargs["proxies"] == {"http": "http://proxy.internal:3128", "https": "http://proxy.internal:3128"}
+        assert (temp_dir / "f").read_bytes() == b"data"
+
+    @pytest.mark.unit
+    def test_hub_for_keeps_proxy(self):
+        """Test that a proxy override reaches the hub alongside configured options."""
+        config = MagicMock()
+        config.policy.registries = {"registry.internal": {"insecure": True, "mirrors": ["https://mirror.internal/"]}}
+
+        with patch("maple.utils.hub.get_config", return_value=config):
+            hub = hub_for("registry.internal", proxy="http://proxy.internal:3128")
+
+        assert hub.endpoint == "https://registry.internal"
+        assert hub.proxy == "http://proxy.internal:3128"
+        assert hub.insecure
+        assert hub.mirrors == ["https://mirror.internal"]
+
+
+class TestMirrors:
+    """Tests for falling back to registry mirrors."""
+
+    @pytest.mark.unit
+    def test_listing_falls_back_to_mirror(self):
+        """Test that a failing primary moves the listing to the next mirror without its token."""
+        hub = Hub(endpoint="https://huggingface.co", mirrors=["https://mirror.internal"])
+        response = MagicMock()
+        response.json.return_value = {"siblings": [{"rfilename": "config.json", "size": 10}]}
+
+        def get(url, **kwargs):
+            if url.startswith("https://huggingface.co"):
+                raise requests.ConnectTimeout("timed out")
+            return response
+
+        with patch("maple.utils.download.requests.get", side_effect=get) as mock_get, \
+             patch("maple.utils.download.auth_headers", return_value={}):
+            files = list_repo_files("org/model", token="hf_secret", hub=hub)
+
+        assert files == [("config.json", 10)]
+        assert mock_get.call_args_list[0].kwargs["headers"] == {"Authorization": "Bearer hf_secret"}
+        assert mock_get.call_args_list[1].args[0].startswith("https://mirror.internal/api/models/org/model")
+        assert mock_get.call_args_list[1].kwargs["headers"] == {}
+
+    @pytest.mark.unit
+    def test_file_falls_back_to_mirror(self, temp_dir):
+        """Test that each file is tried on the mirrors in order."""
+        hub = Hub(mirrors=["https://a.internal", "https://b.internal"])
+        urls = []
+
+        def fake_download(url, **kwargs):
+            urls.append(url)
+            if not url.startswith("https://b.internal"):
+                raise requests.HTTPError("503 Service Unavailable")
+
+        with patch("maple.utils.download.list_repo_entries", return_value=[("model.bin", 1, None)]), \
+             patch("maple.utils.download.download_file", side_effect=fake_download), \
+             patch("maple.utils.download.auth_headers", return_value={}):
+            download_repo("org/model", temp_dir, revision="abc123", hub=hub)
+
+        assert [u.split("/")[2] for u in urls] == ["huggingface.co", "a.internal", "b.internal"]
+        assert urls[-1] == "https://b.internal/org/model/resolve/abc123/model.bin"
+
+    @pytest.mark.unit
+    def test_integrity_failure_moves_to_mirror(self, temp_dir):
+        """Test that a file failing its digest check is fetched again from the next mirror."""
+        hub = Hub(mirrors=["https://mirror.internal"])
+        urls = []
+
+        def fake_download(url, **kwargs):
+            urls.append(url)
+            if url.startswith("https://huggingface.co"):
+                raise IntegrityError("model.bin: digest mismatch")
+
+        with patch("maple.utils.download.list_repo_entries", return_value=[("model.bin", 1, "sha256:ab")]), \
+             patch("maple.utils.download.download_file", side_effect=fake_download), \
+             patch("maple.utils.download.auth_headers", return_value={}):
+            download_repo("org/model", temp_dir, revision="abc123", hub=hub)
+
+        assert [u.split("/")[2] for u in urls] == ["huggingface.co", "mirror.internal"]
+
+    @pytest.mark.unit
+    def test_all_mirrors_fail(self, temp_dir):
+        """Test that the primary's error is raised when every endpoint fails."""
+        hub = Hub(mirrors=["https://mirror.internal"])
+        errors = [requests.ConnectionError("primary down"), requests.ConnectionError("mirror down")]
+
+        with patch("maple.utils.download.requests.get", side_effect=errors), \
+             patch("maple.utils.download.auth_headers", return_value={}):
+            with pytest.raises(requests.ConnectionError, match="primary down"):
+                list_repo_files("org/model", hub=hub)
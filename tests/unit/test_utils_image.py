@@ -0,0 +1,115 @@
+"""
+Unit tests for maple.utils.image module.
+
+Tests cover:
+- Center-cropping to the target aspect ratio before resizing
+- Accepting PNG and JPEG, rejecting other formats and bad base64
+- Base64 PNG output at the policy input size
+- Reading the input size from policy metadata
+"""
+
+import io
+import base64
+
+import pytest
+from PIL import Image
+
+from maple.utils.image import (
+    ImageError,
+    center_crop_resize,
+    decode_base64_image,
+    load_image_file,
+    policy_image_size,
+    preprocess,
+)
+
+
+def encoded(img, fmt):
+    """Encode an image in the given format."""
+    buf = io.BytesIO()
+    img.save(buf, format=fmt)
+    return buf.getvalue()
+
+
+class TestCenterCropResize:
+    """Tests for center_crop_resize."""
+
+    @pytest.mark.unit
+    def test_crops_wide_image_to_center(self):
+        """Test a wide image keeps its center column band and is not stretched."""
+        img = Image.new("RGB", (300, 100), color=(0, 0, 0))
+        img.paste((255, 0, 0), (100, 0, 200, 100))  # Red square in the middle
+
+        out = center_crop_resize(img, (50, 50))
+
+        assert out.size == (50, 50)
+        assert out.getpixel((0, 0)) == (255, 0, 0)
+        assert out.getpixel((49, 49)) == (255, 0, 0)
+
+    @pytest.mark.unit
+    def test_same_size_unchanged(self):
+        """Test an image already at the target size is returned as-is."""
+        img = Image.new("RGB", (224, 224))
+        assert center_crop_resize(img, (224, 224)) is img
+
+
+class TestDecode:
+    """Tests for image decoding."""
+
+    @pytest.mark.unit
+    @pytest.mark.parametrize("fmt", ["PNG", "JPEG"])
+    def test_supported_formats(self, temp_dir, fmt):
+        """Test PNG and JPEG files load as RGB."""
+        path = temp_dir / f"photo.{fmt.lower()}"
+        path.write_bytes(encoded(Image.new("L", (8, 8)), fmt))
+
+        img = load_image_file(path)
+
+        assert img.mode == "RGB"
+        assert img.size == (8, 8)
+
+    @pytest.mark.unit
+    def test_unsupported_format(self, temp_dir):
+        """Test a decodable but unsupported format is rejected."""
+        path = temp_dir / "photo.gif"
+        path.write_bytes(encoded(Image.new("RGB", (8, 8)), "GIF"))
+
+        with pytest.raises(ImageError, match="Unsupported format GIF"):
+            load_image_file(path)
+
+    @pytest.mark.unit
+    def test_bad_base64(self):
+        """Test strings that are not base64 or not images are rejected."""
+        with pytest.raises(ImageError):
+            decode_base64_image("not base64!")
+        with pytest.raises(ImageError):
+            decode_base64_image(base64.b64encode(b"plain text").decode())
+
+
+class TestPreprocess:
+    """Tests for preprocess."""
+
+    @pytest.mark.unit
+    def test_output_is_png_at_size(self):
+        """Test the payload is a base64 PNG at the requested size."""
+        out = preprocess(Image.new("RGB", (640, 480)), (224, 224))
+
+        img = Image.open(io.BytesIO(base64.b64decode(out)))
+        assert img.format == "PNG"
+        assert img.size == (224, 224)
+
+
+class TestPolicyImageSize:
+    """Tests for policy_image_size."""
+
+    @pytest.mark.unit
+    @pytest.mark.parametrize("size, expected", [
+        (224, (224, 224)),
+        ([64, 48], (64, 48)),
+        ([256, 256, 3], (256, 256)),
+        (None, None),
+        ("224", None),
+    ])
+    def test_sizes(self, size, expected):
+        """Test square, (width, height) and missing sizes."""
+        assert policy_image_size({"image_size": size}) == expected
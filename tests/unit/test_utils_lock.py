@@ -0,0 +1,161 @@
+"""
+Unit tests for maple.utils.lock module.
+
+Tests cover:
+- Per-ref lock file paths
+- Serialization of writers holding the same ref lock
+- Daemon PID file ownership and stale file reclaim
+- Daemon socket lock per MAPLE home
+- Stopping a process with SIGTERM and falling back to SIGKILL
+"""
+
+import os
+import sys
+import json
+import threading
+import subprocess
+
+import pytest
+
+from maple.utils.lock import lock_ref, ref_lock_path, PidFile, pid_file, read_pid_file, running_daemon, stop_process
+from maple.utils.lock import DaemonLock, get_socket_path, is_daemon_running
+from maple.utils.paths import maple_home_at
+
+
+class TestRefLock:
+    """Tests for lock_ref."""
+
+    @pytest.mark.unit
+    def test_lock_path_per_ref(self, maple_home):
+        """Test that each name:version gets its own lock file."""
+        assert ref_lock_path("openvla", "7b") == maple_home / "locks" / "openvla-7b.lock"
+        assert ref_lock_path("openvla", "7b") != ref_lock_path("openvla", "latest")
+
+    @pytest.mark.unit
+    def test_same_ref_serializes(self, maple_home):
+        """Test that a second writer waits until the first releases the lock."""
+        events = []
+
+        def second():
+            with lock_ref("openvla", "7b"):
+                events.append("second")
+
+        with lock_ref("openvla", "7b"):
+            t = threading.Thread(target=second)
+            t.start()
+            t.join(timeout=0.2)
+            # Still blocked while the first holder is inside the block
+            assert t.is_alive()
+            events.append("first")
+        t.join(timeout=5)
+
+        assert events == ["first", "second"]
+
+    @pytest.mark.unit
+    def test_released_on_error(self, maple_home):
+        """Test that the lock is released when the block raises."""
+        with pytest.raises(RuntimeError):
+            with lock_ref("openvla", "7b"):
+                raise RuntimeError("pull failed")
+
+        # Would block forever if the lock were still held
+        with lock_ref("openvla", "7b"):
+            pass
+
+
+class TestPidFile:
+    """Tests for PidFile and running_daemon."""
+
+    @pytest.mark.unit
+    def test_acquire_records_daemon(self, maple_home):
+        """Test the PID file records this process and is removed on release."""
+        pid = PidFile()
+        assert pid.acquire(port=8000)
+        try:
+            assert pid_file() == maple_home / "maple.pid"
+            assert read_pid_file() == {"pid": os.getpid(), "port": 8000}
+            assert running_daemon()["port"] == 8000
+        finally:
+            pid.release()
+
+        assert not pid_file().exists()
+        assert running_daemon() is None
+
+    @pytest.mark.unit
+    def test_second_daemon_refused(self, maple_home):
+        """Test a second holder is refused while the first is running."""
+        first = PidFile()
+        assert first.acquire(port=8000)
+        try:
+            assert not PidFile().acquire(port=8001)
+            assert read_pid_file()["port"] == 8000
+        finally:
+            first.release()
+
+    @pytest.mark.unit
+    def test_stale_file_reclaimed(self, maple_home):
+        """Test a PID file left behind by a crashed daemon is not live and is reclaimed."""
+        pid_file().write_text(json.dumps({"pid": 999999999, "port": 8000}))
+
+        assert running_daemon() is None
+
+        pid = PidFile()
+        assert pid.acquire(port=9000)
+        try:
+            assert read_pid_file()["pid"] == os.getpid()
+        finally:
+            pid.release()
+
+
+class TestDaemonLock:
+    """Tests for the daemon socket lock."""
+
+    @pytest.mark.unit
+    def test_separate_roots_do_not_conflict(self, maple_home):
+        """Test a daemon for one MAPLE home does not block one for another."""
+        other = maple_home / "other"
+        lock = DaemonLock()
+        assert lock.acquire()
+        try:
+            assert is_daemon_running()
+            with maple_home_at(other):
+                assert get_socket_path() != lock.socket_path
+                assert not is_daemon_running()
+                second = DaemonLock()
+                assert second.acquire()
+                second.release()
+            assert not DaemonLock().acquire()
+        finally:
+            lock.release()
+
+        assert not is_daemon_running()
+
+
+def _spawn(code):
+    """Start a Python child that prints 'ready' once set up, reaped in the background."""
+    proc = subprocess.Popen([sys.executable, "-c", code], stdout=subprocess.PIPE, text=True)
+    assert proc.stdout.readline().strip() == "ready"
+    # Reap the child so it doesn't linger as a zombie that still looks alive
+    threading.Thread(target=proc.wait, daemon=True).start()
+    return proc
+
+
+class TestStopProcess:
+    """Tests for stop_process."""
+
+    @pytest.mark.unit
+    def test_exits_on_sigterm(self):
+        """Test a process that honors SIGTERM counts as a clean stop."""
+        proc = _spawn("import time; print('ready', flush=True); time.sleep(30)")
+
+        assert stop_process(proc.pid, timeout=5, poll=0.05) is True
+
+    @pytest.mark.unit
+    def test_kills_after_timeout(self):
+        """Test a process ignoring SIGTERM is killed once the timeout passes."""
+        proc = _spawn(
+            "import signal, time; signal.signal(signal.SIGTERM, signal.SIG_IGN); "
+            "print('ready', flush=True); time.sleep(30)"
+        )
+
+        assert stop_process(proc.pid, timeout=0.3, poll=0.05) is False
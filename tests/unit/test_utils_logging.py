@@ -0,0 +1,106 @@
+"""
+Unit tests for maple.utils.logging module.
+
+Tests cover:
+- Size-based rotation with compressed backups
+- Backup count and age limits
+- JSON log formatting
+"""
+
+import os
+import gzip
+import json
+import time
+import logging
+
+import pytest
+
+from maple.utils.logging import RotatingLogHandler, JsonFormatter, make_formatter
+
+
+def _write(handler, message):
+    handler.emit(logging.LogRecord("maple.test", logging.INFO, __file__, 0, message, None, None))
+
+
+class TestRotatingLogHandler:
+    """Tests for RotatingLogHandler."""
+
+    @pytest.mark.unit
+    def test_rotates_and_compresses(self, temp_dir):
+        """Test a full file is gzipped to .1.gz and a fresh file started."""
+        path = temp_dir / "logs" / "server.log"
+        handler = RotatingLogHandler(path, max_bytes=20, backup_count=3)
+        try:
+            _write(handler, "first record here")
+            _write(handler, "second record")
+        finally:
+            handler.close()
+
+        assert path.read_text() == "second record\n"
+        with gzip.open(temp_dir / "logs" / "server.log.1.gz", "rt") as f:
+            assert f.read() == "first record here\n"
+
+    @pytest.mark.unit
+    def test_keeps_backup_count(self, temp_dir):
+        """Test only the newest backups are kept."""
+        path = temp_dir / "server.log"
+        handler = RotatingLogHandler(path, max_bytes=10, backup_count=2)
+        try:
+            for i in range(5):
+                _write(handler, f"record {i:03d}")
+        finally:
+            handler.close()
+
+        assert [p.name for p in handler.backups()] == ["server.log.1.gz", "server.log.2.gz"]
+
+    @pytest.mark.unit
+    def test_removes_expired_backups(self, temp_dir):
+        """Test backups past max_age are deleted and newer ones kept."""
+        path = temp_dir / "server.log"
+        old = temp_dir / "server.log.2.gz"
+        new = temp_dir / "server.log.1.gz"
+        old.write_bytes(b"")
+        new.write_bytes(b"")
+        week_ago = time.time() - 7 * 86400
+        os.utime(old, (week_ago, week_ago))
+
+        handler = RotatingLogHandler(path, max_bytes=0, backup_count=3, max_age=86400)
+        handler.close()
+
+        assert not old.exists()
+        assert new.exists()
+
+
+class TestJsonFormatter:
+    """Tests for JsonFormatter."""
+
+    @pytest.mark.unit
+    def test_formats_access_fields(self):
+        """Test a record becomes one JSON object with its extra fields."""
+        record = logging.LogRecord("maple.access", logging.INFO, __file__, 0, "GET /ps 200", None, None)
+        record.created = 0
+        record.method, record.path, record.status = "GET", "/ps", 200
+        record.duration_ms, record.remote_addr = 1.5, "127.0.0.1"
+
+        line = JsonFormatter().format(record)
+
+        assert "\n" not in line
+        assert json.loads(line) == {
+            "ts": "1970-01-01T00:00:00.000+00:00",
+            "level": "INFO",
+            "logger": "maple.access",
+            "msg": "GET /ps 200",
+            "method": "GET",
+            "path": "/ps",
+            "status": 200,
+            "duration_ms": 1.5,
+            "remote_addr": "127.0.0.1",
+        }
+
+    @pytest.mark.unit
+    def test_make_formatter(self):
+        """Test text is the default and unknown formats are rejected."""
+        assert isinstance(make_formatter("json"), JsonFormatter)
+        assert not isinstance(make_formatter(), JsonFormatter)
+        with pytest.raises(ValueError):
+            make_formatter("xml")
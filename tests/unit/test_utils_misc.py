@@ -0,0 +1,118 @@
+"""
+Unit tests for maple.utils.misc module.
+
+Tests cover:
+- Duration parsing for keep-alive values
+- Relative time formatting
+- Parameter count parsing and VRAM estimates
+- Size parsing
+"""
+
+import pytest
+
+from maple.utils.misc import parse_duration, format_ago, parse_param_size, estimate_vram, parse_size
+
+
+class TestParseDuration:
+    """Tests for parse_duration."""
+
+    @pytest.mark.unit
+    def test_plain_seconds(self):
+        """Test that bare numbers are treated as seconds."""
+        assert parse_duration("30") == 30.0
+        assert parse_duration(45) == 45.0
+        assert parse_duration("0") == 0.0
+
+    @pytest.mark.unit
+    def test_unit_suffixes(self):
+        """Test single unit-suffixed durations."""
+        assert parse_duration("500ms") == 0.5
+        assert parse_duration("30s") == 30.0
+        assert parse_duration("5m") == 300.0
+        assert parse_duration("1h") == 3600.0
+        assert parse_duration("7d") == 604800.0
+
+    @pytest.mark.unit
+    def test_compound_duration(self):
+        """Test combined durations like 1h30m."""
+        assert parse_duration("1h30m") == 5400.0
+        assert parse_duration("2m30s") == 150.0
+
+    @pytest.mark.unit
+    def test_negative_means_forever(self):
+        """Test that negative durations return None (never expire)."""
+        assert parse_duration("-1") is None
+        assert parse_duration("-5m") is None
+        assert parse_duration(-1) is None
+
+    @pytest.mark.unit
+    def test_none_passthrough(self):
+        """Test that None is returned unchanged."""
+        assert parse_duration(None) is None
+
+    @pytest.mark.unit
+    @pytest.mark.parametrize("value", ["", "abc", "5x", "m5", "5m garbage"])
+    def test_invalid_duration(self, value):
+        """Test that malformed durations raise ValueError."""
+        with pytest.raises(ValueError):
+            parse_duration(value)
+
+
+class TestFormatAgo:
+    """Tests for format_ago."""
+
+    @pytest.mark.unit
+    def test_units(self):
+        """Test the largest whole unit is used."""
+        now = 1_000_000_000.0
+        assert format_ago(now - 10, now=now) == "Just now"
+        assert format_ago(now - 60, now=now) == "1 minute ago"
+        assert format_ago(now - 3 * 3600, now=now) == "3 hours ago"
+        assert format_ago(now - 2 * 86400, now=now) == "2 days ago"
+        assert format_ago(now - 14 * 86400, now=now) == "2 weeks ago"
+        assert format_ago(now - 90 * 86400, now=now) == "3 months ago"
+
+    @pytest.mark.unit
+    def test_unknown(self):
+        """Test missing timestamps render as a dash."""
+        assert format_ago(None) == "-"
+
+
+class TestVramEstimate:
+    """Tests for parse_param_size and estimate_vram."""
+
+    @pytest.mark.unit
+    def test_parse_param_size(self):
+        """Test parameter counts with unit suffixes."""
+        assert parse_param_size("7B") == 7_000_000_000
+        assert parse_param_size("450M") == 450_000_000
+        assert parse_param_size("1.5b") == 1_500_000_000
+        assert parse_param_size("") is None
+        assert parse_param_size(None) is None
+        assert parse_param_size("large") is None
+
+    @pytest.mark.unit
+    def test_estimate_vram(self):
+        """Test half-precision weights plus overhead."""
+        assert estimate_vram("7B") == int(7e9 * 2 * 1.2)
+        assert estimate_vram("7B", bytes_per_param=1, overhead=1.0) == 7_000_000_000
+        assert estimate_vram(None) is None
+
+
+class TestParseSize:
+    """Tests for parse_size."""
+
+    @pytest.mark.unit
+    def test_units_are_binary(self):
+        """Test sizes use the same 1024-based units as format_size."""
+        assert parse_size("10MB") == 10 * 1024 ** 2
+        assert parse_size("1.5g") == int(1.5 * 1024 ** 3)
+        assert parse_size("512") == 512
+        assert parse_size(0) == 0
+
+    @pytest.mark.unit
+    @pytest.mark.parametrize("value", ["", "MB", "10XB", "-1MB"])
+    def test_invalid_size(self, value):
+        """Test that malformed sizes raise ValueError."""
+        with pytest.raises(ValueError):
+            parse_size(value)
@@ -0,0 +1,103 @@
+"""
+Unit tests for maple.utils.modelfile module.
+
+Tests cover:
+- Parsing instructions, comments and errors
+- Creating a policy from a pulled base
+- Leaving the base policy's files untouched
+- Refusing to replace an existing policy without overwrite
+"""
+
+import json
+import pytest
+from pathlib import Path
+
+from maple.utils.modelfile import parse_modelfile, create_policy, Modelfile, PolicyExistsError
+
+
+class TestParseModelfile:
+    """Tests for parse_modelfile."""
+
+    @pytest.mark.unit
+    def test_parse(self, temp_dir):
+        """Test that every instruction is parsed and relative paths are resolved."""
+        text = """
+        # Fine-tuned on kitchen demos
+        FROM openvla:7b
+        weights ./ckpt
+        WEIGHTS /abs/stats.json
+        CONFIG action_dim 7
+        CONFIG unnorm_key kitchen_v2
+        LABEL dataset = kitchen-v2
+        """
+
+        modelfile = parse_modelfile(text, base_dir=temp_dir)
+
+        assert modelfile.base == "openvla:7b"
+        assert modelfile.weights == [temp_dir / "ckpt", Path("/abs/stats.json")]
+        assert modelfile.config == {"action_dim": 7, "unnorm_key": "kitchen_v2"}
+        assert modelfile.labels == {"dataset": "kitchen-v2"}
+
+    @pytest.mark.unit
+    def test_unknown_instruction(self):
+        """Test that unknown instructions are reported with their line number."""
+        with pytest.raises(ValueError, match="Line 2: unknown instruction 'ADAPTER'"):
+            parse_modelfile("FROM openvla:7b\nADAPTER ./lora")
+
+    @pytest.mark.unit
+    def test_missing_from(self):
+        """Test that a Modelfile without FROM is rejected."""
+        with pytest.raises(ValueError, match="no FROM"):
+            parse_modelfile("LABEL a=b")
+
+
+class TestCreatePolicy:
+    """Tests for create_policy."""
+
+    @pytest.fixture
+    def base(self, pulled_policy):
+        """Register a pulled openvla:7b with a config and weights."""
+        path = pulled_policy("openvla", "7b", image="img:latest", repo="openvla/openvla-7b")
+        (path / "config.json").write_text(json.dumps({"action_dim": 7}))
+        (path / "model.safetensors").write_bytes(b"base")
+        return path
+
+    @pytest.mark.unit
+    def test_create(self, base, temp_dir):
+        """Test that weights override base files and config keys are merged."""
+        from maple.state import store
+
+        ckpt = temp_dir / "ckpt"
+        ckpt.mkdir()
+        (ckpt / "model.safetensors").write_bytes(b"tuned")
+        modelfile = Modelfile(base="openvla:7b", weights=[ckpt], config={"unnorm_key": "kitchen"}, labels={"run": "42"})
+
+        policy = create_policy("kitchen", modelfile)
+
+        assert (policy["name"], policy["version"]) == ("openvla", "kitchen")
+        assert policy["image"] == "img:latest"
+        assert policy["annotations"] == {"base": "openvla:7b", "run": "42"}
+        created = store.get_policy("openvla", "kitchen")["path"]
+        assert (base.parent / "kitchen" / "model.safetensors").read_bytes() == b"tuned"
+        assert json.loads((base.parent / "kitchen" / "config.json").read_text()) == {"action_dim": 7, "unnorm_key": "kitchen"}
+        assert created == str(base.parent / "kitchen")
+
+        # The base policy is unchanged
+        assert (base / "model.safetensors").read_bytes() == b"base"
+        assert json.loads((base / "config.json").read_text()) == {"action_dim": 7}
+
+    @pytest.mark.unit
+    def test_existing_needs_overwrite(self, base):
+        """Test that an existing policy is only replaced with overwrite."""
+        modelfile = Modelfile(base="openvla:7b")
+        create_policy("openvla:copy", modelfile)
+
+        with pytest.raises(PolicyExistsError):
+            create_policy("openvla:copy", modelfile)
+        create_policy("openvla:copy", modelfile, overwrite=True)
+
+    @pytest.mark.unit
+    def test_base_must_be_pulled(self, test_db, maple_home):
+        """Test that creating from a policy that is not pulled fails."""
+        with pytest.raises(ValueError, match="not pulled"):
+            create_policy("mine", Modelfile(base="openvla:7b"))
@@ -0,0 +1,113 @@
+"""
+Unit tests for maple.utils.paths module.
+
+Tests cover:
+- Default and relocated MAPLE home directory
+- MAPLE_HOME and override precedence
+- Separate models directory
+- Paths of other modules following the relocated home
+- Directory and policy sizes
+- Sizes shared through hard links counted once
+"""
+
+import os
+import pytest
+from pathlib import Path
+
+from maple.utils.paths import maple_home, set_maple_home, models_dir, policy_dir, dir_size, policy_size, unique_size
+
+
+class TestMapleHome:
+    """Tests for maple_home and set_maple_home."""
+
+    @pytest.mark.unit
+    def test_default(self, monkeypatch):
+        """Test the home directory defaults to ~/.maple."""
+        monkeypatch.delenv("MAPLE_HOME", raising=False)
+        assert maple_home() == Path.home() / ".maple"
+
+    @pytest.mark.unit
+    def test_env_and_override_precedence(self, temp_dir, monkeypatch):
+        """Test set_maple_home beats $MAPLE_HOME, which beats the default."""
+        monkeypatch.setenv("MAPLE_HOME", str(temp_dir / "env"))
+        assert maple_home() == temp_dir / "env"
+
+        set_maple_home(temp_dir / "flag")
+        try:
+            assert maple_home() == temp_dir / "flag"
+        finally:
+            set_maple_home(None)
+        assert maple_home() == temp_dir / "env"
+
+    @pytest.mark.unit
+    def test_relocated(self, maple_home):
+        """Test every local path follows a relocated home."""
+        from maple.state.store import db_file
+        from maple.utils.auth import auth_file
+        from maple.utils.config import config_file
+
+        assert policy_dir("openvla", "7b") == maple_home / "models" / "openvla" / "7b"
+        assert db_file() == maple_home / "state.db"
+        assert auth_file() == maple_home / "auth.json"
+        assert config_file() == maple_home / "config.yaml"
+
+    @pytest.mark.unit
+    def test_restore_default(self, temp_dir, monkeypatch):
+        """Test passing None restores the default home."""
+        monkeypatch.delenv("MAPLE_HOME", raising=False)
+        set_maple_home(temp_dir)
+        set_maple_home(None)
+
+        assert maple_home() == Path.home() / ".maple"
+
+    @pytest.mark.unit
+    def test_models_dir_override(self, maple_home, temp_dir, monkeypatch):
+        """Test $MAPLE_MODELS_DIR moves weights but nothing else."""
+        from maple.state.store import db_file
+
+        monkeypatch.delenv("MAPLE_MODELS_DIR", raising=False)
+        assert models_dir() == maple_home / "models"
+
+        monkeypatch.setenv("MAPLE_MODELS_DIR", str(temp_dir / "shared"))
+        assert policy_dir("openvla", "7b") == temp_dir / "shared" / "openvla" / "7b"
+        assert db_file() == maple_home / "state.db"
+
+
+class TestSizes:
+    """Tests for dir_size, policy_size and unique_size."""
+
+    @pytest.mark.unit
+    def test_dir_size(self, temp_dir):
+        """Test sizes are summed recursively, with partial downloads optional."""
+        (temp_dir / "sub").mkdir()
+        (temp_dir / "config.json").write_bytes(b"x" * 10)
+        (temp_dir / "sub" / "model.bin").write_bytes(b"x" * 100)
+        (temp_dir / "sub" / "shard.bin.part").write_bytes(b"x" * 5)
+
+        assert dir_size(temp_dir) == 115
+        assert dir_size(temp_dir, include_partial=False) == 110
+        assert dir_size(temp_dir / "missing") == 0
+
+    @pytest.mark.unit
+    def test_policy_size(self, maple_home):
+        """Test a policy's size counts its complete files only."""
+        weights = policy_dir("openvla", "7b")
+        weights.mkdir(parents=True)
+        (weights / "model.safetensors").write_bytes(b"x" * 64)
+        (weights / "config.json.part").write_bytes(b"x" * 8)
+
+        assert policy_size("openvla", "7b") == 64
+        assert policy_size("openvla", "latest") == 0
+
+    @pytest.mark.unit
+    def test_unique_size_counts_links_once(self, temp_dir):
+        """Test a file hard-linked into two directories is counted once."""
+        base, derived = temp_dir / "base", temp_dir / "derived"
+        base.mkdir()
+        derived.mkdir()
+        (base / "model.safetensors").write_bytes(b"x" * 64)
+        os.link(base / "model.safetensors", derived / "model.safetensors")
+        (derived / "adapter.bin").write_bytes(b"x" * 8)
+
+        assert unique_size([base, derived, temp_dir / "missing"]) == 72
+        assert dir_size(base) + dir_size(derived) == 136
@@ -0,0 +1,57 @@
+"""
+Unit tests for maple.utils.progress module.
+
+Tests cover:
+- Smoothed transfer rate estimation
+- ETA calculation and formatting
+"""
+
+import pytest
+
+from maple.utils.progress import TransferRate, format_eta
+
+
+class TestTransferRate:
+    """Tests for TransferRate."""
+
+    @pytest.mark.unit
+    def test_first_sample_sets_rate(self):
+        """Test that the first interval initializes the rate directly."""
+        rate = TransferRate(alpha=0.5)
+        rate.update(0, now=0.0)
+
+        assert rate.update(100, now=1.0) == 100.0
+
+    @pytest.mark.unit
+    def test_rate_is_smoothed(self):
+        """Test that a spike is blended with the previous rate."""
+        rate = TransferRate(alpha=0.5)
+        rate.update(0, now=0.0)
+        rate.update(100, now=1.0)
+
+        # Instantaneous 300 B/s blended with 100 B/s
+        assert rate.update(400, now=2.0) == 200.0
+
+    @pytest.mark.unit
+    def test_eta(self):
+        """Test ETA from the smoothed rate."""
+        rate = TransferRate()
+        assert rate.eta(100) is None
+
+        rate.update(0, now=0.0)
+        rate.update(50, now=1.0)
+
+        assert rate.eta(100) == 2.0
+        assert rate.eta(0) == 0.0
+
+
+class TestFormatEta:
+    """Tests for format_eta."""
+
+    @pytest.mark.unit
+    def test_format(self):
+        """Test ETA formatting at each scale."""
+        assert format_eta(None) == "--"
+        assert format_eta(42) == "42s"
+        assert format_eta(195) == "3m15s"
+        assert format_eta(3720) == "1h02m"
@@ -0,0 +1,60 @@
+"""
+Unit tests for maple.utils.record module.
+
+Tests cover:
+- Writing headers, steps and results as JSON lines
+- Image references relative to the trajectory file
+- Reading entries back and rejecting foreign files
+"""
+
+import json
+
+import pytest
+
+from maple.utils.record import Recorder, read_record, frames_dir
+
+
+class TestRecorder:
+    """Tests for Recorder and read_record."""
+
+    @pytest.mark.unit
+    def test_round_trip(self, temp_dir):
+        """Test a recorded run reads back in order with absolute image paths."""
+        record = temp_dir / "runs" / "run.jsonl"
+        frame = frames_dir(record) / "run-1" / "step_00000.png"
+
+        with Recorder(record) as recorder:
+            recorder.header(policy="openvla:7b", digest="sha256:00ff")
+            recorder.step(0, 1.5, "pick up the block", str(frame), [0.1, 1.0])
+            recorder.result({"run_id": "run-1", "success": True})
+
+        lines = [json.loads(line) for line in record.read_text().splitlines()]
+        header, step, result = read_record(record)
+
+        assert lines[1]["image"] == "run.frames/run-1/step_00000.png"
+        assert (header["type"], header["policy"], header["version"]) == ("header", "openvla:7b", 1)
+        assert step["image"] == str(frame.resolve())
+        assert (step["step"], step["instruction"], step["action"]) == (0, "pick up the block", [0.1, 1.0])
+        assert result == {"type": "result", "run_id": "run-1", "success": True}
+
+    @pytest.mark.unit
+    def test_appends_runs(self, temp_dir):
+        """Test recording again appends a second run instead of overwriting."""
+        record = temp_dir / "run.jsonl"
+        for run_id in ("run-1", "run-2"):
+            with Recorder(record) as recorder:
+                recorder.header(policy="openvla:7b")
+                recorder.result({"run_id": run_id})
+
+        assert [e["type"] for e in read_record(record)] == ["header", "result", "header", "result"]
+
+    @pytest.mark.unit
+    def test_rejects_foreign_lines(self, temp_dir):
+        """Test files that are not trajectories are reported with the line number."""
+        record = temp_dir / "events.jsonl"
+        record.write_text('{"step": 0}\n')
+
+        with pytest.raises(ValueError) as exc:
+            list(read_record(record))
+
+        assert "events.jsonl:1" in str(exc.value)
@@ -0,0 +1,60 @@
+"""
+Unit tests for maple.utils.ui module.
+
+Tests cover:
+- Turning quiet mode on and off through the environment
+- Suppressing informational output when quiet
+- Turning color off for the global console and child processes
+"""
+
+import os
+import pytest
+from rich import get_console
+
+from maple.utils.ui import QUIET_ENV, NO_COLOR_ENV, set_quiet, set_color, is_quiet, info, progress_console
+
+
+class TestQuiet:
+    """Tests for quiet mode."""
+
+    @pytest.mark.unit
+    def test_set_quiet_exports_env(self, monkeypatch):
+        """Test quiet mode is carried in the environment for child processes."""
+        monkeypatch.delenv(QUIET_ENV, raising=False)
+        assert not is_quiet()
+
+        set_quiet(True)
+        assert is_quiet()
+        assert progress_console().quiet
+
+        set_quiet(False)
+        assert not is_quiet()
+
+    @pytest.mark.unit
+    def test_info_suppressed(self, monkeypatch, capsys):
+        """Test info prints normally and nothing when quiet."""
+        monkeypatch.setenv(QUIET_ENV, "0")
+        info("hint")
+        assert "hint" in capsys.readouterr().out
+
+        monkeypatch.setenv(QUIET_ENV, "1")
+        info("hint")
+        assert capsys.readouterr().out == ""
+
+
+class TestColor:
+    """Tests for color settings."""
+
+    @pytest.mark.unit
+    def test_set_color_updates_global_console(self, monkeypatch):
+        """Test --no-color exports NO_COLOR and reaches consoles fetched earlier."""
+        monkeypatch.delenv(NO_COLOR_ENV, raising=False)
+        console = get_console()
+
+        set_color(False)
+        assert os.environ[NO_COLOR_ENV] == "1"
+        assert console.no_color
+
+        set_color(True)
+        assert NO_COLOR_ENV not in os.environ
+        assert not console.no_color
@@ -0,0 +1,117 @@
+"""
+Unit tests for maple.utils.upload module.
+
+Tests cover:
+- Git blob IDs matching the registry's
+- Skipping files the repo already has, by LFS SHA-256 for LFS files
+- Committing only changed files in one commit
+- Restoring huggingface_hub's HTTP session afterwards
+"""
+
+import sys
+import pytest
+import hashlib
+from unittest.mock import MagicMock, patch
+
+from maple.utils.upload import git_blob_id, plan_upload, upload_repo
+
+
+def listing(siblings, status_code=200):
+    """Build a fake repo metadata response."""
+    r = MagicMock(status_code=status_code)
+    r.json.return_value = {"siblings": siblings}
+    return r
+
+
+class TestPlanUpload:
+    """Tests for comparing local files with the repo."""
+
+    @pytest.mark.unit
+    def test_git_blob_id(self, temp_dir):
+        """Test that blob IDs match git's, as reported by the registry."""
+        (temp_dir / "empty").write_bytes(b"")
+        (temp_dir / "hello").write_bytes(b"hello\n")
+
+        assert git_blob_id(temp_dir / "empty") == "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"
+        assert git_blob_id(temp_dir / "hello") == "ce013625030ba8dba906f756967f9e9ca394464a"
+
+    @pytest.mark.unit
+    def test_marks_files_already_uploaded(self, temp_dir):
+        """Test that only files with a matching blob ID or LFS digest count as uploaded."""
+        (temp_dir / "config.json").write_bytes(b"hello\n")
+        (temp_dir / "model.safetensors").write_bytes(b"weights")
+        (temp_dir / "model.safetensors.part").write_bytes(b"partial")
+        (temp_dir / "optimizer.pt").write_bytes(b"changed")
+        (temp_dir / "tokenizer.json").write_bytes(b"{}")
+        # LFS files report the blob ID of their pointer, never of their contents
+        remote = [
+            {"rfilename": "config.json", "size": 6, "blobId": "ce013625030ba8dba906f756967f9e9ca394464a"},
+            {"rfilename": "model.safetensors", "size": 7, "blobId": "0" * 40,
+             "lfs": {"sha256": hashlib.sha256(b"weights").hexdigest(), "size": 7}},
+            {"rfilename": "optimizer.pt", "size": 7, "blobId": "1" * 40,
+             "lfs": {"sha256": hashlib.sha256(b"earlier").hexdigest(), "size": 7}},
+        ]
+
+        with patch("maple.utils.upload.requests.get", return_value=listing(remote)):
+            plan = plan_upload(temp_dir, "myorg/model", token="hf_x")
+
+        assert [(name, uploaded) for name, _, uploaded in plan] == [
+            ("config.json", True),
+            ("model.safetensors", True),
+            ("optimizer.pt", False),
+            ("tokenizer.json", False),
+        ]
+
+    @pytest.mark.unit
+    def test_new_repo_uploads_everything(self, temp_dir):
+        """Test that a repo that does not exist yet has no files."""
+        (temp_dir / "config.json").write_bytes(b"{}")
+
+        with patch("maple.utils.upload.requests.get", return_value=listing([], status_code=404)):
+            plan = plan_upload(temp_dir, "myorg/model")
+
+        assert [(name, uploaded) for name, _, uploaded in plan] == [("config.json", False)]
+
+
+class TestUploadRepo:
+    """Tests for uploading a policy directory."""
+
+    @pytest.mark.unit
+    def test_commits_changed_files(self, temp_dir):
+        """Test that unchanged files are skipped and the rest go in one commit."""
+        (temp_dir / "config.json").write_bytes(b"hello\n")
+        (temp_dir / "model.safetensors").write_bytes(b"weights")
+        remote = [{"rfilename": "config.json", "size": 6, "blobId": "ce013625030ba8dba906f756967f9e9ca394464a"}]
+
+        hf = MagicMock()
+        hf.CommitOperationAdd.side_effect = lambda path_in_repo, path_or_fileobj: MagicMock(path_in_repo=path_in_repo)
+        api = hf.HfApi.return_value
+        api.create_commit.return_value = MagicMock(oid="abc1234def")
+        events = []
+
+        with patch.dict(sys.modules, {"huggingface_hub": hf}), \
+             patch("maple.utils.upload.requests.get", return_value=listing(remote)):
+            result = upload_repo(temp_dir, "myorg/model", "hf_x", progress=lambda *e: events.append(e))
+
+        assert result == {
+            "repo": "myorg/model",
+            "commit": "abc1234def",
+            "uploaded": ["model.safetensors"],
+            "skipped": ["config.json"],
+        }
+        assert api.preupload_lfs_files.call_count == 1
+        assert api.create_commit.call_count == 1
+        assert ("model.safetensors", 7, 7) in events
+
+    @pytest.mark.unit
+    def test_restores_http_backend(self, temp_dir):
+        """Test that huggingface_hub's session factory is reset even when the upload fails."""
+        hf = MagicMock()
+        hf.HfApi.return_value.create_repo.side_effect = RuntimeError("denied")
+
+        with patch.dict(sys.modules, {"huggingface_hub": hf}):
+            with pytest.raises(RuntimeError):
+                upload_repo(temp_dir, "myorg/model", "hf_x")
+
+        assert hf.configure_http_backend.call_count == 2
+        assert hf.configure_http_backend.call_args == ((), {})